@@ -1,7 +1,62 @@
 package application
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+)
 
 type UseCase[C any, R any] interface {
 	Execute(ctx context.Context, cmd C) (R, error)
 }
+
+// RetryOnConflict calls fn until it succeeds, returns an error that is not conflict,
+// the attempts are exhausted or ctx is done. fn receives the zero-based attempt number
+// and must reload any state it mutates, so each attempt re-validates against fresh data.
+func RetryOnConflict(ctx context.Context, attempts int, conflict error, fn func(attempt int) error) error {
+	var err error
+	for attempt := 0; attempt < max(attempts, 1); attempt++ {
+		if err = fn(attempt); !errors.Is(err, conflict) {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return errors.Join(err, ctxErr)
+		}
+	}
+	return err
+}
+
+// MapInput adapts uc to accept From, converting each command with fn; e.g. to drive a
+// command-based use case from an event subscription.
+func MapInput[From, C, R any](uc UseCase[C, R], fn func(From) C) UseCase[From, R] {
+	return mappedUseCase[From, C, R]{uc: uc, fn: fn}
+}
+
+type mappedUseCase[From, C, R any] struct {
+	uc UseCase[C, R]
+	fn func(From) C
+}
+
+func (m mappedUseCase[From, C, R]) Execute(ctx context.Context, cmd From) (R, error) {
+	return m.uc.Execute(ctx, m.fn(cmd))
+}
+
+// PublishOutcome labels a failed or successful event publish for external_requests_total
+// and the caller's status, telling a publish that ran out of time (timeout) apart from
+// one whose caller went away (canceled) or one shed by a full queue (backpressure).
+// status is empty on success.
+func PublishOutcome(err error) (outcome, status string) {
+	switch {
+	case err == nil:
+		return "success", ""
+	case errors.Is(err, domoutbox.ErrQueueFull):
+		return "backpressure", "EVENT_QUEUE_FULL"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout", "EVENT_PUBLISH_TIMEOUT"
+	case errors.Is(err, context.Canceled):
+		return "canceled", "EVENT_PUBLISH_CANCELED"
+	default:
+		return "error", "EVENT_PUBLISH_FAILED"
+	}
+}
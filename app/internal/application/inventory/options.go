@@ -0,0 +1,46 @@
+package inventory
+
+import dominv "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+
+// Option customises ReserveInventoryUseCase.
+type Option func(*ReserveInventoryUseCase)
+
+// WithLowStockThreshold emits InventoryLowStockEvent and counts inventory_low_stock_total
+// when a reservation leaves stock below the threshold. perProduct overrides global; only
+// products listed there get their own metric label, all others are reported as "other"
+// to keep cardinality bounded. A zero global threshold disables the check for unlisted products.
+func WithLowStockThreshold(global int, perProduct map[string]int) Option {
+	return func(uc *ReserveInventoryUseCase) {
+		uc.lowStockGlobal = global
+		uc.lowStockPerProduct = perProduct
+	}
+}
+
+// WithReservationTracking records each successful reservation in repo so a
+// ReservationSweeper can release it if the order never settles.
+func WithReservationTracking(repo dominv.ReservationRepository) Option {
+	return func(uc *ReserveInventoryUseCase) {
+		uc.reservations = repo
+	}
+}
+
+// WithAutoProvision makes a reservation against an unknown product create it with quantity
+// stock first instead of failing with not found. Meant for demos; production should 404.
+func WithAutoProvision(p dominv.Provisioner, quantity int) Option {
+	return func(uc *ReserveInventoryUseCase) {
+		uc.provisioner = p
+		uc.provisionQuantity = quantity
+	}
+}
+
+// WithRemainingStockGauge reports stock left after each reservation on the
+// inventory_remaining_stock gauge. Only the listed products are reported, which keeps
+// the product label bounded; others still get the inventory.remaining_after span attribute.
+func WithRemainingStockGauge(products ...string) Option {
+	return func(uc *ReserveInventoryUseCase) {
+		uc.remainingProducts = make(map[string]struct{}, len(products))
+		for _, p := range products {
+			uc.remainingProducts[p] = struct{}{}
+		}
+	}
+}
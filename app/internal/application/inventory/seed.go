@@ -0,0 +1,104 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
+	dominv "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const useCaseSeedInventory = "inventory.seed"
+
+type SeedInventoryInput struct {
+	Stock []dominv.Stock
+}
+
+type SeedInventoryResult struct {
+	Seeded   int // entries applied by this call
+	Products int // distinct products stocked afterwards
+}
+
+var _ application.UseCase[SeedInventoryInput, *SeedInventoryResult] = (*SeedInventoryUseCase)(nil)
+
+// SeedInventoryUseCase sets stock levels in bulk and reports the stocked product count
+// on the products_seeded gauge.
+type SeedInventoryUseCase struct {
+	seeder       dominv.Seeder
+	tracer       observability.Tracer
+	log          observability.Logger
+	reqCounter   observability.BoundCounter
+	durHistogram observability.BoundHistogram
+	products     observability.Gauge // products_seeded
+}
+
+func NewSeedInventoryUseCase(seeder dominv.Seeder, tel observability.Observability) *SeedInventoryUseCase {
+	metricsProvider := observability.MetricsOf(tel)
+	return &SeedInventoryUseCase{
+		seeder:       seeder,
+		tracer:       observability.TracerOf(tel),
+		log:          observability.LoggerOf(tel).With(observability.F(observability.FieldService, observability.ServiceInventory)),
+		reqCounter:   metricsProvider.Counter(observability.MUsecaseRequests).Bind(observability.UseCaseLabels(observability.ServiceInventory, useCaseSeedInventory)...),
+		durHistogram: metricsProvider.Histogram(observability.MUsecaseDuration).Bind(observability.UseCaseLabels(observability.ServiceInventory, useCaseSeedInventory)...),
+		products:     metricsProvider.Gauge(observability.MInventoryProductsSeeded),
+	}
+}
+
+func (uc *SeedInventoryUseCase) Execute(ctx context.Context, in SeedInventoryInput) (_ *SeedInventoryResult, err error) {
+	ctx, span := uc.tracer.Start(ctx, spanPrefix+"SeedInventory",
+		attribute.String("use_case", useCaseSeedInventory),
+		attribute.Int("inventory.seed_count", len(in.Stock)),
+	)
+	start := time.Now()
+	outcome := "success"
+	defer func() {
+		if err != nil {
+			outcome = "error"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "SEED_FAILED")
+		}
+		span.End()
+		uc.reqCounter.Add(1, observability.L("outcome", outcome))
+		uc.durHistogram.Observe(time.Since(start).Seconds())
+	}()
+
+	products, err := uc.seeder.SeedMany(ctx, in.Stock)
+	if err != nil {
+		return nil, fmt.Errorf("inventory: seed: %w", err)
+	}
+	uc.products.Set(float64(products))
+	logctx.FromOrWith(ctx, uc.log, observability.F(observability.FieldService, observability.ServiceInventory)).Info("inventory_seeded",
+		observability.F("seeded", len(in.Stock)),
+		observability.F("products", products),
+	)
+	return &SeedInventoryResult{Seeded: len(in.Stock), Products: products}, nil
+}
+
+// ReadSeedFile loads stock levels from a JSON array of {"product_id", "quantity"} objects,
+// the same shape POST /inventory:bulk accepts.
+func ReadSeedFile(path string) ([]dominv.Stock, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("inventory: read seed file: %w", err)
+	}
+	var entries []struct {
+		ProductID string `json:"product_id"`
+		Quantity  int    `json:"quantity"`
+	}
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("inventory: parse seed file %s: %w", path, err)
+	}
+	stock := make([]dominv.Stock, 0, len(entries))
+	for _, e := range entries {
+		stock = append(stock, dominv.Stock{ProductID: e.ProductID, Quantity: e.Quantity})
+	}
+	return stock, nil
+}
@@ -0,0 +1,128 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/domain/clock"
+	dominv "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	useCaseReservationSweep = "inventory.reservation_sweep"
+	endpointExpired         = "inventory.reservation_expired"
+	spanReservationSweep    = "ReservationSweep"
+	minSweepInterval        = time.Second
+)
+
+// SettledFunc reports whether an order has consumed its reserved stock (e.g. it was paid),
+// so its reservation must not be released.
+type SettledFunc func(ctx context.Context, orderID string) (bool, error)
+
+// ReservationSweeper releases reservations older than a TTL whose orders never settled,
+// returning their stock and emitting InventoryReservationExpiredEvent.
+type ReservationSweeper struct {
+	repo    dominv.ReservationRepository
+	settled SettledFunc
+	events  *application.EventPublisher
+	ttl     time.Duration
+	log     observability.Logger
+	tracer  observability.Tracer
+	expired observability.Counter // inventory_reservations_expired_total
+}
+
+func NewReservationSweeper(
+	repo dominv.ReservationRepository,
+	settled SettledFunc,
+	publisher domoutbox.Publisher,
+	ttl time.Duration,
+	tel observability.Observability,
+) *ReservationSweeper {
+	return &ReservationSweeper{
+		repo:    repo,
+		settled: settled,
+		events:  application.NewEventPublisher(publisher, publishTimeout, tel),
+		ttl:     ttl,
+		log:     observability.LoggerOf(tel).With(observability.F(observability.FieldService, observability.ServiceInventory)),
+		tracer:  observability.TracerOf(tel),
+		expired: observability.MetricsOf(tel).Counter(observability.MInventoryReservationsExpired),
+	}
+}
+
+// Run sweeps every half TTL (at least once a second) until ctx is done.
+func (s *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(max(s.ttl/2, minSweepInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = s.Sweep(ctx)
+		}
+	}
+}
+
+// Sweep handles every reservation older than the TTL once and returns how many it released.
+// Reservations of settled orders are forgotten without releasing stock.
+func (s *ReservationSweeper) Sweep(ctx context.Context) (released int, err error) {
+	ctx, span := s.tracer.Start(ctx, spanPrefix+spanReservationSweep)
+	logger := logctx.FromOrWith(ctx, s.log, observability.F(observability.FieldService, observability.ServiceInventory))
+	defer func() {
+		span.SetAttributes(attribute.Int("inventory.reservations_released", released))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "SWEEP_FAILED")
+		}
+		span.End()
+	}()
+
+	stale, err := s.repo.ReservedBefore(ctx, clock.Now().Add(-s.ttl))
+	if err != nil {
+		logger.Error("reservation_sweep_failed", observability.F("error", err.Error()))
+		return 0, err
+	}
+
+	var errs []error
+	for _, res := range stale {
+		resLogger := logger.With(
+			observability.F("order_id", res.OrderID),
+			observability.F("product_id", res.ProductID),
+		)
+		if ok, settleErr := s.settled(ctx, res.OrderID); settleErr != nil {
+			errs = append(errs, settleErr)
+			resLogger.Warn("reservation_settlement_check_failed", observability.F("error", settleErr.Error()))
+			continue
+		} else if ok {
+			if forgetErr := s.repo.Forget(ctx, res.OrderID); forgetErr != nil && !errors.Is(forgetErr, dominv.ErrReservationNotFound) {
+				errs = append(errs, forgetErr)
+			}
+			continue
+		}
+
+		freed, releaseErr := s.repo.Release(ctx, res.OrderID)
+		if errors.Is(releaseErr, dominv.ErrReservationNotFound) {
+			continue // settled or released concurrently
+		}
+		if releaseErr != nil {
+			errs = append(errs, releaseErr)
+			resLogger.Error("reservation_release_failed", observability.F("error", releaseErr.Error()))
+			continue
+		}
+		released++
+		s.expired.Add(1)
+		resLogger.Info("inventory_reservation_expired",
+			observability.F("quantity", freed.Quantity),
+			observability.F("reserved_at", freed.ReservedAt),
+		)
+		_ = s.events.Publish(ctx, resLogger, useCaseReservationSweep, endpointExpired, dominv.NewInventoryReservationExpiredEvent(freed))
+	}
+	return released, errors.Join(errs...)
+}
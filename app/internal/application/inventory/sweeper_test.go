@@ -0,0 +1,75 @@
+package inventory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appInventory "github.com/Zhima-Mochi/minishop-observability/app/internal/application/inventory"
+	dominv "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+)
+
+func TestSweepReleasesOnlyStaleUnsettledReservations(t *testing.T) {
+	const ttl = time.Minute
+	ctx := context.Background()
+	rec := obstest.New()
+	stock := memory.NewInventoryRepository()
+	stock.Seed("sku-1", 10)
+
+	stale := time.Now().UTC().Add(-2 * ttl)
+	for _, res := range []dominv.Reservation{
+		{OrderID: "o-abandoned", ProductID: "sku-1", Quantity: 2, ReservedAt: stale},
+		{OrderID: "o-settled", ProductID: "sku-1", Quantity: 3, ReservedAt: stale},
+		{OrderID: "o-recent", ProductID: "sku-1", Quantity: 1, ReservedAt: time.Now().UTC()},
+	} {
+		if err := stock.Track(ctx, res); err != nil {
+			t.Fatalf("track %s: %v", res.OrderID, err)
+		}
+	}
+	settled := func(_ context.Context, orderID string) (bool, error) {
+		return orderID == "o-settled", nil
+	}
+
+	publisher := &recordingPublisher{}
+	sweeper := appInventory.NewReservationSweeper(stock, settled, publisher, ttl, rec)
+
+	released, err := sweeper.Sweep(ctx)
+	if err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	if released != 1 {
+		t.Errorf("released = %d, want 1", released)
+	}
+	if left, _ := stock.Available(ctx, "sku-1"); left != 12 {
+		t.Errorf("available stock = %d, want 12 (only the abandoned 2 returned)", left)
+	}
+	if got := rec.Count(observability.MInventoryReservationsExpired); got != 1 {
+		t.Errorf("inventory_reservations_expired_total = %v, want 1", got)
+	}
+	var expired []dominv.InventoryReservationExpiredEvent
+	for _, e := range publisher.events {
+		if x, ok := e.(dominv.InventoryReservationExpiredEvent); ok {
+			expired = append(expired, x)
+		}
+	}
+	if len(expired) != 1 || expired[0].OrderID != "o-abandoned" {
+		t.Errorf("expired events = %+v, want one for o-abandoned", expired)
+	}
+
+	// The settled reservation is forgotten and the recent one is still tracked.
+	if _, err := stock.Get(ctx, "o-settled"); !errors.Is(err, dominv.ErrReservationNotFound) {
+		t.Errorf("settled reservation lookup error = %v, want %v", err, dominv.ErrReservationNotFound)
+	}
+	if _, err := stock.Get(ctx, "o-recent"); err != nil {
+		t.Errorf("recent reservation lookup error = %v, want it still tracked", err)
+	}
+
+	// A second sweep finds nothing left to expire.
+	if released, err := sweeper.Sweep(ctx); err != nil || released != 0 {
+		t.Errorf("second Sweep = %d, %v; want 0, nil", released, err)
+	}
+}
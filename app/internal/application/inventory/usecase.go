@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/domain/clock"
 	dominv "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
 	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
 	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
@@ -18,13 +20,13 @@ import (
 )
 
 const (
-	inventoryService            = "inventory-service"
 	useCaseInventoryReservation = "inventory.reserve"
 	inventorySpanName           = "OnOrderCreated"
 	spanPrefix                  = "UC."
-	publishPeer                 = "outbox"
 	endpointReserved            = "inventory.reserved"
 	endpointReservationFailed   = "inventory.reservation_failed"
+	endpointLowStock            = "inventory.low_stock"
+	lowStockOtherProduct        = "other"
 	publishTimeout              = 300 * time.Millisecond
 )
 
@@ -34,50 +36,66 @@ type ReservationResult struct {
 	FailureReason string
 }
 
+// LogFields reports the failure reason on the worker's use_case_done line.
+func (r *ReservationResult) LogFields() []observability.Field {
+	if r == nil || r.FailureReason == "" {
+		return nil
+	}
+	return []observability.Field{observability.F("failure_reason", r.FailureReason)}
+}
+
+var _ application.UseCase[domorder.OrderCreatedEvent, *ReservationResult] = (*ReserveInventoryUseCase)(nil)
+
 type ReserveInventoryUseCase struct {
 	invRepo      dominv.Repository
-	publisher    domoutbox.Publisher
 	log          observability.Logger
 	tracer       observability.Tracer
-	reqCounter   observability.Counter
-	durHistogram observability.Histogram
-	extCounter   observability.Counter
-	extHistogram observability.Histogram
+	reqCounter   observability.BoundCounter
+	durHistogram observability.BoundHistogram
+	events       *application.EventPublisher
+
+	lowStockGlobal     int
+	lowStockPerProduct map[string]int
+	lowStockCounter    observability.Counter // inventory_low_stock_total{product}
+	invariantCounter   observability.Counter // inventory_invariant_violation_total{operation}
+	remainingGauge     observability.Gauge   // inventory_remaining_stock{product}
+	remainingProducts  map[string]struct{}   // allow-list for remainingGauge
+
+	reservations dominv.ReservationRepository // nil unless WithReservationTracking
+
+	provisioner       dominv.Provisioner // nil unless WithAutoProvision
+	provisionQuantity int
 }
 
-func NewReserveInventoryUseCase(invRepo dominv.Repository, publisher domoutbox.Publisher, tel observability.Observability) *ReserveInventoryUseCase {
-	baseLog := observability.NopLogger().With(
-		observability.F("service", inventoryService),
+func NewReserveInventoryUseCase(invRepo dominv.Repository, publisher domoutbox.Publisher, tel observability.Observability, opts ...Option) *ReserveInventoryUseCase {
+	baseLog := observability.LoggerOf(tel).With(
+		observability.F(observability.FieldService, observability.ServiceInventory),
 	)
-	tracer := observability.NopTracer()
-	metricsProvider := observability.NopMetrics()
-	if tel != nil {
-		baseLog = tel.Logger().With(
-			observability.F("service", inventoryService),
-		)
-		tracer = tel.Tracer()
-		metricsProvider = tel.Metrics()
-	}
-	req := metricsProvider.Counter(observability.MUsecaseRequests)
-	dur := metricsProvider.Histogram(observability.MUsecaseDuration)
-	extReq := metricsProvider.Counter(observability.MExternalRequests)
-	extDur := metricsProvider.Histogram(observability.MExternalRequestDuration)
-
-	return &ReserveInventoryUseCase{
-		invRepo:      invRepo,
-		publisher:    publisher,
-		log:          baseLog,
-		tracer:       tracer,
-		reqCounter:   req,
-		durHistogram: dur,
-		extCounter:   extReq,
-		extHistogram: extDur,
+	tracer := observability.TracerOf(tel)
+	metricsProvider := observability.MetricsOf(tel)
+	req := metricsProvider.Counter(observability.MUsecaseRequests).Bind(observability.UseCaseLabels(observability.ServiceInventory, useCaseInventoryReservation)...)
+	dur := metricsProvider.Histogram(observability.MUsecaseDuration).Bind(observability.UseCaseLabels(observability.ServiceInventory, useCaseInventoryReservation)...)
+
+	uc := &ReserveInventoryUseCase{
+		invRepo:          invRepo,
+		log:              baseLog,
+		tracer:           tracer,
+		reqCounter:       req,
+		durHistogram:     dur,
+		events:           application.NewEventPublisher(publisher, publishTimeout, tel),
+		lowStockCounter:  metricsProvider.Counter(observability.MInventoryLowStock),
+		invariantCounter: metricsProvider.Counter(observability.MInventoryInvariant),
+		remainingGauge:   metricsProvider.Gauge(observability.MInventoryRemainingStock),
+	}
+	for _, opt := range opts {
+		opt(uc)
 	}
+	return uc
 }
 
 // Execute reacts to OrderCreated events and emits reservation result events.
 func (uc *ReserveInventoryUseCase) Execute(ctx context.Context, e domorder.OrderCreatedEvent) (_ *ReservationResult, err error) {
-	logger := logctx.FromOr(ctx, uc.log).With(
+	logger := logctx.FromOrWith(ctx, uc.log, observability.F(observability.FieldService, observability.ServiceInventory)).With(
 		observability.F("use_case", useCaseInventoryReservation),
 		observability.F("order_id", e.OrderID),
 		observability.F("product_id", e.ProductID),
@@ -89,6 +107,7 @@ func (uc *ReserveInventoryUseCase) Execute(ctx context.Context, e domorder.Order
 		attribute.String("order.id", e.OrderID),
 		attribute.String("product.id", e.ProductID),
 		attribute.Int("order.quantity", e.Quantity),
+		attribute.String("order.operation_id", e.OperationID()),
 	)
 	start := time.Now()
 	outcome, statusText := "success", "OK"
@@ -110,15 +129,10 @@ func (uc *ReserveInventoryUseCase) Execute(ctx context.Context, e domorder.Order
 
 		latency := time.Since(start).Seconds()
 		if uc.reqCounter != nil {
-			uc.reqCounter.Add(1,
-				observability.L("use_case", useCaseInventoryReservation),
-				observability.L("outcome", outcome),
-			)
+			uc.reqCounter.Add(1, observability.L("outcome", outcome))
 		}
 		if uc.durHistogram != nil {
-			uc.durHistogram.Observe(latency,
-				observability.L("use_case", useCaseInventoryReservation),
-			)
+			uc.durHistogram.Observe(latency)
 		}
 
 		fields := []observability.Field{
@@ -151,15 +165,44 @@ func (uc *ReserveInventoryUseCase) Execute(ctx context.Context, e domorder.Order
 		logger.Info("use_case_done", fields...)
 	}()
 
-	if err = uc.invRepo.Reserve(ctx, e.ProductID, e.Quantity); err != nil {
+	// Keyed on the operation rather than the order so redeliveries dedup on the same key
+	// as order creation.
+	remaining, err := uc.invRepo.Reserve(ctx, e.OperationID(), e.ProductID, e.Quantity)
+	if errors.Is(err, dominv.ErrNotFound) && uc.autoProvision(ctx, logger, e.ProductID) {
+		remaining, err = uc.invRepo.Reserve(ctx, e.OperationID(), e.ProductID, e.Quantity)
+	}
+	if errors.Is(err, dominv.ErrAlreadyReserved) {
+		// Redelivered event: the first delivery already reserved, tracked and published.
+		outcome, statusText = "idempotent_replay", "IDEMPOTENT_REPLAY"
+		if span != nil {
+			span.AddEvent("inventory.idempotent_replay",
+				trace.WithAttributes(attribute.String("order.operation_id", e.OperationID())),
+			)
+		}
+		return result, nil
+	}
+	if errors.Is(err, dominv.ErrInvariantViolation) {
+		uc.invariantCounter.Add(1, observability.L("operation", "reserve"))
+		logger.Error("inventory_invariant_violation", observability.F("error", err.Error()))
+	}
+	if err != nil {
 		outcome, statusText = "error", "RESERVE_FAILED"
 		failureReason = failureReasonFromError(err)
 		result.Reserved = false
 		result.FailureReason = failureReason
-		publishFailureErr = uc.publish(ctx, endpointReservationFailed, dominv.NewInventoryReservationFailedEvent(e.OrderID, e.ProductID, e.Quantity, failureReason))
+		publishFailureErr = uc.events.Publish(ctx, logger, useCaseInventoryReservation, endpointReservationFailed, dominv.NewInventoryReservationFailedEvent(e.OrderID, e.ProductID, e.Quantity, failureReason))
 		return result, fmt.Errorf("inventory: reserve: %w", err)
 	}
 
+	uc.track(ctx, logger, e)
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("inventory.remaining_after", remaining))
+	}
+	if _, ok := uc.remainingProducts[e.ProductID]; ok {
+		uc.remainingGauge.Set(float64(remaining), observability.L("product", e.ProductID))
+	}
+
 	if span != nil {
 		span.AddEvent("inventory.reserved",
 			trace.WithAttributes(
@@ -169,53 +212,84 @@ func (uc *ReserveInventoryUseCase) Execute(ctx context.Context, e domorder.Order
 		)
 	}
 
-	publishReservedErr = uc.publish(ctx, endpointReserved, dominv.NewInventoryReservedEvent(e.OrderID, e.ProductID, e.Quantity))
+	publishReservedErr = uc.events.Publish(ctx, logger, useCaseInventoryReservation, endpointReserved, dominv.NewInventoryReservedEvent(e.OrderID, e.ProductID, e.Quantity))
 	if publishReservedErr != nil {
-		outcome, statusText = "error", "EVENT_PUBLISH_FAILED"
+		_, statusText = application.PublishOutcome(publishReservedErr)
+		outcome = "error"
 		return result, fmt.Errorf("inventory: publish reserved: %w", publishReservedErr)
 	}
 
+	uc.checkLowStock(ctx, logger, e.ProductID, remaining)
 	return result, nil
 }
 
-// OnOrderCreated keeps the old API available while the rest of the codebase migrates to Execute.
-func (uc *ReserveInventoryUseCase) OnOrderCreated(ctx context.Context, e domorder.OrderCreatedEvent) error {
-	_, err := uc.Execute(ctx, e)
-	return err
+// autoProvision stocks an unknown product when WithAutoProvision is set and reports
+// whether the reservation is worth retrying. Failures keep the original not-found error.
+func (uc *ReserveInventoryUseCase) autoProvision(ctx context.Context, logger observability.Logger, productID string) bool {
+	if uc.provisioner == nil || productID == "" {
+		return false
+	}
+	created, err := uc.provisioner.Provision(ctx, productID, uc.provisionQuantity)
+	if err != nil {
+		logger.Warn("inventory_auto_provision_failed",
+			observability.F("product_id", productID),
+			observability.F("error", err.Error()),
+		)
+		return false
+	}
+	if created {
+		trace.SpanFromContext(ctx).AddEvent("inventory.auto_provisioned",
+			trace.WithAttributes(
+				attribute.String("product.id", productID),
+				attribute.Int("inventory.quantity", uc.provisionQuantity),
+			),
+		)
+		logger.Info("inventory_auto_provisioned",
+			observability.F("product_id", productID),
+			observability.F("quantity", uc.provisionQuantity),
+		)
+	}
+	return true
 }
 
-func (uc *ReserveInventoryUseCase) publish(ctx context.Context, endpoint string, event domoutbox.Event) error {
-	if uc.publisher == nil || event == nil {
-		return nil
+// track records the reservation for TTL expiry. A failure is logged only: the stock is
+// reserved either way, it just won't be released automatically.
+func (uc *ReserveInventoryUseCase) track(ctx context.Context, logger observability.Logger, e domorder.OrderCreatedEvent) {
+	if uc.reservations == nil {
+		return
 	}
-
-	pubCtx, cancel := context.WithTimeout(ctx, publishTimeout)
-	start := time.Now()
-	err := uc.publisher.Publish(pubCtx, event)
-	outcome := "success"
+	err := uc.reservations.Track(ctx, dominv.Reservation{
+		OrderID:    e.OrderID,
+		ProductID:  e.ProductID,
+		Quantity:   e.Quantity,
+		ReservedAt: clock.Now(),
+	})
 	if err != nil {
-		outcome = "error"
-	} else if pubCtx.Err() != nil {
-		outcome = "canceled"
-		err = pubCtx.Err()
+		logger.Warn("reservation_track_failed", observability.F("error", err.Error()))
 	}
-	cancel()
+}
 
-	if uc.extCounter != nil {
-		uc.extCounter.Add(1,
-			observability.L("peer", publishPeer),
-			observability.L("endpoint", endpoint),
-			observability.L("outcome", outcome),
-		)
+// checkLowStock reports stock that fell below its threshold. A failed low-stock publish is
+// only logged (event_published): the reservation itself succeeded.
+func (uc *ReserveInventoryUseCase) checkLowStock(ctx context.Context, logger observability.Logger, productID string, remaining int) {
+	threshold, label := uc.lowStockGlobal, lowStockOtherProduct
+	if t, ok := uc.lowStockPerProduct[productID]; ok {
+		threshold, label = t, productID
 	}
-	if uc.extHistogram != nil {
-		uc.extHistogram.Observe(time.Since(start).Seconds(),
-			observability.L("peer", publishPeer),
-			observability.L("endpoint", endpoint),
-		)
+	if threshold <= 0 || remaining >= threshold {
+		return
 	}
 
-	return err
+	uc.lowStockCounter.Add(1, observability.L("product", label))
+	trace.SpanFromContext(ctx).AddEvent("inventory.low_stock",
+		trace.WithAttributes(
+			attribute.String("product.id", productID),
+			attribute.Int("inventory.remaining", remaining),
+			attribute.Int("inventory.threshold", threshold),
+		),
+	)
+	_ = uc.events.Publish(ctx, logger.With(observability.F("product_id", productID)), useCaseInventoryReservation, endpointLowStock,
+		dominv.NewInventoryLowStockEvent(productID, remaining, threshold))
 }
 
 func failureReasonFromError(err error) string {
@@ -226,6 +300,8 @@ func failureReasonFromError(err error) string {
 		return dominv.FailureReasonPersistenceError
 	case errors.Is(err, dominv.ErrInsufficientStock):
 		return dominv.FailureReasonInsufficientStock
+	case errors.Is(err, dominv.ErrInvariantViolation):
+		return dominv.FailureReasonInvariant
 	default:
 		return err.Error()
 	}
@@ -0,0 +1,283 @@
+package inventory_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	appInventory "github.com/Zhima-Mochi/minishop-observability/app/internal/application/inventory"
+	dominv "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/testkit"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// recordingPublisher keeps every published event in order.
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []domoutbox.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, e domoutbox.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, e)
+	return nil
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, domoutbox.Event) error {
+	return errors.New("broker unavailable")
+}
+
+func TestReserveInventoryRecordsOneOutcomePerRun(t *testing.T) {
+	created := domorder.OrderCreatedEvent{OrderID: "o-1", ProductID: "sku-1", Quantity: 2, Amount: 100}
+
+	tests := []struct {
+		name         string
+		stock        int
+		publisher    domoutbox.Publisher
+		wantReserved bool
+		wantOutcome  string
+	}{
+		{"reserved", 5, nil, true, "success"},
+		{"insufficient stock", 1, nil, false, "error"},
+		{"publish failed", 5, failingPublisher{}, true, "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := obstest.New()
+			stock := memory.NewInventoryRepository()
+			stock.Seed("sku-1", tt.stock)
+			publisher := tt.publisher
+			if publisher == nil {
+				publisher = testkit.StartedBus(t)
+			}
+			uc := appInventory.NewReserveInventoryUseCase(stock, publisher, rec)
+
+			res, _ := obstest.ExecuteUseCase(t, context.Background(), rec, uc, "inventory.reserve", created)
+			if res == nil || res.Reserved != tt.wantReserved {
+				t.Errorf("result = %+v, want Reserved=%v", res, tt.wantReserved)
+			}
+			got := rec.Count(observability.MUsecaseRequests,
+				observability.L("use_case", "inventory.reserve"),
+				observability.L("outcome", tt.wantOutcome),
+			)
+			if got != 1 {
+				t.Errorf("usecase_requests_total{outcome=%q} = %v, want 1 (series: %v)",
+					tt.wantOutcome, got, rec.Series(observability.MUsecaseRequests))
+			}
+		})
+	}
+}
+
+func TestReserveInventoryRedeliveryIsIdempotentReplay(t *testing.T) {
+	rec := obstest.New()
+	stock := memory.NewInventoryRepository()
+	stock.Seed("sku-1", 5)
+	uc := appInventory.NewReserveInventoryUseCase(stock, testkit.StartedBus(t), rec)
+	created := domorder.OrderCreatedEvent{OrderID: "o-1", ProductID: "sku-1", Quantity: 2, Amount: 100}
+
+	for range 2 {
+		if _, err := obstest.ExecuteUseCase(t, context.Background(), rec, uc, "inventory.reserve", created); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	}
+	replays := rec.Count(observability.MUsecaseRequests,
+		observability.L("use_case", "inventory.reserve"),
+		observability.L("outcome", "idempotent_replay"),
+	)
+	if replays != 1 {
+		t.Errorf("usecase_requests_total{outcome=\"idempotent_replay\"} = %v, want 1", replays)
+	}
+	if left, _ := stock.Available(context.Background(), "sku-1"); left != 3 {
+		t.Errorf("available stock = %d, want 3 (reserved once)", left)
+	}
+}
+
+func TestReserveRefusesToOversellCorruptedStock(t *testing.T) {
+	ctx := context.Background()
+	rec := obstest.New()
+	stock := memory.NewInventoryRepository()
+	stock.Seed("sku-1", -1) // corrupted by an upstream bug
+	stock.Seed("sku-2", 1)
+
+	publisher := &recordingPublisher{}
+	uc := appInventory.NewReserveInventoryUseCase(stock, publisher, rec)
+
+	_, err := uc.Execute(ctx, domorder.OrderCreatedEvent{OrderID: "o-1", ProductID: "sku-1", Quantity: 1, Amount: 100})
+	if !errors.Is(err, dominv.ErrInvariantViolation) {
+		t.Fatalf("Execute error = %v, want %v", err, dominv.ErrInvariantViolation)
+	}
+	// Plain insufficient stock is a business result, not a broken invariant.
+	if _, err := uc.Execute(ctx, domorder.OrderCreatedEvent{OrderID: "o-2", ProductID: "sku-2", Quantity: 2, Amount: 100}); !errors.Is(err, dominv.ErrInsufficientStock) {
+		t.Fatalf("Execute error = %v, want %v", err, dominv.ErrInsufficientStock)
+	}
+
+	if got := rec.Count(observability.MInventoryInvariant, observability.L("operation", "reserve")); got != 1 {
+		t.Errorf("inventory_invariant_violation_total{operation=\"reserve\"} = %v, want 1", got)
+	}
+	if left, _ := stock.Available(ctx, "sku-1"); left != -1 {
+		t.Errorf("available stock = %d, want -1 (untouched)", left)
+	}
+	var failures []dominv.InventoryReservationFailedEvent
+	for _, e := range publisher.events {
+		if f, ok := e.(dominv.InventoryReservationFailedEvent); ok {
+			failures = append(failures, f)
+		}
+	}
+	if len(failures) != 2 || failures[0].Reason != dominv.FailureReasonInvariant {
+		t.Errorf("reservation failed events = %+v, want the first with reason %q", failures, dominv.FailureReasonInvariant)
+	}
+}
+
+// stalledPublisher blocks every publish until its context ends, like a broker that
+// stopped responding.
+type stalledPublisher struct{ started chan struct{} }
+
+func (p stalledPublisher) Publish(ctx context.Context, _ domoutbox.Event) error {
+	close(p.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestReservePublishTimeoutAndCancellationAreLabeledApart(t *testing.T) {
+	tests := []struct {
+		name        string
+		cancel      bool // cancel the caller while the publish is stalled
+		wantOutcome string
+		wantStatus  string
+	}{
+		{"publish too slow", false, "timeout", "EVENT_PUBLISH_TIMEOUT"},
+		{"caller canceled", true, "canceled", "EVENT_PUBLISH_CANCELED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := obstest.New()
+			stock := memory.NewInventoryRepository()
+			stock.Seed("sku-1", 5)
+			publisher := stalledPublisher{started: make(chan struct{})}
+			uc := appInventory.NewReserveInventoryUseCase(stock, publisher, rec)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				go func() {
+					<-publisher.started
+					cancel()
+				}()
+			}
+			_, err := uc.Execute(ctx, domorder.OrderCreatedEvent{OrderID: "o-1", ProductID: "sku-1", Quantity: 1, Amount: 100})
+			if err == nil {
+				t.Fatal("Execute error = nil, want the publish failure")
+			}
+
+			published := rec.Count(observability.MExternalRequests,
+				observability.L("peer", "outbox"),
+				observability.L("endpoint", "inventory.reserved"),
+				observability.L("outcome", tt.wantOutcome),
+			)
+			if published != 1 {
+				t.Errorf("external_requests_total{outcome=%q} = %v, want 1 (series: %v)",
+					tt.wantOutcome, published, rec.Series(observability.MExternalRequests))
+			}
+			logs := rec.Logs("use_case_done")
+			if len(logs) != 1 || logs[0].Fields["status"] != tt.wantStatus {
+				t.Errorf("use_case_done = %+v, want status %q", logs, tt.wantStatus)
+			}
+			spans := rec.Spans("UC.OnOrderCreated")
+			if len(spans) != 1 {
+				t.Fatalf("recorded %d use case spans, want 1", len(spans))
+			}
+			if st := spans[0].Status(); st.Code != codes.Error || st.Description != tt.wantStatus {
+				t.Errorf("span status = %v %q, want Error %q", st.Code, st.Description, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestReserveReportsRemainingStock(t *testing.T) {
+	ctx := context.Background()
+	rec := obstest.New()
+	stock := memory.NewInventoryRepository()
+	stock.Seed("sku-1", 10)
+	stock.Seed("sku-2", 10)
+	uc := appInventory.NewReserveInventoryUseCase(stock, &recordingPublisher{}, rec,
+		appInventory.WithRemainingStockGauge("sku-1"))
+
+	for i, evt := range []domorder.OrderCreatedEvent{
+		{OrderID: "o-1", ProductID: "sku-1", Quantity: 3, Amount: 100},
+		{OrderID: "o-2", ProductID: "sku-1", Quantity: 4, Amount: 100},
+		{OrderID: "o-3", ProductID: "sku-2", Quantity: 1, Amount: 100},
+	} {
+		if _, err := uc.Execute(ctx, evt); err != nil {
+			t.Fatalf("Execute #%d: %v", i, err)
+		}
+	}
+
+	var remaining []int64
+	for _, s := range rec.Spans("UC.OnOrderCreated") {
+		for _, kv := range s.Attributes() {
+			if kv.Key == "inventory.remaining_after" {
+				remaining = append(remaining, kv.Value.AsInt64())
+			}
+		}
+	}
+	if !slices.Equal(remaining, []int64{7, 3, 9}) {
+		t.Errorf("inventory.remaining_after = %v, want [7 3 9] (stock after each reserve)", remaining)
+	}
+	if got := rec.GaugeValue(observability.MInventoryRemainingStock, observability.L("product", "sku-1")); got != 3 {
+		t.Errorf("inventory_remaining_stock{product=\"sku-1\"} = %v, want 3", got)
+	}
+	// sku-2 is not allow-listed, so it never gets a series.
+	for _, labels := range rec.Series(observability.MInventoryRemainingStock) {
+		if slices.Contains(labels, observability.L("product", "sku-2")) {
+			t.Errorf("inventory_remaining_stock has a series for sku-2: %v", labels)
+		}
+	}
+}
+
+func TestRedeliveredOrderCreatedReservesOnce(t *testing.T) {
+	ctx := context.Background()
+	rec := obstest.New()
+	stock := memory.NewInventoryRepository()
+	stock.Seed("sku-1", 5)
+	publisher := &recordingPublisher{}
+	uc := appInventory.NewReserveInventoryUseCase(stock, publisher, rec)
+
+	// At-least-once delivery: the same OrderCreated arrives twice.
+	created := domorder.OrderCreatedEvent{OrderID: "o-1", ProductID: "sku-1", Quantity: 2, Amount: 100}
+	for range 2 {
+		if _, err := uc.Execute(ctx, created); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	}
+
+	if left, _ := stock.Available(ctx, "sku-1"); left != 3 {
+		t.Errorf("available stock = %d, want 3 (reserved once)", left)
+	}
+	var reserved int
+	for _, e := range publisher.events {
+		if _, ok := e.(dominv.InventoryReservedEvent); ok {
+			reserved++
+		}
+	}
+	if reserved != 1 {
+		t.Errorf("InventoryReserved published %d times, want 1", reserved)
+	}
+	replays := rec.Count(observability.MUsecaseRequests,
+		observability.L("use_case", "inventory.reserve"),
+		observability.L("outcome", "idempotent_replay"),
+	)
+	if replays != 1 {
+		t.Errorf("usecase_requests_total{outcome=\"idempotent_replay\"} = %v, want 1", replays)
+	}
+}
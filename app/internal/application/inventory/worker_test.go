@@ -0,0 +1,70 @@
+package inventory_test
+
+import (
+	"context"
+	"testing"
+
+	appInventory "github.com/Zhima-Mochi/minishop-observability/app/internal/application/inventory"
+	dominv "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+)
+
+// handlerSubscriber keeps the handlers a worker registers so a test can call them
+// directly, without the bus putting a logger in their context.
+type handlerSubscriber map[string]domoutbox.Handler
+
+func (s handlerSubscriber) Subscribe(event string, h domoutbox.Handler) domoutbox.SubscriptionToken {
+	return s.SubscribeNamed(event, event, h)
+}
+
+func (s handlerSubscriber) SubscribeNamed(_, event string, h domoutbox.Handler) domoutbox.SubscriptionToken {
+	s[event] = h
+	return domoutbox.SubscriptionToken(len(s))
+}
+
+func (handlerSubscriber) Unsubscribe(string, domoutbox.SubscriptionToken) {}
+
+func TestWorkerLogsThroughFallbackWithoutContextLogger(t *testing.T) {
+	rec := obstest.New()
+	inv := memory.NewInventoryRepository()
+	inv.Seed("sku-1", 5)
+	subs := handlerSubscriber{}
+	appInventory.New(subs, appInventory.NewReserveInventoryUseCase(inv, nil, rec), rec, rec.Logger()).Start()
+
+	ctx := context.Background()
+	if err := subs["inventory.low_stock"](ctx, dominv.InventoryLowStockEvent{ProductID: "sku-1", Remaining: 1, Threshold: 2}); err != nil {
+		t.Fatalf("low-stock handler: %v", err)
+	}
+	if err := subs["order.created"](ctx, domorder.OrderCreatedEvent{OrderID: "o-1", ProductID: "sku-1", Quantity: 1, Amount: 100}); err != nil {
+		t.Fatalf("order-created handler: %v", err)
+	}
+
+	low := rec.Logs("inventory_low_stock")
+	if len(low) != 1 {
+		t.Fatalf("inventory_low_stock lines = %d, want 1", len(low))
+	}
+	if low[0].Fields[observability.FieldService] != observability.ServiceInventoryWorker || low[0].Fields["product_id"] != "sku-1" {
+		t.Errorf("inventory_low_stock fields = %v, want the worker's service and the product", low[0].Fields)
+	}
+	var workerDone bool
+	for _, e := range rec.Logs("use_case_done") {
+		if e.Fields["use_case"] == "inventory.worker.order_created" {
+			workerDone = true
+		}
+	}
+	if !workerDone {
+		t.Error("order-created handler wrote no use_case_done line through the fallback logger")
+	}
+}
+
+func TestWorkerWithoutAnyLoggerDoesNotPanic(t *testing.T) {
+	subs := handlerSubscriber{}
+	appInventory.New(subs, appInventory.NewReserveInventoryUseCase(memory.NewInventoryRepository(), nil, nil), nil, nil).Start()
+	if err := subs["inventory.low_stock"](context.Background(), dominv.InventoryLowStockEvent{ProductID: "sku-1"}); err != nil {
+		t.Errorf("low-stock handler: %v", err)
+	}
+}
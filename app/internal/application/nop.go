@@ -0,0 +1,73 @@
+package application
+
+import (
+	"context"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+)
+
+const dropReasonNoPublisher = "no_publisher"
+
+var (
+	_ domoutbox.Publisher  = (*NopPublisher)(nil)
+	_ domoutbox.Subscriber = NopSubscriber{}
+)
+
+// NopPublisher drops every event, logging and counting it in outbox_events_dropped_total
+// (reason "no_publisher"). NewEventPublisher substitutes it for a nil publisher; wire it
+// explicitly where events are deliberately disabled, e.g. tests or a read-only
+// deployment, so the drops stay visible.
+type NopPublisher struct {
+	log     observability.Logger
+	dropped observability.Counter // outbox_events_dropped_total{event,reason}
+}
+
+func NewNopPublisher(tel observability.Observability) *NopPublisher {
+	return &NopPublisher{
+		log:     observability.LoggerOf(tel).With(observability.F(observability.FieldComponent, observability.ComponentOutbox)),
+		dropped: observability.MetricsOf(tel).Counter(observability.MOutboxEventsDropped),
+	}
+}
+
+func (p *NopPublisher) Publish(ctx context.Context, e domoutbox.Event) error {
+	if e == nil {
+		return nil
+	}
+	logctx.FromOr(ctx, p.log).Warn("event_dropped_no_publisher",
+		observability.F("event", e.EventName()),
+		observability.F("reason", dropReasonNoPublisher),
+	)
+	p.dropped.Add(1,
+		observability.L("event", e.EventName()),
+		observability.L("reason", dropReasonNoPublisher),
+	)
+	return nil
+}
+
+// NopSubscriber accepts subscriptions and never delivers, for components that must be
+// constructed without a bus (e.g. driving a use case directly in tests).
+type NopSubscriber struct{}
+
+func (NopSubscriber) Subscribe(string, domoutbox.Handler) domoutbox.SubscriptionToken { return 0 }
+
+func (NopSubscriber) SubscribeNamed(string, string, domoutbox.Handler) domoutbox.SubscriptionToken {
+	return 0
+}
+
+func (NopSubscriber) Unsubscribe(string, domoutbox.SubscriptionToken) {}
+
+// SubscriberOrNop returns subscriber, or a NopSubscriber if it is nil. Like a nil
+// publisher, a nil subscriber is almost always a wiring mistake, so it is logged once
+// here, naming the worker that will receive no events.
+func SubscriberOrNop(subscriber domoutbox.Subscriber, service string, tel observability.Observability) domoutbox.Subscriber {
+	if subscriber != nil {
+		return subscriber
+	}
+	observability.LoggerOf(tel).Warn("event_subscriber_missing",
+		observability.F(observability.FieldService, service),
+		observability.F("detail", "running without event subscriber, worker receives no events"),
+	)
+	return NopSubscriber{}
+}
@@ -0,0 +1,57 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/testkit"
+)
+
+func TestNilPublisherCountsEveryDroppedEvent(t *testing.T) {
+	rec := obstest.New()
+	p := application.NewEventPublisher(nil, time.Second, rec)
+
+	evt := domorder.OrderCreatedEvent{OrderID: "o-1"}
+	for range 2 {
+		if err := p.Publish(context.Background(), rec.Logger(), "order.create", "order.created", evt); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	dropped := rec.Count(observability.MOutboxEventsDropped,
+		observability.L("event", "order.created"),
+		observability.L("reason", "no_publisher"),
+	)
+	if dropped != 2 {
+		t.Errorf("outbox_events_dropped_total{reason=\"no_publisher\"} = %v, want 2 (series: %v)",
+			dropped, rec.Series(observability.MOutboxEventsDropped))
+	}
+}
+
+func TestUseCasesAndWorkersRunWithoutEvents(t *testing.T) {
+	rec := obstest.New()
+	h := testkit.New(t, testkit.WithTelemetry(rec), testkit.WithoutEvents())
+	h.Seed("sku-1", 5)
+
+	res, err := h.RunCreateOrder(context.Background(), appOrder.CreateOrderInput{
+		CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100,
+	})
+	if err != nil {
+		t.Fatalf("RunCreateOrder: %v", err)
+	}
+	// With no subscriber the saga never starts, so the order stays pending and its
+	// event is counted as dropped rather than lost silently.
+	h.ExpectOrderStatus(t, res.OrderID, domorder.StatusPending)
+	dropped := rec.Count(observability.MOutboxEventsDropped,
+		observability.L("event", "order.created"),
+		observability.L("reason", "no_publisher"),
+	)
+	if dropped != 1 {
+		t.Errorf("outbox_events_dropped_total{event=\"order.created\"} = %v, want 1", dropped)
+	}
+}
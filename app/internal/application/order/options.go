@@ -0,0 +1,41 @@
+package order
+
+import domain "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+
+// PublishPolicy decides what a failed OrderCreated publish means for the caller.
+type PublishPolicy int
+
+const (
+	// PublishBestEffort keeps the created order and reports success; the failure is
+	// only visible in logs, the span status and external_requests_total.
+	PublishBestEffort PublishPolicy = iota
+	// PublishRequired fails the operation with ErrEventPublish so the client retries.
+	// The order is already stored by then and stays pending: a retry with the same
+	// idempotency key publishes its OrderCreated again, a retry without one creates a
+	// second order.
+	PublishRequired
+)
+
+func (p PublishPolicy) String() string {
+	switch p {
+	case PublishRequired:
+		return "required"
+	default:
+		return "best_effort"
+	}
+}
+
+// Option customises CreateOrderUseCase.
+type Option func(*CreateOrderUseCase)
+
+// WithPublishPolicy sets how publish failures are surfaced; defaults to PublishBestEffort.
+func WithPublishPolicy(p PublishPolicy) Option {
+	return func(uc *CreateOrderUseCase) { uc.publishPolicy = p }
+}
+
+// WithUnitOfWork makes the order insert and its OrderCreated event commit atomically
+// through a transactional outbox. The publisher is then bypassed (a relay publishes),
+// so PublishPolicy no longer applies.
+func WithUnitOfWork(uow domain.UnitOfWork) Option {
+	return func(uc *CreateOrderUseCase) { uc.uow = uow }
+}
@@ -1,5 +1,11 @@
 package order
 
+// The order context keeps no synchronous inventory or payment port: stock is reserved by
+// the inventory worker reacting to OrderCreated, and the outcome returns as an
+// inventory.reserved / inventory.reservation_failed event handled by Worker. Payment
+// follows order.inventory_reserved in the payment worker; callers that need to pay
+// synchronously use POST /payment/pay, which runs the same payment use case.
+
 type IDGenerator interface {
     NewID() string
 }
@@ -0,0 +1,173 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
+	dominventory "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+	domain "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const useCaseSagaView = "order.saga_view"
+
+// Reservation states reported by SagaView.
+const (
+	ReservationPending  = "pending"  // inventory has not answered yet
+	ReservationHeld     = "held"     // stock reserved, awaiting payment
+	ReservationConsumed = "consumed" // order paid; the stock is gone for good
+	ReservationFailed   = "failed"   // never reserved, or released (see FailureReason)
+)
+
+// Payment states reported by SagaView.
+const (
+	PaymentNotAttempted = "not_attempted"
+	PaymentSucceeded    = "succeeded"
+	PaymentFailed       = "failed"
+)
+
+type SagaViewInput struct {
+	OrderID string
+}
+
+// SagaView joins what each context knows about one order, for support investigations.
+type SagaView struct {
+	Order     SagaOrder     `json:"order"`
+	Inventory SagaInventory `json:"inventory"`
+	Payment   SagaPayment   `json:"payment"`
+}
+
+type SagaOrder struct {
+	ID            string        `json:"id"`
+	Status        domain.Status `json:"status"`
+	FailureReason string        `json:"failure_reason,omitempty"`
+	Version       int64         `json:"version"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type SagaInventory struct {
+	ProductID   string     `json:"product_id"`
+	Quantity    int        `json:"quantity"`
+	Reservation string     `json:"reservation"`
+	ReservedAt  *time.Time `json:"reserved_at,omitempty"` // set while a TTL-tracked reservation is held
+	Available   *int       `json:"available,omitempty"`   // current product stock, when known
+}
+
+type SagaPayment struct {
+	Status        string `json:"status"`
+	Amount        int64  `json:"amount"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+var _ application.UseCase[SagaViewInput, *SagaView] = (*SagaViewUseCase)(nil)
+
+// SagaViewUseCase is a read-only projection of an order's saga across contexts.
+// The inventory readers are optional; without them those fields are omitted.
+type SagaViewUseCase struct {
+	orders       domain.Repository
+	stock        dominventory.StockReader
+	reservations dominventory.ReservationRepository
+	tracer       observability.Tracer
+	log          observability.Logger
+	reqCounter   observability.BoundCounter   // usecase_requests_total{stage,use_case,outcome}
+	durHistogram observability.BoundHistogram // usecase_duration_seconds{stage,use_case}
+}
+
+func NewSagaViewUseCase(
+	orders domain.Repository,
+	stock dominventory.StockReader,
+	reservations dominventory.ReservationRepository,
+	tel observability.Observability,
+) *SagaViewUseCase {
+	metricsProvider := observability.MetricsOf(tel)
+	return &SagaViewUseCase{
+		orders:       orders,
+		stock:        stock,
+		reservations: reservations,
+		tracer:       observability.TracerOf(tel),
+		log:          observability.LoggerOf(tel).With(observability.F(observability.FieldService, observability.ServiceOrder)),
+		reqCounter:   metricsProvider.Counter(observability.MUsecaseRequests).Bind(observability.UseCaseLabels(observability.ServiceOrder, useCaseSagaView)...),
+		durHistogram: metricsProvider.Histogram(observability.MUsecaseDuration).Bind(observability.UseCaseLabels(observability.ServiceOrder, useCaseSagaView)...),
+	}
+}
+
+func (uc *SagaViewUseCase) Execute(ctx context.Context, in SagaViewInput) (_ *SagaView, err error) {
+	ctx, span := uc.tracer.Start(ctx, spanPrefix+"SagaView",
+		attribute.String("use_case", useCaseSagaView),
+		attribute.String("order.id", in.OrderID),
+	)
+	start := time.Now()
+	outcome := "success"
+	defer func() {
+		if err != nil {
+			outcome = "error"
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "SAGA_VIEW_FAILED")
+		}
+		span.End()
+		uc.reqCounter.Add(1, observability.L("outcome", outcome))
+		uc.durHistogram.Observe(time.Since(start).Seconds())
+	}()
+
+	o, err := uc.orders.Get(ctx, in.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("saga: load order: %w", err)
+	}
+
+	view := &SagaView{
+		Order: SagaOrder{
+			ID:            o.ID,
+			Status:        o.Status,
+			FailureReason: o.FailureReason,
+			Version:       o.Version,
+			CreatedAt:     o.CreatedAt,
+			UpdatedAt:     o.UpdatedAt,
+		},
+		Inventory: SagaInventory{ProductID: o.ProductID, Quantity: o.Quantity},
+		Payment:   SagaPayment{Status: PaymentNotAttempted, Amount: o.Amount},
+	}
+
+	switch o.Status {
+	case domain.StatusPending:
+		view.Inventory.Reservation = ReservationPending
+	case domain.StatusInventoryReserved:
+		view.Inventory.Reservation = ReservationHeld
+	case domain.StatusPaymentFailed:
+		view.Inventory.Reservation = ReservationHeld
+		view.Payment.Status, view.Payment.FailureReason = PaymentFailed, o.FailureReason
+	case domain.StatusCompleted:
+		view.Inventory.Reservation = ReservationConsumed
+		view.Payment.Status = PaymentSucceeded
+	default:
+		view.Inventory.Reservation = ReservationFailed
+	}
+
+	// Projection extras are best effort: a failing side lookup must not hide the order.
+	logger := logctx.FromOrWith(ctx, uc.log, observability.F(observability.FieldService, observability.ServiceOrder))
+	if uc.reservations != nil && view.Inventory.Reservation == ReservationHeld {
+		res, resErr := uc.reservations.Get(ctx, o.ID)
+		switch {
+		case resErr == nil:
+			view.Inventory.ReservedAt = &res.ReservedAt
+		case !errors.Is(resErr, dominventory.ErrReservationNotFound):
+			logger.Warn("saga_view_reservation_lookup_failed", observability.F("order_id", o.ID), observability.F("error", resErr.Error()))
+		}
+	}
+	if uc.stock != nil {
+		available, stockErr := uc.stock.Available(ctx, o.ProductID)
+		if stockErr == nil {
+			view.Inventory.Available = &available
+		} else if !errors.Is(stockErr, dominventory.ErrNotFound) {
+			logger.Warn("saga_view_stock_lookup_failed", observability.F("order_id", o.ID), observability.F("error", stockErr.Error()))
+		}
+	}
+	return view, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
 	domain "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
 	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
@@ -17,32 +18,43 @@ import (
 )
 
 const (
-	orderService       = "order-service"
 	useCaseOrderCreate = "order.create"
 	spanPrefix         = "UC."
-	publishPeer        = "outbox"
 	publishEndpoint    = "order.created"
 	publishTimeout     = 300 * time.Millisecond
+
+	repoPeer                  = "order_repository"
+	idempotencyLookupEndpoint = "find_by_idempotency"
+	idempotencyLookupSpanName = "order.idempotency_lookup"
 )
 
 var (
 	ErrConflict   = domain.ErrConflict
 	ErrNotFound   = domain.ErrNotFound
 	ErrRepository = errors.New("order: repository failure")
+	// ErrEventPublish is returned under PublishRequired when OrderCreated could not be published.
+	ErrEventPublish = errors.New("order: event publish failed")
+	// ErrValidation marks input rejected before any side effect; match it with IsValidation.
+	ErrValidation = errors.New("validation")
 )
 
+var _ application.UseCase[CreateOrderInput, *CreateOrderResult] = (*CreateOrderUseCase)(nil)
+
 // CreateOrderUseCase encapsulates the order creation workflow with observability hooks.
 type CreateOrderUseCase struct {
 	repo        domain.Repository
 	idGenerator IDGenerator
-	publisher   domoutbox.Publisher
-	tel         observability.Observability
+	events      *application.EventPublisher
+	tracer      observability.Tracer
+
+	publishPolicy PublishPolicy
+	uow           domain.UnitOfWork
 
 	// Base logger with fixed fields prebound (vendor must remain hidden).
 	log observability.Logger
 	// RED metrics (supplied via DI; do not instantiate inside methods).
-	reqCounter   observability.Counter   // usecase_requests_total{use_case,outcome}
-	durHistogram observability.Histogram // usecase_duration_seconds{use_case}
+	reqCounter   observability.BoundCounter   // usecase_requests_total{stage,use_case,outcome}
+	durHistogram observability.BoundHistogram // usecase_duration_seconds{stage,use_case}
 
 	extCounter   observability.Counter   // external_requests_total{peer,endpoint,outcome}
 	extHistogram observability.Histogram // external_request_duration_seconds{peer,endpoint}
@@ -54,36 +66,36 @@ func NewCreateOrderUseCase(
 	idGen IDGenerator,
 	publisher domoutbox.Publisher,
 	tel observability.Observability,
+	opts ...Option,
 ) *CreateOrderUseCase {
-	baseLog := observability.NopLogger()
-	if tel != nil {
-		baseLog = tel.Logger()
-	}
-	baseLog = baseLog.With(
-		observability.F("service", orderService),
+	baseLog := observability.LoggerOf(tel).With(
+		observability.F(observability.FieldService, observability.ServiceOrder),
 	)
+	metricsProvider := observability.MetricsOf(tel)
 
-	metricsProvider := observability.NopMetrics()
-	if tel != nil {
-		metricsProvider = tel.Metrics()
-	}
-
-	req := metricsProvider.Counter(observability.MUsecaseRequests)
-	dur := metricsProvider.Histogram(observability.MUsecaseDuration)
+	req := metricsProvider.Counter(observability.MUsecaseRequests).Bind(observability.UseCaseLabels(observability.ServiceOrder, useCaseOrderCreate)...)
+	dur := metricsProvider.Histogram(observability.MUsecaseDuration).Bind(observability.UseCaseLabels(observability.ServiceOrder, useCaseOrderCreate)...)
 	extReq := metricsProvider.Counter(observability.MExternalRequests)
 	extDur := metricsProvider.Histogram(observability.MExternalRequestDuration)
 
-	return &CreateOrderUseCase{
+	uc := &CreateOrderUseCase{
 		repo:         repo,
 		idGenerator:  idGen,
-		publisher:    publisher,
-		tel:          tel,
+		tracer:       observability.TracerOf(tel),
 		log:          baseLog,
 		reqCounter:   req,
 		durHistogram: dur,
 		extCounter:   extReq,
 		extHistogram: extDur,
 	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	// With a unit of work the relay publishes OrderCreated, so the use case needs no publisher.
+	if uc.uow == nil {
+		uc.events = application.NewEventPublisher(publisher, publishTimeout, tel)
+	}
+	return uc
 }
 
 type CreateOrderInput struct {
@@ -100,12 +112,12 @@ type CreateOrderResult struct {
 
 // Execute performs the order creation flow.
 func (uc *CreateOrderUseCase) Execute(ctx context.Context, cmd CreateOrderInput) (_ *CreateOrderResult, err error) {
-	logger := logctx.FromOr(ctx, uc.log).With(observability.F("use_case", useCaseOrderCreate))
+	logger := logctx.FromOrWith(ctx, uc.log, observability.F(observability.FieldService, observability.ServiceOrder)).With(observability.F("use_case", useCaseOrderCreate))
 
 	var orderID string
 	var publishErr error
 
-	ctx, span := uc.tel.Tracer().Start(ctx, spanPrefix+"CreateOrder",
+	ctx, span := uc.tracer.Start(ctx, spanPrefix+"CreateOrder",
 		attribute.String("use_case", useCaseOrderCreate),
 		attribute.String("order.customer_id", cmd.CustomerID),
 		attribute.String("order.product_id", cmd.ProductID),
@@ -127,15 +139,10 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, cmd CreateOrderInput)
 		}
 
 		if uc.reqCounter != nil {
-			uc.reqCounter.Add(1,
-				observability.L("use_case", useCaseOrderCreate),
-				observability.L("outcome", outcome),
-			)
+			uc.reqCounter.Add(1, observability.L("outcome", outcome))
 		}
 		if uc.durHistogram != nil {
-			uc.durHistogram.Observe(lat,
-				observability.L("use_case", useCaseOrderCreate),
-			)
+			uc.durHistogram.Observe(lat)
 		}
 
 		fields := []observability.Field{
@@ -159,21 +166,45 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, cmd CreateOrderInput)
 		logger.Info("use_case_done", fields...)
 	}()
 
-	if cmd.CustomerID == "" {
-		outcome, statusText = "error", "CUSTOMER_ID_REQUIRED"
-		return nil, newValidation("customer id is required")
-	}
-	if cmd.ProductID == "" {
-		outcome, statusText = "error", "PRODUCT_ID_REQUIRED"
-		return nil, newValidation("product id is required")
+	// publish sends OrderCreated for entity; only under PublishRequired does a failure
+	// fail the operation.
+	publish := func(entity *domain.Order) error {
+		publishErr = uc.events.Publish(ctx, logger, useCaseOrderCreate, publishEndpoint, domain.NewOrderCreatedEvent(entity))
+		pubOutcome, pubStatus := application.PublishOutcome(publishErr)
+		if publishErr != nil {
+			statusText = pubStatus
+		}
+
+		if publishErr != nil && uc.publishPolicy == PublishRequired {
+			outcome = "error"
+			if pubOutcome == "backpressure" {
+				outcome = pubOutcome
+			}
+			return fmt.Errorf("%w: %w", ErrEventPublish, publishErr)
+		}
+		return nil
 	}
-	if cmd.Quantity <= 0 {
-		outcome, statusText = "error", "QUANTITY_INVALID"
-		return nil, newValidation("quantity must be greater than zero")
+	// replay answers a retried idempotency key with the order it already created. Under
+	// PublishRequired a still-pending order may be one whose OrderCreated failed to
+	// publish, so the event is sent again; consumers already tolerate redelivery.
+	replay := func(existing *domain.Order) (*CreateOrderResult, error) {
+		orderID = existing.ID
+		statusText = "IDEMPOTENT_REPLAY"
+		span.SetAttributes(attribute.String("order.status", string(existing.Status)))
+		span.AddEvent("order.idempotent_replay",
+			trace.WithAttributes(attribute.String("order.id", orderID)),
+		)
+		if uc.uow == nil && uc.publishPolicy == PublishRequired && existing.Status == domain.StatusPending {
+			if err := publish(existing); err != nil {
+				return nil, err
+			}
+		}
+		return &CreateOrderResult{OrderID: existing.ID, Status: existing.Status}, nil
 	}
-	if cmd.Amount <= 0 {
-		outcome, statusText = "error", "AMOUNT_INVALID"
-		return nil, newValidation("amount must be greater than zero")
+
+	if verr := validateCreateOrder(cmd); verr != nil {
+		outcome, statusText = "error", verr.Code
+		return nil, verr
 	}
 	if err := ctx.Err(); err != nil {
 		outcome, statusText = "error", "CONTEXT_CANCELED"
@@ -181,16 +212,10 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, cmd CreateOrderInput)
 	}
 
 	if cmd.IdempotencyKey != "" {
-		existing, repoErr := uc.repo.FindByIdempotency(ctx, cmd.CustomerID, cmd.IdempotencyKey)
+		existing, repoErr := uc.findByIdempotency(ctx, cmd.CustomerID, cmd.IdempotencyKey)
 		switch {
 		case repoErr == nil:
-			orderID = existing.ID
-			statusText = "IDEMPOTENT_REPLAY"
-			span.SetAttributes(attribute.String("order.status", string(existing.Status)))
-			span.AddEvent("order.idempotent_replay",
-				trace.WithAttributes(attribute.String("order.id", orderID)),
-			)
-			return &CreateOrderResult{OrderID: existing.ID, Status: existing.Status}, nil
+			return replay(existing)
 		case errors.Is(repoErr, domain.ErrNotFound):
 			// continue
 		default:
@@ -209,50 +234,20 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, cmd CreateOrderInput)
 		outcome, statusText = "error", "CONTEXT_CANCELED"
 		return nil, err
 	}
-	if err := uc.repo.Insert(ctx, entity); err != nil {
+	if err := uc.insert(ctx, entity); err != nil {
 		if errors.Is(err, domain.ErrConflict) && cmd.IdempotencyKey != "" {
-			if existing, lookupErr := uc.repo.FindByIdempotency(ctx, cmd.CustomerID, cmd.IdempotencyKey); lookupErr == nil {
-				orderID = existing.ID
-				statusText = "IDEMPOTENT_REPLAY"
-				span.SetAttributes(attribute.String("order.status", string(existing.Status)))
-				span.AddEvent("order.idempotent_replay",
-					trace.WithAttributes(attribute.String("order.id", orderID)),
-				)
-				return &CreateOrderResult{OrderID: existing.ID, Status: existing.Status}, nil
+			if existing, lookupErr := uc.findByIdempotency(ctx, cmd.CustomerID, cmd.IdempotencyKey); lookupErr == nil {
+				return replay(existing)
 			}
 		}
 		outcome, statusText = "error", "REPO_INSERT_FAILED"
 		return nil, wrapRepositoryError(err)
 	}
 
-	if uc.publisher != nil {
-		pubCtx, cancel := context.WithTimeout(ctx, publishTimeout)
-		pubStart := time.Now()
-		pubOutcome := "success"
-
-		publishErr = uc.publisher.Publish(pubCtx, domain.NewOrderCreatedEvent(entity))
-		if publishErr != nil {
-			pubOutcome = "error"
-			statusText = "EVENT_PUBLISH_FAILED"
-		} else if pubCtx.Err() != nil {
-			pubOutcome = "canceled"
-			publishErr = pubCtx.Err()
-			statusText = "EVENT_PUBLISH_TIMEOUT"
-		}
-		cancel()
-
-		if uc.extCounter != nil {
-			uc.extCounter.Add(1,
-				observability.L("peer", publishPeer),
-				observability.L("endpoint", publishEndpoint),
-				observability.L("outcome", pubOutcome),
-			)
-		}
-		if uc.extHistogram != nil {
-			uc.extHistogram.Observe(time.Since(pubStart).Seconds(),
-				observability.L("peer", publishPeer),
-				observability.L("endpoint", publishEndpoint),
-			)
+	// With a unit of work the event is already in the transactional outbox; the relay publishes it.
+	if uc.uow == nil {
+		if err := publish(entity); err != nil {
+			return nil, err
 		}
 	}
 
@@ -266,9 +261,53 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, cmd CreateOrderInput)
 	return &CreateOrderResult{OrderID: entity.ID, Status: entity.Status}, nil
 }
 
-// CreateOrder preserves backwards compatibility with existing callers that have not been migrated yet.
-func (uc *CreateOrderUseCase) CreateOrder(ctx context.Context, input CreateOrderInput) (*CreateOrderResult, error) {
-	return uc.Execute(ctx, input)
+// findByIdempotency wraps the repository lookup in a child span and records it as an
+// external call, so a slow idempotency store shows up in traces and latency metrics.
+func (uc *CreateOrderUseCase) findByIdempotency(ctx context.Context, customerID, key string) (*domain.Order, error) {
+	ctx, span := uc.tracer.Start(ctx, idempotencyLookupSpanName)
+	defer span.End()
+	start := time.Now()
+
+	existing, err := uc.repo.FindByIdempotency(ctx, customerID, key)
+	outcome := "hit"
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrNotFound):
+		outcome = "miss"
+	default:
+		outcome = "error"
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "IDEMPOTENCY_LOOKUP_FAILED")
+	}
+	span.SetAttributes(attribute.String("idempotency.outcome", outcome))
+
+	if uc.extCounter != nil {
+		uc.extCounter.Add(1,
+			observability.L("peer", repoPeer),
+			observability.L("endpoint", idempotencyLookupEndpoint),
+			observability.L("outcome", outcome),
+		)
+	}
+	if uc.extHistogram != nil {
+		uc.extHistogram.Observe(time.Since(start).Seconds(),
+			observability.L("peer", repoPeer),
+			observability.L("endpoint", idempotencyLookupEndpoint),
+		)
+	}
+	return existing, err
+}
+
+// insert stores the order, together with its OrderCreated event when a unit of work is configured.
+func (uc *CreateOrderUseCase) insert(ctx context.Context, entity *domain.Order) error {
+	if uc.uow == nil {
+		return uc.repo.Insert(ctx, entity)
+	}
+	return uc.uow.Do(ctx, func(tx domain.Tx) error {
+		if err := tx.Insert(ctx, entity); err != nil {
+			return err
+		}
+		return tx.Enqueue(ctx, domain.NewOrderCreatedEvent(entity))
+	})
 }
 
 func wrapRepositoryError(err error) error {
@@ -285,6 +324,46 @@ func wrapRepositoryError(err error) error {
 	}
 }
 
-func newValidation(msg string) error {
-	return fmt.Errorf("validation: %w", errors.New(msg))
+// ValidationError rejects a command field. Code is stable and field-specific (e.g.
+// CUSTOMER_ID_REQUIRED) so clients can branch on it; it doubles as the span status.
+type ValidationError struct {
+	Code string
+	msg  string
+}
+
+func (e *ValidationError) Error() string { return "validation: " + e.msg }
+
+func (e *ValidationError) Unwrap() error { return ErrValidation }
+
+func newValidation(code, msg string) *ValidationError {
+	return &ValidationError{Code: code, msg: msg}
+}
+
+func validateCreateOrder(cmd CreateOrderInput) *ValidationError {
+	switch {
+	case cmd.CustomerID == "":
+		return newValidation("CUSTOMER_ID_REQUIRED", "customer id is required")
+	case cmd.ProductID == "":
+		return newValidation("PRODUCT_ID_REQUIRED", "product id is required")
+	case cmd.Quantity <= 0:
+		return newValidation("QUANTITY_INVALID", "quantity must be greater than zero")
+	case cmd.Amount <= 0:
+		return newValidation("AMOUNT_INVALID", "amount must be greater than zero")
+	}
+	return nil
+}
+
+// IsValidation reports whether err (or anything it wraps) is an input validation error,
+// which transports should surface as a client error rather than a server failure.
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// ValidationCode returns the field-specific code of a wrapped ValidationError, or "".
+func ValidationCode(err error) string {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return verr.Code
+	}
+	return ""
 }
@@ -0,0 +1,218 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/id"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/testkit"
+)
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, domoutbox.Event) error {
+	return errors.New("broker unavailable")
+}
+
+func TestCreateOrderRecordsOneOutcomePerRun(t *testing.T) {
+	valid := appOrder.CreateOrderInput{CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100}
+	invalid := valid
+	invalid.Quantity = 0
+
+	tests := []struct {
+		name        string
+		publisher   domoutbox.Publisher
+		opts        []appOrder.Option
+		in          appOrder.CreateOrderInput
+		wantErr     error
+		wantOutcome string
+	}{
+		{"created", nil, nil, valid, nil, "success"},
+		{"validation error", nil, nil, invalid, nil, "error"},
+		{"publish failed, best effort", failingPublisher{}, nil, valid, nil, "success"},
+		{"publish failed, required", failingPublisher{},
+			[]appOrder.Option{appOrder.WithPublishPolicy(appOrder.PublishRequired)},
+			valid, appOrder.ErrEventPublish, "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := obstest.New()
+			publisher := tt.publisher
+			if publisher == nil {
+				publisher = testkit.StartedBus(t)
+			}
+			uc := appOrder.NewCreateOrderUseCase(memory.NewOrderRepository(), id.NewUUIDGenerator(), publisher, rec, tt.opts...)
+
+			_, err := obstest.ExecuteUseCase(t, context.Background(), rec, uc, "order.create", tt.in)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Execute error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantOutcome == "success" && err != nil {
+				t.Errorf("Execute error = %v, want nil", err)
+			}
+			got := rec.Count(observability.MUsecaseRequests,
+				observability.L("use_case", "order.create"),
+				observability.L("outcome", tt.wantOutcome),
+			)
+			if got != 1 {
+				t.Errorf("usecase_requests_total{outcome=%q} = %v, want 1 (series: %v)",
+					tt.wantOutcome, got, rec.Series(observability.MUsecaseRequests))
+			}
+		})
+	}
+}
+
+func TestCreateOrderIdempotentReplayRecordsOneOutcome(t *testing.T) {
+	rec := obstest.New()
+	uc := appOrder.NewCreateOrderUseCase(memory.NewOrderRepository(), id.NewUUIDGenerator(), testkit.StartedBus(t), rec)
+	in := appOrder.CreateOrderInput{IdempotencyKey: "k-1", CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100}
+
+	first, err := obstest.ExecuteUseCase(t, context.Background(), rec, uc, "order.create", in)
+	if err != nil {
+		t.Fatalf("first Execute: %v", err)
+	}
+	second, err := obstest.ExecuteUseCase(t, context.Background(), rec, uc, "order.create", in)
+	if err != nil {
+		t.Fatalf("replayed Execute: %v", err)
+	}
+	if first.OrderID != second.OrderID {
+		t.Errorf("replay created order %s, want %s", second.OrderID, first.OrderID)
+	}
+}
+
+// flakyPublisher fails its first publish and records every later one.
+type flakyPublisher struct {
+	calls     int
+	published []domoutbox.Event
+}
+
+func (p *flakyPublisher) Publish(_ context.Context, e domoutbox.Event) error {
+	p.calls++
+	if p.calls == 1 {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, e)
+	return nil
+}
+
+func TestRequiredPublishRetryRepublishesPendingOrder(t *testing.T) {
+	ctx := context.Background()
+	publisher := &flakyPublisher{}
+	uc := appOrder.NewCreateOrderUseCase(memory.NewOrderRepository(), id.NewUUIDGenerator(), publisher, nil,
+		appOrder.WithPublishPolicy(appOrder.PublishRequired))
+	in := appOrder.CreateOrderInput{IdempotencyKey: "k-1", CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100}
+
+	if _, err := uc.Execute(ctx, in); !errors.Is(err, appOrder.ErrEventPublish) {
+		t.Fatalf("first Execute error = %v, want %v", err, appOrder.ErrEventPublish)
+	}
+	// The client retries with the same key: the stored order is still pending and its
+	// OrderCreated never got out, so the replay publishes it.
+	res, err := uc.Execute(ctx, in)
+	if err != nil {
+		t.Fatalf("retried Execute: %v", err)
+	}
+	if len(publisher.published) != 1 {
+		t.Fatalf("published %d events on retry, want 1", len(publisher.published))
+	}
+	created, ok := publisher.published[0].(domorder.OrderCreatedEvent)
+	if !ok || created.OrderID != res.OrderID {
+		t.Errorf("published %+v, want OrderCreated for %s", publisher.published[0], res.OrderID)
+	}
+}
+
+func TestIdempotencyLookupIsTracedOnTheKeyedPath(t *testing.T) {
+	rec := obstest.New()
+	uc := appOrder.NewCreateOrderUseCase(memory.NewOrderRepository(), id.NewUUIDGenerator(), testkit.StartedBus(t), rec)
+	unkeyed := appOrder.CreateOrderInput{CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100}
+	keyed := unkeyed
+	keyed.IdempotencyKey = "k-1"
+
+	if _, err := uc.Execute(context.Background(), unkeyed); err != nil {
+		t.Fatalf("unkeyed Execute: %v", err)
+	}
+	if spans := rec.Spans("order.idempotency_lookup"); len(spans) != 0 {
+		t.Fatalf("unkeyed create started %d lookup spans, want 0", len(spans))
+	}
+
+	// The first keyed create misses; the replay hits.
+	for range 2 {
+		if _, err := uc.Execute(context.Background(), keyed); err != nil {
+			t.Fatalf("keyed Execute: %v", err)
+		}
+	}
+	spans := rec.Spans("order.idempotency_lookup")
+	var outcomes []string
+	for _, s := range spans {
+		if !s.Parent().IsValid() {
+			t.Errorf("lookup span has no parent, want it under the create span")
+		}
+		for _, kv := range s.Attributes() {
+			if kv.Key == "idempotency.outcome" {
+				outcomes = append(outcomes, kv.Value.AsString())
+			}
+		}
+	}
+	if !slices.Equal(outcomes, []string{"miss", "hit"}) {
+		t.Errorf("lookup span outcomes = %v, want [miss hit]", outcomes)
+	}
+	lookups := rec.Observations(observability.MExternalRequestDuration,
+		observability.L("peer", "order_repository"),
+		observability.L("endpoint", "find_by_idempotency"),
+	)
+	if lookups != 2 {
+		t.Errorf("external_request_duration_seconds observations for the lookup = %d, want 2", lookups)
+	}
+}
+
+func TestIsValidationMatchesEveryValidationError(t *testing.T) {
+	valid := appOrder.CreateOrderInput{CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100}
+	tests := []struct {
+		name     string
+		mutate   func(*appOrder.CreateOrderInput)
+		wantCode string
+	}{
+		{"missing customer", func(in *appOrder.CreateOrderInput) { in.CustomerID = "" }, "CUSTOMER_ID_REQUIRED"},
+		{"missing product", func(in *appOrder.CreateOrderInput) { in.ProductID = "" }, "PRODUCT_ID_REQUIRED"},
+		{"zero quantity", func(in *appOrder.CreateOrderInput) { in.Quantity = 0 }, "QUANTITY_INVALID"},
+		{"zero amount", func(in *appOrder.CreateOrderInput) { in.Amount = 0 }, "AMOUNT_INVALID"},
+	}
+	uc := appOrder.NewCreateOrderUseCase(memory.NewOrderRepository(), id.NewUUIDGenerator(), testkit.StartedBus(t), nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := valid
+			tt.mutate(&in)
+			_, err := uc.Execute(context.Background(), in)
+			if !appOrder.IsValidation(err) {
+				t.Fatalf("IsValidation(%v) = false, want true", err)
+			}
+			// Still recognised once a caller wraps it.
+			if wrapped := fmt.Errorf("handler: %w", err); !appOrder.IsValidation(wrapped) || appOrder.ValidationCode(wrapped) != tt.wantCode {
+				t.Errorf("wrapped: IsValidation = %v, ValidationCode = %q; want true, %q",
+					appOrder.IsValidation(wrapped), appOrder.ValidationCode(wrapped), tt.wantCode)
+			}
+		})
+	}
+
+	// Failures after validation are not the client's fault.
+	required := appOrder.NewCreateOrderUseCase(memory.NewOrderRepository(), id.NewUUIDGenerator(), failingPublisher{}, nil,
+		appOrder.WithPublishPolicy(appOrder.PublishRequired))
+	_, err := required.Execute(context.Background(), valid)
+	if err == nil {
+		t.Fatal("Execute with a failing publisher = nil error, want ErrEventPublish")
+	}
+	if appOrder.IsValidation(err) {
+		t.Errorf("IsValidation(%v) = true, want false", err)
+	}
+	if appOrder.IsValidation(nil) {
+		t.Error("IsValidation(nil) = true, want false")
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
 	dominventory "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
 	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
 	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/health"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+	workerpresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/worker"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -19,20 +22,29 @@ import (
 type Worker struct {
 	repo       domorder.Repository
 	subscriber domoutbox.Subscriber
-	publisher  domoutbox.Publisher
 	tel        observability.Observability
 
+	tracer       observability.Tracer
 	log          observability.Logger
-	reqCounter   observability.Counter   // usecase_requests_total{use_case,outcome}
-	durHistogram observability.Histogram // usecase_duration_seconds{use_case}
-	extCounter   observability.Counter   // external_requests_total{peer,endpoint,outcome}
-	extHistogram observability.Histogram // external_request_duration_seconds{peer,endpoint}
+	reqCounter   observability.BoundCounter   // usecase_requests_total{stage,use_case,outcome}
+	durHistogram observability.BoundHistogram // usecase_duration_seconds{stage,use_case}
+	transitions  application.TransitionObserver
+	events       *application.EventPublisher
+	sla          *workerpresentation.SLA
+	subs         workerpresentation.Subscriptions
+	health       *health.State
 }
 
 const (
-	workerService       = "order-worker"
 	endpointInvReserved = "order.inventory_reserved"
 	endpointInvFailed   = "order.inventory_reservation_failed"
+
+	// updateAttempts bounds reload-transition-update retries on ErrVersionConflict.
+	updateAttempts = 3
+
+	handlerInvReserved = "order.inventory_reserved"
+	handlerInvFailed   = "order.inventory_reservation_failed"
+	handlerInvExpired  = "order.inventory_reservation_expired"
 )
 
 func New(
@@ -41,41 +53,54 @@ func New(
 	publisher domoutbox.Publisher,
 	tel observability.Observability,
 	logger observability.Logger,
+	opts ...workerpresentation.Option,
 ) *Worker {
 	base := logger
-	if base == nil && tel != nil {
-		base = tel.Logger()
-	}
 	if base == nil {
-		base = observability.NopLogger()
+		base = observability.LoggerOf(tel)
 	}
 	base = base.With(
-		observability.F("service", workerService),
+		observability.F(observability.FieldService, observability.ServiceOrderWorker),
 	)
-	metricsProvider := observability.NopMetrics()
-	if tel != nil {
-		metricsProvider = tel.Metrics()
-	}
+	metricsProvider := observability.MetricsOf(tel)
+	// use_case varies per handler, so only the stage is bound here.
+	stage := observability.L("stage", observability.StageOf(observability.ServiceOrderWorker))
 
+	o := workerpresentation.ApplyOptions(opts...)
+	o.Health.Register(observability.ServiceOrderWorker)
 	return &Worker{
 		repo:         repo,
-		subscriber:   subscriber,
-		publisher:    publisher,
+		subscriber:   application.SubscriberOrNop(subscriber, observability.ServiceOrderWorker, tel),
 		tel:          tel,
+		tracer:       observability.TracerOf(tel),
 		log:          base,
-		reqCounter:   metricsProvider.Counter(observability.MUsecaseRequests),
-		durHistogram: metricsProvider.Histogram(observability.MUsecaseDuration),
-		extCounter:   metricsProvider.Counter(observability.MExternalRequests),
-		extHistogram: metricsProvider.Histogram(observability.MExternalRequestDuration),
+		reqCounter:   metricsProvider.Counter(observability.MUsecaseRequests).Bind(stage),
+		durHistogram: metricsProvider.Histogram(observability.MUsecaseDuration).Bind(stage),
+		transitions:  application.DefaultTransitionObservers(tel),
+		events:       application.NewEventPublisher(publisher, publishTimeout, tel),
+		sla:          o.SLA,
+		health:       o.Health,
 	}
 }
 
 func (w *Worker) Start() {
-	if w.subscriber == nil || w.repo == nil {
+	if w.repo == nil {
 		return
 	}
-	w.subscriber.Subscribe(dominventory.InventoryReservedEvent{}.EventName(), w.handleInventoryReserved)
-	w.subscriber.Subscribe(dominventory.InventoryReservationFailedEvent{}.EventName(), w.handleInventoryReservationFailed)
+	w.subscribe(handlerInvReserved, dominventory.InventoryReservedEvent{}.EventName(), w.handleInventoryReserved)
+	w.subscribe(handlerInvFailed, dominventory.InventoryReservationFailedEvent{}.EventName(), w.handleInventoryReservationFailed)
+	w.subscribe(handlerInvExpired, dominventory.InventoryReservationExpiredEvent{}.EventName(), w.handleInventoryReservationExpired)
+	w.health.Set(observability.ServiceOrderWorker, true)
+}
+
+// Stop unsubscribes the handlers registered by Start.
+func (w *Worker) Stop() {
+	w.subs.UnsubscribeAll(w.subscriber)
+	w.health.Set(observability.ServiceOrderWorker, false)
+}
+
+func (w *Worker) subscribe(handler, eventName string, h domoutbox.Handler) {
+	w.subs.Add(eventName, w.subscriber.SubscribeNamed(handler, eventName, h))
 }
 
 func (w *Worker) handleInventoryReserved(ctx context.Context, e domoutbox.Event) (err error) {
@@ -86,7 +111,7 @@ func (w *Worker) handleInventoryReserved(ctx context.Context, e domoutbox.Event)
 		return nil
 	}
 
-	ctx, span := w.tel.Tracer().Start(ctx, spanPrefix+"InventoryReserved",
+	ctx, span := w.tracer.Start(ctx, spanPrefix+"InventoryReserved",
 		attribute.String("use_case", useCase),
 		attribute.String("event", e.EventName()),
 		attribute.String("order.id", evt.OrderID),
@@ -95,26 +120,16 @@ func (w *Worker) handleInventoryReserved(ctx context.Context, e domoutbox.Event)
 	outcome, status := "success", "OK"
 	var publishErr error
 
-	logger := logctx.From(ctx)
-	if logger == nil {
-		logger = w.log
-	}
-	logger = logger.With(
-		observability.F("use_case", useCase),
-		observability.F("event", e.EventName()),
+	ctx = w.eventContext(ctx, useCase, e)
+	logger := logctx.FromOr(ctx, w.log).With(
 		observability.F("order_id", evt.OrderID),
 	)
-	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
-		logger = logger.With(
-			observability.F("trace_id", sc.TraceID().String()),
-			observability.F("span_id", sc.SpanID().String()),
-		)
-	}
 	ctx = logctx.With(ctx, logger)
 
 	defer func() {
 		lat := time.Since(start).Seconds()
 		w.observe(useCase, outcome, lat)
+		w.sla.Check(logger, e.EventName(), lat)
 
 		if span != nil {
 			if err != nil {
@@ -144,25 +159,38 @@ func (w *Worker) handleInventoryReserved(ctx context.Context, e domoutbox.Event)
 		logger.Info("use_case_done", fields...)
 	}()
 
-	order, loadErr := w.repo.Get(ctx, evt.OrderID)
-	if loadErr != nil {
-		outcome, status = "error", "ORDER_LOAD_FAILED"
-		return fmt.Errorf("worker: load order: %w", loadErr)
-	}
-
-	if transErr := order.InventoryReserved(); transErr != nil {
-		outcome, status = "error", "STATE_TRANSITION_FAILED"
-		return fmt.Errorf("worker: inventory reserved transition: %w", transErr)
-	}
-
-	if updateErr := w.repo.Update(ctx, order); updateErr != nil {
-		outcome, status = "error", "ORDER_UPDATE_FAILED"
-		return fmt.Errorf("worker: update order: %w", updateErr)
+	var order *domorder.Order
+	err = application.RetryOnConflict(ctx, updateAttempts, domorder.ErrVersionConflict, func(attempt int) error {
+		if attempt > 0 {
+			span.AddEvent("order.version_conflict_retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+		}
+		var loadErr error
+		if order, loadErr = w.repo.Get(ctx, evt.OrderID); loadErr != nil {
+			outcome, status = "error", "ORDER_LOAD_FAILED"
+			return fmt.Errorf("worker: load order: %w", loadErr)
+		}
+		from := order.Status
+		if transErr := order.InventoryReserved(); transErr != nil {
+			outcome, status = "error", "STATE_TRANSITION_FAILED"
+			return fmt.Errorf("worker: inventory reserved transition: %w", transErr)
+		}
+		if updateErr := w.repo.Update(ctx, order); updateErr != nil {
+			outcome, status = "error", "ORDER_UPDATE_FAILED"
+			return fmt.Errorf("worker: update order: %w", updateErr)
+		}
+		w.transitions.ObserveTransition(ctx, application.Transition{OrderID: order.ID, From: from, To: order.Status, Reason: ""})
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	publishErr = w.publish(ctx, endpointInvReserved, domorder.NewOrderInventoryReservedEvent(order))
+	// Published only after the inventory_reserved update is stored, so the payment worker
+	// subscribed to it can never load the order in an earlier state, however the bus
+	// orders or parallelises fanout.
+	publishErr = w.events.Publish(ctx, logger, useCase, endpointInvReserved, domorder.NewOrderInventoryReservedEvent(order))
 	if publishErr != nil {
-		status = "EVENT_PUBLISH_FAILED"
+		_, status = application.PublishOutcome(publishErr)
 	}
 
 	return nil
@@ -176,7 +204,7 @@ func (w *Worker) handleInventoryReservationFailed(ctx context.Context, e domoutb
 		return nil
 	}
 
-	ctx, span := w.tel.Tracer().Start(ctx, spanPrefix+"InventoryReservationFailed",
+	ctx, span := w.tracer.Start(ctx, spanPrefix+"InventoryReservationFailed",
 		attribute.String("use_case", useCase),
 		attribute.String("event", e.EventName()),
 		attribute.String("order.id", evt.OrderID),
@@ -186,26 +214,16 @@ func (w *Worker) handleInventoryReservationFailed(ctx context.Context, e domoutb
 	outcome, status := "success", "OK"
 	var publishErr error
 
-	logger := logctx.From(ctx)
-	if logger == nil {
-		logger = w.log
-	}
-	logger = logger.With(
-		observability.F("use_case", useCase),
-		observability.F("event", e.EventName()),
+	ctx = w.eventContext(ctx, useCase, e)
+	logger := logctx.FromOr(ctx, w.log).With(
 		observability.F("order_id", evt.OrderID),
 	)
-	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
-		logger = logger.With(
-			observability.F("trace_id", sc.TraceID().String()),
-			observability.F("span_id", sc.SpanID().String()),
-		)
-	}
 	ctx = logctx.With(ctx, logger)
 
 	defer func() {
 		lat := time.Since(start).Seconds()
 		w.observe(useCase, outcome, lat)
+		w.sla.Check(logger, e.EventName(), lat)
 
 		if span != nil {
 			if err != nil {
@@ -238,28 +256,119 @@ func (w *Worker) handleInventoryReservationFailed(ctx context.Context, e domoutb
 		logger.Info("use_case_done", fields...)
 	}()
 
-	order, loadErr := w.repo.Get(ctx, evt.OrderID)
-	if loadErr != nil {
-		outcome, status = "error", "ORDER_LOAD_FAILED"
-		return fmt.Errorf("worker: load order: %w", loadErr)
+	var order *domorder.Order
+	err = application.RetryOnConflict(ctx, updateAttempts, domorder.ErrVersionConflict, func(attempt int) error {
+		if attempt > 0 {
+			span.AddEvent("order.version_conflict_retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+		}
+		var loadErr error
+		if order, loadErr = w.repo.Get(ctx, evt.OrderID); loadErr != nil {
+			outcome, status = "error", "ORDER_LOAD_FAILED"
+			return fmt.Errorf("worker: load order: %w", loadErr)
+		}
+		from := order.Status
+		if transErr := order.InventoryReservationFailed(evt.Reason); transErr != nil {
+			outcome, status = "error", "STATE_TRANSITION_FAILED"
+			return fmt.Errorf("worker: inventory reservation failed transition: %w", transErr)
+		}
+		if updateErr := w.repo.Update(ctx, order); updateErr != nil {
+			outcome, status = "error", "ORDER_UPDATE_FAILED"
+			return fmt.Errorf("worker: update order: %w", updateErr)
+		}
+		w.transitions.ObserveTransition(ctx, application.Transition{OrderID: order.ID, From: from, To: order.Status, Reason: evt.Reason})
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if transErr := order.InventoryReservationFailed(evt.Reason); transErr != nil {
-		outcome, status = "error", "STATE_TRANSITION_FAILED"
-		return fmt.Errorf("worker: inventory reservation failed transition: %w", transErr)
+	publishErr = w.events.Publish(ctx, logger, useCase, endpointInvFailed, domorder.NewOrderInventoryReservationFailedEvent(order, evt.Reason))
+	if publishErr != nil {
+		_, status = application.PublishOutcome(publishErr)
 	}
 
-	if updateErr := w.repo.Update(ctx, order); updateErr != nil {
-		outcome, status = "error", "ORDER_UPDATE_FAILED"
-		return fmt.Errorf("worker: update order: %w", updateErr)
-	}
+	return nil
+}
 
-	publishErr = w.publish(ctx, endpointInvFailed, domorder.NewOrderInventoryReservationFailedEvent(order, evt.Reason))
-	if publishErr != nil {
-		status = "EVENT_PUBLISH_FAILED"
+// handleInventoryReservationExpired fails an order whose reserved stock was released by the
+// reservation sweeper. Orders that completed in the meantime reject the transition.
+func (w *Worker) handleInventoryReservationExpired(ctx context.Context, e domoutbox.Event) (err error) {
+	const useCase = "order.worker.inventory_reservation_expired"
+	evt, ok := e.(dominventory.InventoryReservationExpiredEvent)
+	if !ok {
+		w.count(useCase, "ignored")
+		return nil
 	}
 
-	return nil
+	ctx, span := w.tracer.Start(ctx, spanPrefix+"InventoryReservationExpired",
+		attribute.String("use_case", useCase),
+		attribute.String("event", e.EventName()),
+		attribute.String("order.id", evt.OrderID),
+	)
+	start := time.Now()
+	outcome, status := "success", "OK"
+
+	ctx = w.eventContext(ctx, useCase, e)
+	logger := logctx.FromOr(ctx, w.log).With(
+		observability.F("order_id", evt.OrderID),
+	)
+	ctx = logctx.With(ctx, logger)
+
+	defer func() {
+		lat := time.Since(start).Seconds()
+		w.observe(useCase, outcome, lat)
+		w.sla.Check(logger, e.EventName(), lat)
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, status)
+		} else {
+			span.SetStatus(codes.Ok, status)
+		}
+		span.End()
+
+		fields := []observability.Field{
+			observability.F("outcome", outcome),
+			observability.F("status", status),
+			observability.F("latency_seconds", lat),
+			observability.F("order_id", evt.OrderID),
+		}
+		if err != nil {
+			fields = append(fields, observability.F("error", err.Error()))
+		}
+		logger.Info("use_case_done", fields...)
+	}()
+
+	return application.RetryOnConflict(ctx, updateAttempts, domorder.ErrVersionConflict, func(attempt int) error {
+		if attempt > 0 {
+			span.AddEvent("order.version_conflict_retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+		}
+		order, loadErr := w.repo.Get(ctx, evt.OrderID)
+		if loadErr != nil {
+			outcome, status = "error", "ORDER_LOAD_FAILED"
+			return fmt.Errorf("worker: load order: %w", loadErr)
+		}
+		from := order.Status
+		if transErr := order.ReservationExpired(dominventory.FailureReasonExpired); transErr != nil {
+			outcome, status = "error", "STATE_TRANSITION_FAILED"
+			return fmt.Errorf("worker: reservation expired transition: %w", transErr)
+		}
+		if updateErr := w.repo.Update(ctx, order); updateErr != nil {
+			outcome, status = "error", "ORDER_UPDATE_FAILED"
+			return fmt.Errorf("worker: update order: %w", updateErr)
+		}
+		w.transitions.ObserveTransition(ctx, application.Transition{OrderID: order.ID, From: from, To: order.Status, Reason: dominventory.FailureReasonExpired})
+		return nil
+	})
+}
+
+// eventContext binds the shared worker correlation fields (event_id, trace/span IDs) to ctx.
+func (w *Worker) eventContext(ctx context.Context, useCase string, e domoutbox.Event) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	return workerpresentation.WithEventContext(ctx, logctx.FromOrWith(ctx, w.log, observability.F(observability.FieldService, observability.ServiceOrderWorker)), w.tel, sc.TraceID(), sc.SpanID(), map[string]string{
+		"use_case": useCase,
+		"event":    e.EventName(),
+	})
 }
 
 func (w *Worker) count(useCase, outcome string) {
@@ -271,6 +380,12 @@ func (w *Worker) count(useCase, outcome string) {
 	}
 }
 
+// AddTransitionObserver registers o for every order transition the worker stores, in
+// addition to the default metrics and span events. Call it before Start.
+func (w *Worker) AddTransitionObserver(o application.TransitionObserver) {
+	w.transitions = append(application.TransitionObservers{w.transitions}, o)
+}
+
 func (w *Worker) observe(useCase string, outcome string, latencySeconds float64) {
 	w.count(useCase, outcome)
 	if w.durHistogram != nil {
@@ -279,37 +394,3 @@ func (w *Worker) observe(useCase string, outcome string, latencySeconds float64)
 		)
 	}
 }
-
-func (w *Worker) publish(ctx context.Context, endpoint string, event domoutbox.Event) error {
-	if w.publisher == nil || event == nil {
-		return nil
-	}
-
-	pubCtx, cancel := context.WithTimeout(ctx, publishTimeout)
-	start := time.Now()
-	err := w.publisher.Publish(pubCtx, event)
-	outcome := "success"
-	if err != nil {
-		outcome = "error"
-	} else if pubCtx.Err() != nil {
-		outcome = "canceled"
-		err = pubCtx.Err()
-	}
-	cancel()
-
-	if w.extCounter != nil {
-		w.extCounter.Add(1,
-			observability.L("peer", publishPeer),
-			observability.L("endpoint", endpoint),
-			observability.L("outcome", outcome),
-		)
-	}
-	if w.extHistogram != nil {
-		w.extHistogram.Observe(time.Since(start).Seconds(),
-			observability.L("peer", publishPeer),
-			observability.L("endpoint", endpoint),
-		)
-	}
-
-	return err
-}
@@ -0,0 +1,141 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	dominventory "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+)
+
+// racingOrders holds the first n Gets until all of them arrived, so that many concurrent
+// writers load the same order version and all but one must hit a version conflict.
+type racingOrders struct {
+	*memory.OrderRepository
+	mu      sync.Mutex
+	waiting int
+	release chan struct{}
+}
+
+func newRacingOrders(orders *memory.OrderRepository, n int) *racingOrders {
+	return &racingOrders{OrderRepository: orders, waiting: n, release: make(chan struct{})}
+}
+
+func (r *racingOrders) Get(ctx context.Context, id string) (*domorder.Order, error) {
+	o, err := r.OrderRepository.Get(ctx, id)
+	r.mu.Lock()
+	if r.waiting == 0 {
+		r.mu.Unlock()
+		return o, err
+	}
+	r.waiting--
+	if r.waiting == 0 {
+		close(r.release)
+	}
+	r.mu.Unlock()
+	<-r.release
+	return o, err
+}
+
+// syncBus runs every handler for an event inside Publish and returns their errors, so a
+// test sees exactly how each handler ended.
+type syncBus struct {
+	mu       sync.Mutex
+	handlers map[string][]domoutbox.Handler
+}
+
+func (b *syncBus) Subscribe(eventName string, h domoutbox.Handler) domoutbox.SubscriptionToken {
+	return b.SubscribeNamed("", eventName, h)
+}
+
+func (b *syncBus) SubscribeNamed(_, eventName string, h domoutbox.Handler) domoutbox.SubscriptionToken {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.handlers == nil {
+		b.handlers = make(map[string][]domoutbox.Handler)
+	}
+	b.handlers[eventName] = append(b.handlers[eventName], h)
+	return domoutbox.SubscriptionToken(len(b.handlers[eventName]))
+}
+
+func (b *syncBus) Unsubscribe(string, domoutbox.SubscriptionToken) {}
+
+func (b *syncBus) Publish(ctx context.Context, e domoutbox.Event) error {
+	b.mu.Lock()
+	handlers := b.handlers[e.EventName()]
+	b.mu.Unlock()
+	var errs []error
+	for _, h := range handlers {
+		errs = append(errs, h(ctx, e))
+	}
+	return errors.Join(errs...)
+}
+
+func TestConcurrentEventsOnOneOrderApplyExactlyOneTransition(t *testing.T) {
+	ctx := context.Background()
+	orders := memory.NewOrderRepository()
+	o, err := domorder.New("o-1", "c-1", "sku-1", "", 1, 100)
+	if err != nil {
+		t.Fatalf("new order: %v", err)
+	}
+	if err := orders.Insert(ctx, o); err != nil {
+		t.Fatalf("insert order: %v", err)
+	}
+	start, err := orders.Get(ctx, "o-1")
+	if err != nil {
+		t.Fatalf("load order: %v", err)
+	}
+	racing := newRacingOrders(orders, 2)
+
+	bus := &syncBus{}
+	appOrder.New(racing, bus, bus, nil, nil).Start()
+
+	// Inventory reports both a reservation and a failure for the same pending order.
+	var reservedErr, failedErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		reservedErr = bus.Publish(ctx, dominventory.InventoryReservedEvent{OrderID: "o-1", ProductID: "sku-1", Quantity: 1})
+	}()
+	go func() {
+		defer wg.Done()
+		failedErr = bus.Publish(ctx, dominventory.InventoryReservationFailedEvent{OrderID: "o-1", ProductID: "sku-1", Quantity: 1, Reason: "out_of_stock"})
+	}()
+	wg.Wait()
+
+	// Both loaded the same version; the loser reloaded, re-checked its transition against
+	// the winner's state and rejected it instead of overwriting it or giving up on a conflict.
+	for _, err := range []error{reservedErr, failedErr} {
+		if errors.Is(err, domorder.ErrVersionConflict) {
+			t.Errorf("handler gave up on a version conflict: %v", err)
+		}
+	}
+	if (reservedErr == nil) == (failedErr == nil) {
+		t.Fatalf("reserved error = %v, failed error = %v; want exactly one to succeed", reservedErr, failedErr)
+	}
+	loserErr := reservedErr
+	want := domorder.StatusInventoryFailed
+	if reservedErr == nil {
+		loserErr, want = failedErr, domorder.StatusInventoryReserved
+	}
+	if !errors.Is(loserErr, domorder.ErrInvalidStateTransition) {
+		t.Errorf("losing handler error = %v, want %v", loserErr, domorder.ErrInvalidStateTransition)
+	}
+
+	final, err := orders.Get(ctx, "o-1")
+	if err != nil {
+		t.Fatalf("load order: %v", err)
+	}
+	if final.Status != want {
+		t.Errorf("order status = %q, want %q from the winning event", final.Status, want)
+	}
+	if final.Version != start.Version+1 {
+		t.Errorf("order version = %d, want %d (exactly one stored update)", final.Version, start.Version+1)
+	}
+}
@@ -3,10 +3,13 @@ package payment
 import (
 	"context"
 	"errors"
-	"math/rand"
-	"sync"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync/atomic"
 	"time"
 
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
 	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
 	pstat "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/payment"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
@@ -18,74 +21,106 @@ import (
 )
 
 const (
-	paymentService          = "payment-service"
 	useCasePaymentProcess   = "payment.process"
 	paymentSpanName         = "ProcessPayment"
 	spanPrefix              = "UC."
 	defaultPaymentSuccess   = 0.7
 	paymentDeclinedReason   = "payment_declined"
 	paymentSimulationFailed = "PAYMENT_SIMULATION_FAILED"
+	// updateAttempts bounds reload-transition-update retries on ErrVersionConflict.
+	updateAttempts = 3
+
+	paymentResultSuccess  = "success"
+	paymentResultDeclined = "declined"
+	paymentResultError    = "error"
 )
 
+// ErrZeroAmount is returned when the stored order has no positive amount; charging 0
+// would silently complete an unpaid order.
+var ErrZeroAmount = errors.New("payment: amount must be greater than zero")
+
+// ErrOrderNotReady is returned when the order has not reached inventory_reserved, i.e.
+// payment ran ahead of the reservation it depends on.
+var ErrOrderNotReady = errors.New("payment: order not ready for payment")
+
+// ErrAmountMismatch is returned when the command carries an amount other than the
+// stored order's. The order amount is authoritative; a differing amount means a stale or
+// tampered event, so the payment is refused rather than charged either way.
+var ErrAmountMismatch = errors.New("payment: amount does not match the order")
+
 type ProcessPaymentInput struct {
 	OrderID string
-	Amount  int64
+	// Amount, when non-zero, must equal the stored order amount; 0 charges the order
+	// amount unchecked.
+	Amount int64
 }
 
 type ProcessPaymentResult struct {
 	Status pstat.Status
 }
 
+// LogFields reports the payment status on the worker's use_case_done line.
+func (r *ProcessPaymentResult) LogFields() []observability.Field {
+	if r == nil {
+		return nil
+	}
+	return []observability.Field{observability.F("payment_status", string(r.Status))}
+}
+
+var _ application.UseCase[ProcessPaymentInput, *ProcessPaymentResult] = (*ProcessPaymentUseCase)(nil)
+
 type ProcessPaymentUseCase struct {
-	mu          sync.Mutex
-	random      *rand.Rand
-	successRate float64
+	// successRate holds math.Float64bits of the simulated success probability; atomic so
+	// concurrent payments never contend on it.
+	successRate atomic.Uint64
 	orderRepo   domorder.Repository
-	tel         observability.Observability
+	tracer      observability.Tracer
 	log         observability.Logger
-	reqCounter  observability.Counter
-	durHist     observability.Histogram
+	reqCounter  observability.BoundCounter
+	durHist     observability.BoundHistogram
+	// payments_total{result}: business outcome, unlike outcome which treats a decline as success.
+	paymentsCounter observability.Counter
+	// notified once the new order status is stored
+	transitions application.TransitionObserver
 }
 
 func NewProcessPaymentUseCase(orderRepo domorder.Repository, tel observability.Observability) *ProcessPaymentUseCase {
-	baseLog := observability.NopLogger().With(
-		observability.F("service", paymentService),
+	baseLog := observability.LoggerOf(tel).With(
+		observability.F(observability.FieldService, observability.ServicePayment),
 	)
-	metricsProvider := observability.NopMetrics()
-	if tel != nil {
-		baseLog = tel.Logger().With(
-			observability.F("service", paymentService),
-		)
-		metricsProvider = tel.Metrics()
-	}
-	req := metricsProvider.Counter(observability.MUsecaseRequests)
-	dur := metricsProvider.Histogram(observability.MUsecaseDuration)
-
-	return &ProcessPaymentUseCase{
-		random:      rand.New(rand.NewSource(time.Now().UnixNano())),
-		successRate: defaultPaymentSuccess,
-		orderRepo:   orderRepo,
-		tel:         tel,
-		log:         baseLog,
-		reqCounter:  req,
-		durHist:     dur,
+	metricsProvider := observability.MetricsOf(tel)
+	req := metricsProvider.Counter(observability.MUsecaseRequests).Bind(observability.UseCaseLabels(observability.ServicePayment, useCasePaymentProcess)...)
+	dur := metricsProvider.Histogram(observability.MUsecaseDuration).Bind(observability.UseCaseLabels(observability.ServicePayment, useCasePaymentProcess)...)
+
+	uc := &ProcessPaymentUseCase{
+		orderRepo:  orderRepo,
+		tracer:     observability.TracerOf(tel),
+		log:        baseLog,
+		reqCounter: req,
+		durHist:    dur,
+
+		paymentsCounter: metricsProvider.Counter(observability.MPayments),
+		transitions:     application.DefaultTransitionObservers(tel),
 	}
+	uc.successRate.Store(math.Float64bits(defaultPaymentSuccess))
+	return uc
 }
 
 // Execute checks order existence and status, then simulates payment and updates order state.
+//
+// Observability semantics: outcome (usecase_requests_total, span status, use_case_done log)
+// reports whether the payment was processed; a decline is a processed payment, so it is
+// outcome="success" with status "DECLINED". The business result (success|declined|error)
+// is reported as payments_total{result}, the payment.result span attribute and the
+// payment_result log field.
 func (uc *ProcessPaymentUseCase) Execute(ctx context.Context, cmd ProcessPaymentInput) (_ *ProcessPaymentResult, err error) {
-	logger := logctx.FromOr(ctx, uc.log).With(
+	logger := logctx.FromOrWith(ctx, uc.log, observability.F(observability.FieldService, observability.ServicePayment)).With(
 		observability.F("use_case", useCasePaymentProcess),
 		observability.F("order_id", cmd.OrderID),
 		observability.F("amount", cmd.Amount),
 	)
 
-	tracer := observability.NopTracer()
-	if uc.tel != nil {
-		tracer = uc.tel.Tracer()
-	}
-
-	ctx, span := tracer.Start(ctx, spanPrefix+paymentSpanName,
+	ctx, span := uc.tracer.Start(ctx, spanPrefix+paymentSpanName,
 		attribute.String("use_case", useCasePaymentProcess),
 		attribute.String("order.id", cmd.OrderID),
 		attribute.Int64("payment.amount_requested", cmd.Amount),
@@ -97,9 +132,11 @@ func (uc *ProcessPaymentUseCase) Execute(ctx context.Context, cmd ProcessPayment
 	var failureReason string
 
 	defer func() {
+		payResult := paymentResult(result.Status, err)
 		if span != nil {
 			span.SetAttributes(
 				attribute.String("payment.status", string(result.Status)),
+				attribute.String("payment.result", payResult),
 			)
 			if err != nil {
 				span.RecordError(err)
@@ -112,15 +149,13 @@ func (uc *ProcessPaymentUseCase) Execute(ctx context.Context, cmd ProcessPayment
 
 		latency := time.Since(start).Seconds()
 		if uc.reqCounter != nil {
-			uc.reqCounter.Add(1,
-				observability.L("use_case", useCasePaymentProcess),
-				observability.L("outcome", outcome),
-			)
+			uc.reqCounter.Add(1, observability.L("outcome", outcome))
 		}
 		if uc.durHist != nil {
-			uc.durHist.Observe(latency,
-				observability.L("use_case", useCasePaymentProcess),
-			)
+			uc.durHist.Observe(latency)
+		}
+		if uc.paymentsCounter != nil {
+			uc.paymentsCounter.Add(1, observability.L("result", payResult))
 		}
 
 		fields := []observability.Field{
@@ -130,6 +165,7 @@ func (uc *ProcessPaymentUseCase) Execute(ctx context.Context, cmd ProcessPayment
 			observability.F("order_id", cmd.OrderID),
 			observability.F("amount", cmd.Amount),
 			observability.F("payment_status", string(result.Status)),
+			observability.F("payment_result", payResult),
 		}
 		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
 			fields = append(fields,
@@ -167,10 +203,17 @@ func (uc *ProcessPaymentUseCase) Execute(ctx context.Context, cmd ProcessPayment
 	}
 	if !order.CanProcessPayment() {
 		outcome, statusText = "error", "ORDER_NOT_READY"
-		return nil, errors.New("payment: order not ready for payment")
+		return nil, ErrOrderNotReady
+	}
+	// The stored order amount is authoritative; cmd.Amount, when set, must agree with it.
+	if cmd.Amount > 0 && cmd.Amount != order.Amount {
+		outcome, statusText = "error", "AMOUNT_MISMATCH"
+		return nil, fmt.Errorf("%w: command %d, order %d", ErrAmountMismatch, cmd.Amount, order.Amount)
 	}
-	if cmd.Amount > 0 {
-		order.Amount = cmd.Amount
+	span.SetAttributes(attribute.Int64("payment.amount", order.Amount))
+	if order.Amount <= 0 {
+		outcome, statusText = "error", "AMOUNT_ZERO"
+		return nil, ErrZeroAmount
 	}
 
 	status, err = uc.pay(ctx, order.ID, order.Amount)
@@ -181,49 +224,74 @@ func (uc *ProcessPaymentUseCase) Execute(ctx context.Context, cmd ProcessPayment
 		return result, err
 	}
 
-	switch status {
-	case pstat.StatusSuccess:
-		if transErr := order.PaymentSucceeded(); transErr != nil {
-			outcome, statusText = "error", "STATE_TRANSITION_FAILED"
-			failureReason = transErr.Error()
-			result.Status = pstat.StatusFailed
-			return result, transErr
+	// The payment is not re-run on a version conflict: reload the order and re-apply the
+	// same result, which re-validates the transition against the fresh state.
+	err = application.RetryOnConflict(ctx, updateAttempts, domorder.ErrVersionConflict, func(attempt int) error {
+		if attempt > 0 {
+			span.AddEvent("order.version_conflict_retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+			fresh, getErr := uc.orderRepo.Get(ctx, order.ID)
+			if getErr != nil {
+				outcome, statusText = "error", "ORDER_LOOKUP_FAILED"
+				failureReason = getErr.Error()
+				return getErr
+			}
+			order = fresh
 		}
-		statusText = "OK"
-	default:
-		failureReason = paymentDeclinedReason
-		if transErr := order.PaymentFailed(paymentDeclinedReason); transErr != nil {
-			outcome, statusText = "error", "STATE_TRANSITION_FAILED"
-			failureReason = transErr.Error()
-			result.Status = pstat.StatusFailed
-			return result, transErr
+
+		from := order.Status
+		switch status {
+		case pstat.StatusSuccess:
+			if transErr := order.PaymentSucceeded(); transErr != nil {
+				outcome, statusText = "error", "STATE_TRANSITION_FAILED"
+				failureReason = transErr.Error()
+				result.Status = pstat.StatusFailed
+				return transErr
+			}
+			statusText = "OK"
+		default:
+			failureReason = paymentDeclinedReason
+			if transErr := order.PaymentFailed(paymentDeclinedReason); transErr != nil {
+				outcome, statusText = "error", "STATE_TRANSITION_FAILED"
+				failureReason = transErr.Error()
+				result.Status = pstat.StatusFailed
+				return transErr
+			}
+			statusText = "DECLINED"
 		}
-		statusText = "DECLINED"
-	}
 
-	if err = uc.orderRepo.Update(ctx, order); err != nil {
-		outcome, statusText = "error", "ORDER_UPDATE_FAILED"
-		failureReason = err.Error()
+		if updateErr := uc.orderRepo.Update(ctx, order); updateErr != nil {
+			outcome, statusText = "error", "ORDER_UPDATE_FAILED"
+			failureReason = updateErr.Error()
+			return updateErr
+		}
+		var reason string
+		if status != pstat.StatusSuccess {
+			reason = paymentDeclinedReason
+		}
+		uc.transitions.ObserveTransition(ctx, application.Transition{OrderID: order.ID, From: from, To: order.Status, Reason: reason})
+		return nil
+	})
+	if err != nil {
 		return result, err
 	}
 
 	return result, nil
 }
 
-// ProcessPayment maintains the previous signature for callers not yet updated.
-func (uc *ProcessPaymentUseCase) ProcessPayment(ctx context.Context, orderID string, amount int64) (pstat.Status, error) {
-	res, err := uc.Execute(ctx, ProcessPaymentInput{OrderID: orderID, Amount: amount})
-	if res == nil {
-		return pstat.StatusFailed, err
+func paymentResult(status pstat.Status, err error) string {
+	switch {
+	case err != nil:
+		return paymentResultError
+	case status == pstat.StatusSuccess:
+		return paymentResultSuccess
+	default:
+		return paymentResultDeclined
 	}
-	return res.Status, err
 }
 
-// pay simulates the payment result.
+// pay simulates the payment result. It draws from math/rand/v2's global source, which
+// is safe for concurrent use without a lock, so payments are not serialized.
 func (uc *ProcessPaymentUseCase) pay(ctx context.Context, orderID string, amount int64) (pstat.Status, error) {
-	uc.mu.Lock()
-	defer uc.mu.Unlock()
-
 	// respect cancellation even though this is mocked
 	select {
 	case <-ctx.Done():
@@ -231,22 +299,20 @@ func (uc *ProcessPaymentUseCase) pay(ctx context.Context, orderID string, amount
 	default:
 	}
 
-	if uc.random.Float64() <= uc.successRate {
+	if rand.Float64() < math.Float64frombits(uc.successRate.Load()) {
 		return pstat.StatusSuccess, nil
 	}
 
 	return pstat.StatusFailed, nil
 }
 
+// AddTransitionObserver registers o for every order transition a payment stores, in
+// addition to the default metrics and span events.
+func (uc *ProcessPaymentUseCase) AddTransitionObserver(o application.TransitionObserver) {
+	uc.transitions = append(application.TransitionObservers{uc.transitions}, o)
+}
+
 // SetSuccessRate adjusts the success rate for simulations (primarily for tests).
 func (uc *ProcessPaymentUseCase) SetSuccessRate(rate float64) {
-	uc.mu.Lock()
-	if rate < 0 {
-		rate = 0
-	}
-	if rate > 1 {
-		rate = 1
-	}
-	uc.successRate = rate
-	uc.mu.Unlock()
+	uc.successRate.Store(math.Float64bits(min(max(rate, 0), 1)))
 }
@@ -0,0 +1,205 @@
+package payment_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+)
+
+// insertOrder stores an order with the given amount, moved to inventory_reserved when
+// reserved is set.
+func insertOrder(t testing.TB, orders *memory.OrderRepository, id string, amount int64, reserved bool) {
+	t.Helper()
+	o, err := domorder.New(id, "c-1", "sku-1", "", 1, amount)
+	if err != nil {
+		t.Fatalf("new order: %v", err)
+	}
+	if reserved {
+		if err := o.InventoryReserved(); err != nil {
+			t.Fatalf("reserve order: %v", err)
+		}
+	}
+	if err := orders.Insert(context.Background(), o); err != nil {
+		t.Fatalf("insert order: %v", err)
+	}
+}
+
+func TestProcessPaymentRecordsOneOutcomePerRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		successRate float64
+		amount      int64
+		reserved    bool
+		in          payment.ProcessPaymentInput
+		wantErr     error
+		wantOutcome string
+		wantStatus  domorder.Status
+	}{
+		{"paid", 1, 100, true, payment.ProcessPaymentInput{OrderID: "o-1"}, nil, "success", domorder.StatusCompleted},
+		{"paid, amount matches", 1, 100, true, payment.ProcessPaymentInput{OrderID: "o-1", Amount: 100}, nil, "success", domorder.StatusCompleted},
+		// A decline is a handled business result, not a failed run.
+		{"declined", 0, 100, true, payment.ProcessPaymentInput{OrderID: "o-1"}, nil, "success", domorder.StatusPaymentFailed},
+		{"amount mismatch", 1, 100, true, payment.ProcessPaymentInput{OrderID: "o-1", Amount: 1}, payment.ErrAmountMismatch, "error", domorder.StatusInventoryReserved},
+		{"zero-amount order", 1, 0, true, payment.ProcessPaymentInput{OrderID: "o-1"}, payment.ErrZeroAmount, "error", domorder.StatusInventoryReserved},
+		{"zero-amount order, amount supplied", 1, 0, true, payment.ProcessPaymentInput{OrderID: "o-1", Amount: 100}, payment.ErrAmountMismatch, "error", domorder.StatusInventoryReserved},
+		{"not reserved", 1, 100, false, payment.ProcessPaymentInput{OrderID: "o-1"}, payment.ErrOrderNotReady, "error", domorder.StatusPending},
+		{"unknown order", 1, 100, true, payment.ProcessPaymentInput{OrderID: "o-2"}, domorder.ErrNotFound, "error", domorder.StatusInventoryReserved},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := obstest.New()
+			orders := memory.NewOrderRepository()
+			insertOrder(t, orders, "o-1", tt.amount, tt.reserved)
+			uc := payment.NewProcessPaymentUseCase(orders, rec)
+			uc.SetSuccessRate(tt.successRate)
+
+			_, err := obstest.ExecuteUseCase(t, context.Background(), rec, uc, "payment.process", tt.in)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Execute error = %v, want %v", err, tt.wantErr)
+			}
+			got := rec.Count(observability.MUsecaseRequests,
+				observability.L("use_case", "payment.process"),
+				observability.L("outcome", tt.wantOutcome),
+			)
+			if got != 1 {
+				t.Errorf("usecase_requests_total{outcome=%q} = %v, want 1 (series: %v)",
+					tt.wantOutcome, got, rec.Series(observability.MUsecaseRequests))
+			}
+			o, err := orders.Get(context.Background(), "o-1")
+			if err != nil {
+				t.Fatalf("load order: %v", err)
+			}
+			if o.Status != tt.wantStatus {
+				t.Errorf("order status = %q, want %q", o.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestPaymentsTotalCountsDeclinesApartFromUseCaseSuccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		successRate float64
+		amount      int64
+		wantResult  string
+		wantOutcome string
+	}{
+		{"paid", 1, 100, "success", "success"},
+		// The use case handled the decline, so its outcome stays success; only the
+		// business counter tells the two apart.
+		{"declined", 0, 100, "declined", "success"},
+		{"failed", 1, 0, "error", "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := obstest.New()
+			orders := memory.NewOrderRepository()
+			insertOrder(t, orders, "o-1", tt.amount, true)
+			uc := payment.NewProcessPaymentUseCase(orders, rec)
+			uc.SetSuccessRate(tt.successRate)
+
+			_, _ = obstest.ExecuteUseCase(t, context.Background(), rec, uc, "payment.process", payment.ProcessPaymentInput{OrderID: "o-1"})
+			if got := rec.Count(observability.MPayments, observability.L("result", tt.wantResult)); got != 1 {
+				t.Errorf("payments_total{result=%q} = %v, want 1 (series: %v)",
+					tt.wantResult, got, rec.Series(observability.MPayments))
+			}
+			if got := rec.Count(observability.MPayments); got != 1 {
+				t.Errorf("payments_total = %v, want exactly one result per run", got)
+			}
+			outcome := rec.Count(observability.MUsecaseRequests,
+				observability.L("use_case", "payment.process"),
+				observability.L("outcome", tt.wantOutcome),
+			)
+			if outcome != 1 {
+				t.Errorf("usecase_requests_total{outcome=%q} = %v, want 1", tt.wantOutcome, outcome)
+			}
+		})
+	}
+}
+
+func TestPaymentWorkerRefusesZeroAmountOrder(t *testing.T) {
+	rec := obstest.New()
+	orders := memory.NewOrderRepository()
+	insertOrder(t, orders, "o-1", 0, true)
+	bus := outbox.NewBus(nil, rec,
+		outbox.WithSynchronousDispatch(),
+		outbox.WithRetryPolicy(outbox.RetryPolicy{MaxAttempts: 1}),
+	)
+	payment.New(bus, payment.NewProcessPaymentUseCase(orders, rec), rec).Start()
+	bus.Start(context.Background())
+	t.Cleanup(func() { bus.Stop(context.Background()) })
+
+	o, err := orders.Get(context.Background(), "o-1")
+	if err != nil {
+		t.Fatalf("load order: %v", err)
+	}
+	if err := bus.Publish(context.Background(), domorder.NewOrderInventoryReservedEvent(o)); !errors.Is(err, payment.ErrZeroAmount) {
+		t.Errorf("Publish error = %v, want %v", err, payment.ErrZeroAmount)
+	}
+	if o, _ = orders.Get(context.Background(), "o-1"); o.Status != domorder.StatusInventoryReserved {
+		t.Errorf("order status = %q, want %q (never charged 0)", o.Status, domorder.StatusInventoryReserved)
+	}
+	if got := rec.Count(observability.MPayments); got != 1 {
+		t.Errorf("payments_total = %v, want 1 (series: %v)", got, rec.Series(observability.MPayments))
+	}
+	if got := rec.Count(observability.MPayments, observability.L("result", "error")); got != 1 {
+		t.Errorf("payments_total{result=\"error\"} = %v, want 1", got)
+	}
+}
+
+func TestSuccessRateBoundsAreExact(t *testing.T) {
+	for _, tt := range []struct {
+		rate float64
+		want domorder.Status
+	}{
+		{0, domorder.StatusPaymentFailed},
+		{1, domorder.StatusCompleted},
+	} {
+		orders := memory.NewOrderRepository()
+		uc := payment.NewProcessPaymentUseCase(orders, nil)
+		uc.SetSuccessRate(tt.rate)
+		for i := range 500 {
+			id := fmt.Sprintf("o-%d", i)
+			insertOrder(t, orders, id, 100, true)
+			if _, err := uc.Execute(context.Background(), payment.ProcessPaymentInput{OrderID: id}); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if o, _ := orders.Get(context.Background(), id); o.Status != tt.want {
+				t.Fatalf("rate %v: order %s status = %q, want %q", tt.rate, id, o.Status, tt.want)
+			}
+		}
+	}
+}
+
+// BenchmarkProcessPaymentParallel measures payment throughput with every goroutine
+// charging its own order, so nothing but the use case itself is shared.
+func BenchmarkProcessPaymentParallel(b *testing.B) {
+	orders := memory.NewOrderRepository()
+	ids := make([]string, b.N)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("o-%d", i)
+		insertOrder(b, orders, ids[i], 100, true)
+	}
+	uc := payment.NewProcessPaymentUseCase(orders, nil)
+	uc.SetSuccessRate(0.5)
+	var next atomic.Int64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			id := ids[next.Add(1)-1]
+			if _, err := uc.Execute(context.Background(), payment.ProcessPaymentInput{OrderID: id}); err != nil {
+				b.Errorf("Execute(%s): %v", id, err)
+			}
+		}
+	})
+}
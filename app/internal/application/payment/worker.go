@@ -1,85 +1,77 @@
 package payment
 
 import (
-	"context"
-
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
 	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
 	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
-	pstat "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/payment"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/health"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
-	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+	workerpresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/worker"
 )
 
-const paymentWorker = "payment_worker"
+const (
+	handlerOrderInventoryReserved = "payment.order_inventory_reserved"
+)
 
 type Worker struct {
 	subscriber domoutbox.Subscriber
 	useCase    application.UseCase[ProcessPaymentInput, *ProcessPaymentResult]
 	tel        observability.Observability
-
-	log          observability.Logger
-	reqCounter   observability.Counter   // usecase_requests_total{use_case,outcome}
-	durHistogram observability.Histogram // usecase_duration_seconds{use_case}
+	log        observability.Logger
+	sla        *workerpresentation.SLA
+	subs       workerpresentation.Subscriptions
+	health     *health.State
 }
 
 func New(
 	subscriber domoutbox.Subscriber,
 	useCase application.UseCase[ProcessPaymentInput, *ProcessPaymentResult],
 	tel observability.Observability,
+	opts ...workerpresentation.Option,
 ) *Worker {
-	baseLog := observability.NopLogger()
-	metricsProvider := observability.NopMetrics()
-	if tel != nil {
-		baseLog = tel.Logger()
-		metricsProvider = tel.Metrics()
-	}
-
+	o := workerpresentation.ApplyOptions(opts...)
+	o.Health.Register(observability.ServicePaymentWorker)
 	return &Worker{
-		subscriber:   subscriber,
-		useCase:      useCase,
-		tel:          tel,
-		log:          baseLog,
-		reqCounter:   metricsProvider.Counter(observability.MUsecaseRequests),
-		durHistogram: metricsProvider.Histogram(observability.MUsecaseDuration),
+		subscriber: application.SubscriberOrNop(subscriber, observability.ServicePaymentWorker, tel),
+		useCase:    useCase,
+		tel:        tel,
+		log:        observability.LoggerOf(tel).With(observability.F(observability.FieldService, observability.ServicePaymentWorker)),
+		sla:        o.SLA,
+		health:     o.Health,
 	}
 }
 
 func (w *Worker) Start() {
-	if w.subscriber == nil || w.useCase == nil {
+	if w.useCase == nil {
 		return
 	}
-	w.subscriber.Subscribe(domorder.OrderInventoryReservedEvent{}.EventName(), w.handleOrderInventoryReserved)
-}
-
-func (w *Worker) handleOrderInventoryReserved(ctx context.Context, e domoutbox.Event) error {
-	logger := logctx.FromOr(ctx, w.log)
-	logger = logger.With(
-		observability.F("event", e.EventName()),
+	token := workerpresentation.ForEvent(w.subscriber,
+		workerpresentation.EventConfig{
+			Handler: handlerOrderInventoryReserved,
+			Service: observability.ServicePaymentWorker,
+			UseCase: "payment.worker.order_inventory_reserved",
+			Span:    spanPrefix + "OrderInventoryReserved",
+			SLA:     w.sla,
+		},
+		application.MapInput(w.useCase, func(evt domorder.OrderInventoryReservedEvent) ProcessPaymentInput {
+			// Amount 0 (events from older publishers) skips the check against the order amount.
+			return ProcessPaymentInput{OrderID: evt.OrderID, Amount: evt.Amount}
+		}),
+		w.tel, w.log,
+		workerpresentation.WithEventFields(func(evt domorder.OrderInventoryReservedEvent) []observability.Field {
+			fields := []observability.Field{observability.F("order_id", evt.OrderID)}
+			if evt.IdempotencyKey != "" {
+				fields = append(fields, observability.F("idempotency_key", evt.IdempotencyKey))
+			}
+			return fields
+		}),
 	)
+	w.subs.Add(domorder.OrderInventoryReservedEvent{}.EventName(), token)
+	w.health.Set(observability.ServicePaymentWorker, true)
+}
 
-	evt, ok := e.(domorder.OrderInventoryReservedEvent)
-	if !ok {
-		return nil
-	}
-
-	res, err := w.useCase.Execute(ctx, ProcessPaymentInput{OrderID: evt.OrderID, Amount: 0})
-	if err != nil {
-		logger.Warn("payment_processing_failed",
-			observability.F("order_id", evt.OrderID),
-			observability.F("error", err.Error()),
-		)
-		return err
-	}
-
-	status := pstat.StatusFailed
-	if res != nil {
-		status = res.Status
-	}
-
-	logger.Info("payment_processed",
-		observability.F("order_id", evt.OrderID),
-		observability.F("status", string(status)),
-	)
-	return nil
+// Stop unsubscribes the handlers registered by Start.
+func (w *Worker) Stop() {
+	w.subs.UnsubscribeAll(w.subscriber)
+	w.health.Set(observability.ServicePaymentWorker, false)
 }
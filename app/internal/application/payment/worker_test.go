@@ -0,0 +1,96 @@
+package payment_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
+	dominv "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+)
+
+// recordingPayments forwards to the real use case and keeps every error it returns.
+type recordingPayments struct {
+	uc *payment.ProcessPaymentUseCase
+
+	mu   sync.Mutex
+	runs int
+	errs []error
+}
+
+func (r *recordingPayments) Execute(ctx context.Context, cmd payment.ProcessPaymentInput) (*payment.ProcessPaymentResult, error) {
+	res, err := r.uc.Execute(ctx, cmd)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs++
+	if err != nil {
+		r.errs = append(r.errs, err)
+	}
+	return res, err
+}
+
+func TestPaymentNeverRunsBeforeReservationIsStored(t *testing.T) {
+	rec := obstest.New()
+	orders := memory.NewOrderRepository()
+	// Default bus: background dispatchers and concurrent fanout, as in production.
+	bus := outbox.NewBus(nil, rec)
+
+	payments := &recordingPayments{uc: payment.NewProcessPaymentUseCase(orders, rec)}
+	payments.uc.SetSuccessRate(1)
+	appOrder.New(orders, bus, bus, rec, rec.Logger()).Start()
+	payment.New(bus, payments, rec).Start()
+	bus.Start(context.Background())
+	t.Cleanup(func() { bus.Stop(context.Background()) })
+
+	const n = 50
+	ids := make([]string, n)
+	for i := range ids {
+		o, err := domorder.New(fmt.Sprintf("o-%d", i), "c-1", "sku-1", "", 1, 100)
+		if err != nil {
+			t.Fatalf("new order: %v", err)
+		}
+		if err := orders.Insert(context.Background(), o); err != nil {
+			t.Fatalf("insert order: %v", err)
+		}
+		ids[i] = o.ID
+	}
+	for _, id := range ids {
+		if err := bus.Publish(context.Background(), dominv.NewInventoryReservedEvent(id, "sku-1", 1)); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := bus.WaitIdle(ctx); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+
+	payments.mu.Lock()
+	defer payments.mu.Unlock()
+	if payments.runs != n {
+		t.Errorf("payment ran %d times, want %d", payments.runs, n)
+	}
+	for _, err := range payments.errs {
+		if errors.Is(err, payment.ErrOrderNotReady) {
+			t.Fatalf("payment ran before the order was inventory_reserved: %v", err)
+		}
+		t.Errorf("payment failed: %v", err)
+	}
+	for _, id := range ids {
+		o, err := orders.Get(context.Background(), id)
+		if err != nil {
+			t.Fatalf("load order %s: %v", id, err)
+		}
+		if o.Status != domorder.StatusCompleted {
+			t.Errorf("order %s status = %q, want %q", id, o.Status, domorder.StatusCompleted)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+)
+
+// publishPeer labels event publishes on the external request metrics.
+const publishPeer = "outbox"
+
+// EventPublisher publishes domain events for use cases and workers and reports every
+// attempt the same way: external_requests_total and external_request_duration_seconds
+// (peer "outbox") plus one event_published log line naming the producing use case.
+type EventPublisher struct {
+	publisher    domoutbox.Publisher
+	timeout      time.Duration
+	extCounter   observability.Counter   // external_requests_total{peer,endpoint,outcome}
+	extHistogram observability.Histogram // external_request_duration_seconds{peer,endpoint}
+}
+
+// NewEventPublisher bounds each publish through publisher by timeout. A nil publisher is
+// almost always a wiring mistake, so it is reported once here, at construction, and
+// replaced by a NopPublisher, which logs and counts every event it drops.
+func NewEventPublisher(publisher domoutbox.Publisher, timeout time.Duration, tel observability.Observability) *EventPublisher {
+	if publisher == nil {
+		observability.LoggerOf(tel).Warn("event_publisher_missing",
+			observability.F("detail", "running without event publisher, sagas disabled"),
+		)
+		publisher = NewNopPublisher(tel)
+	}
+	metricsProvider := observability.MetricsOf(tel)
+	return &EventPublisher{
+		publisher:    publisher,
+		timeout:      timeout,
+		extCounter:   metricsProvider.Counter(observability.MExternalRequests),
+		extHistogram: metricsProvider.Histogram(observability.MExternalRequestDuration),
+	}
+}
+
+// Publish sends event on behalf of useCase, labelled endpoint in metrics, and logs the
+// result through logger: at debug on success, as a warning otherwise. The publisher's
+// error is returned as is and classified by PublishOutcome, so a publish that returned
+// nil is a success even if the timeout fired just after. A nil *EventPublisher, as held
+// by producers whose events are relayed from a Store instead, publishes nothing.
+func (p *EventPublisher) Publish(ctx context.Context, logger observability.Logger, useCase, endpoint string, event domoutbox.Event) error {
+	if p == nil || event == nil {
+		return nil
+	}
+
+	pubCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	start := time.Now()
+	err := p.publisher.Publish(pubCtx, event)
+	cancel()
+	latency := time.Since(start).Seconds()
+	outcome, _ := PublishOutcome(err)
+
+	p.extCounter.Add(1,
+		observability.L("peer", publishPeer),
+		observability.L("endpoint", endpoint),
+		observability.L("outcome", outcome),
+	)
+	p.extHistogram.Observe(latency,
+		observability.L("peer", publishPeer),
+		observability.L("endpoint", endpoint),
+	)
+
+	fields := []observability.Field{
+		observability.F("event", event.EventName()),
+		observability.F("producer", useCase),
+		observability.F("outcome", outcome),
+		observability.F("latency_seconds", latency),
+	}
+	if err != nil {
+		logger.Warn("event_published", append(fields, observability.F("error", err.Error()))...)
+	} else {
+		logger.Debug("event_published", fields...)
+	}
+	return err
+}
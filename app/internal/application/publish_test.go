@@ -0,0 +1,60 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+)
+
+// slowPublisher succeeds only after the publish deadline has already passed.
+type slowPublisher struct{}
+
+func (slowPublisher) Publish(ctx context.Context, _ domoutbox.Event) error {
+	<-ctx.Done()
+	return nil
+}
+
+// timeoutPublisher gives up with the deadline error of its context.
+type timeoutPublisher struct{}
+
+func (timeoutPublisher) Publish(ctx context.Context, _ domoutbox.Event) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestPublishOutcomeComesFromThePublisherError(t *testing.T) {
+	tests := []struct {
+		name        string
+		publisher   domoutbox.Publisher
+		wantErr     bool
+		wantOutcome string
+	}{
+		{"success after the deadline", slowPublisher{}, false, "success"},
+		{"publisher timed out", timeoutPublisher{}, true, "timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := obstest.New()
+			p := application.NewEventPublisher(tt.publisher, time.Millisecond, rec)
+
+			err := p.Publish(context.Background(), rec.Logger(), "order.create", "order.created", domorder.OrderCreatedEvent{OrderID: "o-1"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Publish error = %v, want error %v", err, tt.wantErr)
+			}
+			got := rec.Count(observability.MExternalRequests,
+				observability.L("endpoint", "order.created"),
+				observability.L("outcome", tt.wantOutcome),
+			)
+			if got != 1 {
+				t.Errorf("external_requests_total{outcome=%q} = %v, want 1 (series: %v)",
+					tt.wantOutcome, got, rec.Series(observability.MExternalRequests))
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Transition is an order status change that has been stored.
+type Transition struct {
+	OrderID string
+	From    domorder.Status
+	To      domorder.Status
+	Reason  string // failure reason, empty for successful steps
+}
+
+// TransitionObserver is notified by use cases and workers after each successful
+// Order transition is persisted, so metrics and spans can follow the order lifecycle
+// without the domain depending on observability.
+type TransitionObserver interface {
+	ObserveTransition(ctx context.Context, t Transition)
+}
+
+// TransitionObservers notifies every observer in order.
+type TransitionObservers []TransitionObserver
+
+func (obs TransitionObservers) ObserveTransition(ctx context.Context, t Transition) {
+	for _, o := range obs {
+		o.ObserveTransition(ctx, t)
+	}
+}
+
+// DefaultTransitionObservers records transitions as metrics and span events.
+func DefaultTransitionObservers(tel observability.Observability) TransitionObservers {
+	return TransitionObservers{NewTransitionMetrics(tel), TransitionSpanEvents{}}
+}
+
+// TransitionMetrics counts transitions in order_transitions_total{from,to}.
+type TransitionMetrics struct {
+	counter observability.Counter
+}
+
+func NewTransitionMetrics(tel observability.Observability) *TransitionMetrics {
+	return &TransitionMetrics{counter: observability.MetricsOf(tel).Counter(observability.MOrderTransitions)}
+}
+
+func (m *TransitionMetrics) ObserveTransition(_ context.Context, t Transition) {
+	m.counter.Add(1,
+		observability.L("from", string(t.From)),
+		observability.L("to", string(t.To)),
+	)
+}
+
+// TransitionSpanEvents adds an order.transition event to the span in ctx.
+type TransitionSpanEvents struct{}
+
+func (TransitionSpanEvents) ObserveTransition(ctx context.Context, t Transition) {
+	attrs := []attribute.KeyValue{
+		attribute.String("order.id", t.OrderID),
+		attribute.String("order.status.from", string(t.From)),
+		attribute.String("order.status.to", string(t.To)),
+	}
+	if t.Reason != "" {
+		attrs = append(attrs, attribute.String("failure.reason", t.Reason))
+	}
+	trace.SpanFromContext(ctx).AddEvent("order.transition", trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,104 @@
+package application_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
+	appInventory "github.com/Zhima-Mochi/minishop-observability/app/internal/application/inventory"
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	appPayment "github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/id"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+)
+
+// capturingObserver records every transition it is notified of.
+type capturingObserver struct {
+	mu   sync.Mutex
+	seen []application.Transition
+}
+
+func (o *capturingObserver) ObserveTransition(_ context.Context, t application.Transition) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.seen = append(o.seen, t)
+}
+
+func (o *capturingObserver) transitions() []application.Transition {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return slices.Clone(o.seen)
+}
+
+func TestTransitionObserverSeesEveryStoredTransition(t *testing.T) {
+	tests := []struct {
+		name        string
+		successRate float64
+		want        []application.Transition
+	}{
+		{"paid", 1, []application.Transition{
+			{From: domorder.StatusPending, To: domorder.StatusInventoryReserved},
+			{From: domorder.StatusInventoryReserved, To: domorder.StatusCompleted},
+		}},
+		{"declined", 0, []application.Transition{
+			{From: domorder.StatusPending, To: domorder.StatusInventoryReserved},
+			{From: domorder.StatusInventoryReserved, To: domorder.StatusPaymentFailed, Reason: "payment_declined"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			rec := obstest.New()
+			orders := memory.NewOrderRepository()
+			stock := memory.NewInventoryRepository()
+			stock.Seed("sku-1", 5)
+			bus := outbox.NewBus(nil, rec)
+
+			observer := &capturingObserver{}
+			orderWorker := appOrder.New(orders, bus, bus, rec, nil)
+			orderWorker.AddTransitionObserver(observer)
+			payments := appPayment.NewProcessPaymentUseCase(orders, rec)
+			payments.SetSuccessRate(tt.successRate)
+			payments.AddTransitionObserver(observer)
+			appInventory.New(bus, appInventory.NewReserveInventoryUseCase(stock, bus, rec), rec, nil).Start()
+			orderWorker.Start()
+			appPayment.New(bus, payments, rec).Start()
+			bus.Start(ctx)
+			t.Cleanup(func() { bus.Stop(ctx) })
+
+			res, err := appOrder.NewCreateOrderUseCase(orders, id.NewUUIDGenerator(), bus, rec).Execute(ctx, appOrder.CreateOrderInput{
+				CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100,
+			})
+			if err != nil {
+				t.Fatalf("create order: %v", err)
+			}
+			if err := bus.WaitIdle(ctx); err != nil {
+				t.Fatalf("WaitIdle: %v", err)
+			}
+
+			want := slices.Clone(tt.want)
+			for i := range want {
+				want[i].OrderID = res.OrderID
+			}
+			if got := observer.transitions(); !slices.Equal(got, want) {
+				t.Errorf("observed transitions = %+v, want %+v", got, want)
+			}
+			// The default observers still run alongside the registered one.
+			for _, tr := range want {
+				got := rec.Count(observability.MOrderTransitions,
+					observability.L("from", string(tr.From)),
+					observability.L("to", string(tr.To)),
+				)
+				if got != 1 {
+					t.Errorf("order_transitions_total{from=%q,to=%q} = %v, want 1", tr.From, tr.To, got)
+				}
+			}
+		})
+	}
+}
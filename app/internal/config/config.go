@@ -0,0 +1,456 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the runtime settings read from the environment at startup.
+type Config struct {
+	ServiceName string
+	Env         string
+	// Version identifies the running build: APP_VERSION, else the module version
+	// embedded by the Go toolchain.
+	Version string
+	// Commit is the VCS revision embedded by the Go toolchain, if any.
+	Commit string
+	// LogExporter enables an extra log pipeline; "otlp" ships logs through the OTel exporter.
+	LogExporter string
+	// LogFileMaxSizeMB, LogFileMaxBackups, LogFileMaxAgeDays and LogFileCompress rotate
+	// LOG_FILE; all unset (default) leaves it unrotated.
+	LogFileMaxSizeMB  int
+	LogFileMaxBackups int
+	LogFileMaxAgeDays int
+	LogFileCompress   bool
+
+	// TraceDebugTenants lists X-Tenant-ID values whose requests carry debug baggage, so
+	// the debug sampler traces them fully (TRACE_DEBUG_TENANTS="acme,globex").
+	TraceDebugTenants []string
+	// TraceExporter enables span export; "otlp" installs a TracerProvider sending to
+	// TraceExporterEndpoint over TraceExporterProtocol ("http", the default, or "grpc").
+	// An empty endpoint falls back to the standard OTEL_EXPORTER_OTLP_* variables.
+	TraceExporter         string
+	TraceExporterEndpoint string
+	TraceExporterProtocol string
+	// TraceSampler is "always", "never", "ratio" or "parentbased_ratio" (the default),
+	// read from OTEL_TRACES_SAMPLER; the spec names always_on, always_off, traceidratio
+	// and parentbased_traceidratio are accepted too. TraceSamplerRatio is the fraction
+	// of traces the ratio modes keep (OTEL_TRACES_SAMPLER_ARG, default 0.1), also
+	// accepted inline as OTEL_TRACES_SAMPLER="ratio:0.25".
+	TraceSampler      string
+	TraceSamplerRatio float64
+	// TracePropagateB3 also propagates B3 headers alongside W3C Trace Context, for
+	// services that only speak B3.
+	TracePropagateB3 bool
+
+	// MetricsBackend is "prometheus" (default, scraped on /metrics), or "otlp" or "stdout"
+	// to push metrics through the OTel SDK every MetricsExportInterval (0 uses the SDK's
+	// one minute). OTLP endpoints follow the standard OTEL_EXPORTER_OTLP_* variables.
+	MetricsBackend        string
+	MetricsExportInterval time.Duration
+	// MetricsNativeHistograms enables Prometheus native histograms alongside classic buckets.
+	MetricsNativeHistograms bool
+
+	// OutboxDispatchers is how many events the bus dispatches concurrently.
+	OutboxDispatchers int
+	// OutboxEventConcurrency caps concurrently running handlers per event name.
+	// Format: OUTBOX_EVENT_CONCURRENCY="order.created=4,inventory.reserved=2".
+	OutboxEventConcurrency map[string]int
+	// OutboxGlobalConcurrency caps concurrently running handlers across all event names
+	// (0, the default, leaves them unbounded). Per-event limits apply first.
+	OutboxGlobalConcurrency int
+	// OutboxRequiredEvents lists event names that must have a subscriber at startup
+	// (OUTBOX_REQUIRED_EVENTS="order.created,inventory.reserved").
+	OutboxRequiredEvents []string
+	// OutboxNonBlockingPublish fails publishes on a full queue instead of waiting; with
+	// ORDER_PUBLISH_POLICY=required order creation then answers 503 with Retry-After.
+	// Validate rejects it under best_effort unless OUTBOX_TRANSACTIONAL is on.
+	OutboxNonBlockingPublish bool
+	// OutboxRetryAttempts is how many times a failing handler is tried in total (default 1,
+	// no retries; raise it only for idempotent handlers). Waits start at
+	// OutboxRetryBaseDelay and double up to OutboxRetryMaxDelay, with jitter.
+	OutboxRetryAttempts  int
+	OutboxRetryBaseDelay time.Duration
+	OutboxRetryMaxDelay  time.Duration
+	// OutboxRetryBudget caps handler retries across the bus per second (bursts up to the
+	// same number); beyond it failures skip retries and are dead-lettered. 0 (default)
+	// leaves retries unbudgeted.
+	OutboxRetryBudget int
+	// OutboxDeadLetters keeps the last N events whose handlers exhausted their retries
+	// (default 100, shown in /debug/snapshot); 0 disables the dead-letter sink.
+	OutboxDeadLetters int
+	// OutboxHandlerTimeout bounds one handler's delivery, retries included (default 10s).
+	// It must not exceed ShutdownGrace, or shutdown force-cancels handlers mid-flight.
+	OutboxHandlerTimeout time.Duration
+	// OutboxSequentialFanout runs each event's handlers one at a time in subscription order.
+	OutboxSequentialFanout bool
+	// OutboxStrictEvents rejects publishing or subscribing to event names the bus does not know.
+	OutboxStrictEvents bool
+
+	// InventoryLowStockThreshold is the global low-stock threshold (0 disables it).
+	InventoryLowStockThreshold int
+	// InventoryLowStockThresholds overrides the threshold per product; listed products
+	// also get their own inventory_low_stock_total label.
+	// Format: INVENTORY_LOW_STOCK_THRESHOLDS="sku-1=10,sku-2=3".
+	InventoryLowStockThresholds map[string]int
+	// InventoryStockGaugeProducts lists products whose post-reservation stock is reported
+	// on inventory_remaining_stock (INVENTORY_STOCK_GAUGE_PRODUCTS="sku-1,sku-2").
+	InventoryStockGaugeProducts []string
+	// InventoryReservationTTL releases reservations whose orders have not completed
+	// within the TTL (Go duration, e.g. "15m"); 0 (default) keeps them indefinitely.
+	InventoryReservationTTL time.Duration
+
+	// InventorySeedFile is an optional JSON file of [{"product_id", "quantity"}] loaded
+	// into stock at startup.
+	InventorySeedFile string
+
+	// InventoryAutoProvision creates unknown products with InventoryAutoProvisionQuantity
+	// stock on first reservation instead of failing the order. Demo only; off by default.
+	InventoryAutoProvision         bool
+	InventoryAutoProvisionQuantity int
+
+	// HTTPMaxInFlight caps concurrently handled API requests; excess ones get 503 with
+	// Retry-After. "auto" sizes it as 64 per GOMAXPROCS; 0 (default) disables the limit.
+	HTTPMaxInFlight int
+	// HTTPMaxBodyBytes caps JSON request bodies; larger ones get 413. Default 1 MiB.
+	HTTPMaxBodyBytes int
+
+	// DebugRecentEvents retains the last N bus events and serves them on
+	// GET /debug/events/recent on DebugAddr; 0 (default) disables both.
+	DebugRecentEvents int
+	// DebugSnapshot serves GET /debug/snapshot (bus, order and inventory state and recent
+	// handler error counts) on DebugAddr, never on the public listener.
+	DebugSnapshot bool
+	// DebugAddr is the operator-only listener for the /debug endpoints (default
+	// localhost:6060); empty disables them.
+	DebugAddr string
+
+	// WorkerSLA is the handling budget for every worker event; slower handlers count in
+	// worker_sla_violations_total. 0 (default) disables the check.
+	WorkerSLA time.Duration
+	// WorkerEventSLA overrides WorkerSLA per event name.
+	// Format: WORKER_EVENT_SLA="order.created=1s,inventory.reserved=500ms".
+	WorkerEventSLA map[string]time.Duration
+
+	// OrderPublishPolicy is "best_effort" (default) or "required"; with "required" a
+	// failed OrderCreated publish fails order creation.
+	OrderPublishPolicy string
+	// ShutdownGrace is how long shutdown waits for the HTTP server, bus and workers to
+	// drain before force-cancelling what is left (default 10s).
+	ShutdownGrace time.Duration
+
+	// OutboxFile, when set, replaces the in-memory bus with a file-backed outbox at that
+	// path: events are fsynced on publish and those not yet handled are redelivered after
+	// a restart. Local development only; the bus-only debug endpoints stay empty.
+	OutboxFile string
+	// OutboxMaxEventBytes caps an event's serialized size in durable transports (the file
+	// outbox); larger events are rejected at publish. Default 1 MiB.
+	OutboxMaxEventBytes int
+
+	// OutboxTransactional writes OrderCreated to a transactional outbox in the same unit
+	// of work as the order insert; a relay then publishes it to the bus.
+	OutboxTransactional bool
+}
+
+// Load reads the configuration from environment variables, applying defaults.
+func Load() (Config, error) {
+	cfg := Config{
+		ServiceName: getenvDefault("SERVICE_NAME", "minishop"),
+		Env:         getenvDefault("ENV", "dev"),
+		LogExporter: os.Getenv("LOG_EXPORTER"),
+
+		TraceExporter:         os.Getenv("TRACE_EXPORTER"),
+		TraceExporterEndpoint: os.Getenv("TRACE_EXPORTER_ENDPOINT"),
+		TraceExporterProtocol: getenvDefault("TRACE_EXPORTER_PROTOCOL", "http"),
+
+		MetricsBackend: getenvDefault("METRICS_BACKEND", "prometheus"),
+
+		InventorySeedFile: os.Getenv("INVENTORY_SEED_FILE"),
+		DebugAddr:         getenvDefault("DEBUG_ADDR", "localhost:6060"),
+		OutboxFile:        os.Getenv("OUTBOX_FILE"),
+	}
+	cfg.Version, cfg.Commit = buildVersion(os.Getenv("APP_VERSION"))
+
+	var err error
+	if cfg.LogFileMaxSizeMB, err = intEnv("LOG_FILE_MAX_SIZE_MB", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.LogFileMaxBackups, err = intEnv("LOG_FILE_MAX_BACKUPS", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.LogFileMaxAgeDays, err = intEnv("LOG_FILE_MAX_AGE_DAYS", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.LogFileCompress, err = boolEnv("LOG_FILE_COMPRESS", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.TraceSampler, cfg.TraceSamplerRatio, err = samplerEnv("OTEL_TRACES_SAMPLER", "OTEL_TRACES_SAMPLER_ARG"); err != nil {
+		return Config{}, err
+	}
+	if cfg.TracePropagateB3, err = boolEnv("TRACE_PROPAGATE_B3", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.MetricsExportInterval, err = durationEnv("METRICS_EXPORT_INTERVAL", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.MetricsNativeHistograms, err = boolEnv("METRICS_NATIVE_HISTOGRAMS", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxDispatchers, err = intEnv("OUTBOX_DISPATCHERS", 4); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxEventConcurrency, err = limitsEnv("OUTBOX_EVENT_CONCURRENCY"); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxGlobalConcurrency, err = intEnv("OUTBOX_GLOBAL_CONCURRENCY", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.InventoryLowStockThreshold, err = intEnv("INVENTORY_LOW_STOCK_THRESHOLD", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.InventoryLowStockThresholds, err = limitsEnv("INVENTORY_LOW_STOCK_THRESHOLDS"); err != nil {
+		return Config{}, err
+	}
+	if cfg.InventoryReservationTTL, err = durationEnv("INVENTORY_RESERVATION_TTL", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.InventoryAutoProvision, err = boolEnv("INVENTORY_AUTO_PROVISION", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.InventoryAutoProvisionQuantity, err = intEnv("INVENTORY_AUTO_PROVISION_QUANTITY", 100); err != nil {
+		return Config{}, err
+	}
+	if os.Getenv("HTTP_MAX_IN_FLIGHT") == "auto" {
+		cfg.HTTPMaxInFlight = 64 * runtime.GOMAXPROCS(0)
+	} else if cfg.HTTPMaxInFlight, err = intEnv("HTTP_MAX_IN_FLIGHT", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.HTTPMaxBodyBytes, err = intEnv("HTTP_MAX_BODY_BYTES", 1<<20); err != nil {
+		return Config{}, err
+	}
+	if cfg.DebugRecentEvents, err = intEnv("DEBUG_RECENT_EVENTS", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.DebugSnapshot, err = boolEnv("DEBUG_SNAPSHOT", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxNonBlockingPublish, err = boolEnv("OUTBOX_NONBLOCKING_PUBLISH", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxRetryAttempts, err = intEnv("OUTBOX_RETRY_ATTEMPTS", 1); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxRetryBaseDelay, err = durationEnv("OUTBOX_RETRY_BASE_DELAY", 100*time.Millisecond); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxRetryMaxDelay, err = durationEnv("OUTBOX_RETRY_MAX_DELAY", 2*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxRetryBudget, err = intEnv("OUTBOX_RETRY_BUDGET", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxDeadLetters, err = intEnv("OUTBOX_DEAD_LETTERS", 100); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxMaxEventBytes, err = intEnv("OUTBOX_MAX_EVENT_BYTES", 1<<20); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxHandlerTimeout, err = durationEnv("OUTBOX_HANDLER_TIMEOUT", 10*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxSequentialFanout, err = boolEnv("OUTBOX_SEQUENTIAL_FANOUT", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxStrictEvents, err = boolEnv("OUTBOX_STRICT_EVENTS", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxTransactional, err = boolEnv("OUTBOX_TRANSACTIONAL", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.ShutdownGrace, err = durationEnv("SHUTDOWN_GRACE", 10*time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.WorkerSLA, err = durationEnv("WORKER_SLA", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.WorkerEventSLA, err = durationsEnv("WORKER_EVENT_SLA"); err != nil {
+		return Config{}, err
+	}
+	cfg.TraceDebugTenants = listEnv("TRACE_DEBUG_TENANTS")
+	cfg.OutboxRequiredEvents = listEnv("OUTBOX_REQUIRED_EVENTS")
+	cfg.InventoryStockGaugeProducts = listEnv("INVENTORY_STOCK_GAUGE_PRODUCTS")
+	switch cfg.OrderPublishPolicy = getenvDefault("ORDER_PUBLISH_POLICY", "best_effort"); cfg.OrderPublishPolicy {
+	case "best_effort", "required":
+	default:
+		return Config{}, fmt.Errorf("config: ORDER_PUBLISH_POLICY: unknown policy %q", cfg.OrderPublishPolicy)
+	}
+	return cfg, nil
+}
+
+// buildVersion falls back to debug.ReadBuildInfo for whatever override leaves empty.
+func buildVersion(override string) (version, commit string) {
+	version, commit = override, "unknown"
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		if version == "" {
+			version = "unknown"
+		}
+		return version, commit
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" && s.Value != "" {
+			commit = s.Value
+		}
+	}
+	if version == "" {
+		version = info.Main.Version
+	}
+	if version == "" {
+		version = "unknown"
+	}
+	return version, commit
+}
+
+func getenvDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func intEnv(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// samplerEnv parses a sampler name (optionally "name:arg") and its ratio argument,
+// mapping the OpenTelemetry spec names onto the short ones.
+func samplerEnv(key, argKey string) (string, float64, error) {
+	name, arg, hasArg := strings.Cut(getenvDefault(key, "parentbased_ratio"), ":")
+	switch name {
+	case "always", "always_on":
+		name = "always"
+	case "never", "always_off":
+		name = "never"
+	case "ratio", "traceidratio":
+		name = "ratio"
+	case "parentbased_ratio", "parentbased_traceidratio":
+		name = "parentbased_ratio"
+	default:
+		return "", 0, fmt.Errorf("config: %s: unknown sampler %q", key, name)
+	}
+	if !hasArg {
+		arg = os.Getenv(argKey)
+	}
+	if arg == "" {
+		return name, 0.1, nil
+	}
+	ratio, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("config: %s: %w", argKey, err)
+	}
+	return name, ratio, nil
+}
+
+func durationEnv(key string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("config: %s: %w", key, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("config: %s: must not be negative", key)
+	}
+	return d, nil
+}
+
+func boolEnv(key string, def bool) (bool, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("config: %s: %w", key, err)
+	}
+	return b, nil
+}
+
+// listEnv parses a comma-separated list, dropping empty entries.
+func listEnv(key string) []string {
+	var out []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// limitsEnv parses "name=n,name=n" pairs.
+func limitsEnv(key string) (map[string]int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil, nil
+	}
+	out := make(map[string]int)
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("config: %s: expected name=limit, got %q", key, pair)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: %s: %w", key, name, err)
+		}
+		out[strings.TrimSpace(name)] = n
+	}
+	return out, nil
+}
+
+// durationsEnv parses "name=duration,name=duration" pairs.
+func durationsEnv(key string) (map[string]time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil, nil
+	}
+	out := make(map[string]time.Duration)
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("config: %s: expected name=duration, got %q", key, pair)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("config: %s: %s: %w", key, name, err)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("config: %s: %s: must not be negative", key, name)
+		}
+		out[strings.TrimSpace(name)] = d
+	}
+	return out, nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import "testing"
+
+func TestLoadDefaultsAreValid(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() on defaults = %v, want nil", err)
+	}
+	if cfg.OutboxMaxEventBytes != 1<<20 {
+		t.Errorf("OutboxMaxEventBytes = %d, want 1 MiB by default", cfg.OutboxMaxEventBytes)
+	}
+	if cfg.OutboxRetryAttempts != 1 {
+		t.Errorf("OutboxRetryAttempts = %d, want 1 (no retries) by default", cfg.OutboxRetryAttempts)
+	}
+}
+
+func TestLoadReadsMaxEventBytes(t *testing.T) {
+	t.Setenv("OUTBOX_MAX_EVENT_BYTES", "2048")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.OutboxMaxEventBytes != 2048 {
+		t.Errorf("OutboxMaxEventBytes = %d, want 2048", cfg.OutboxMaxEventBytes)
+	}
+}
+
+func TestLoadReadsTraceSampler(t *testing.T) {
+	tests := []struct {
+		name      string
+		sampler   string
+		arg       string
+		wantMode  string
+		wantRatio float64
+	}{
+		{"default", "", "", "parentbased_ratio", 0.1},
+		{"spec name", "always_off", "", "never", 0.1},
+		{"ratio from arg", "traceidratio", "0.5", "ratio", 0.5},
+		{"inline ratio wins over arg", "ratio:0.25", "0.5", "ratio", 0.25},
+		{"parent-based spec name", "parentbased_traceidratio", "1", "parentbased_ratio", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_TRACES_SAMPLER", tt.sampler)
+			t.Setenv("OTEL_TRACES_SAMPLER_ARG", tt.arg)
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.TraceSampler != tt.wantMode || cfg.TraceSamplerRatio != tt.wantRatio {
+				t.Errorf("sampler = %q ratio %v, want %q ratio %v",
+					cfg.TraceSampler, cfg.TraceSamplerRatio, tt.wantMode, tt.wantRatio)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsUnknownTraceSampler(t *testing.T) {
+	t.Setenv("OTEL_TRACES_SAMPLER", "sometimes")
+	if _, err := Load(); err == nil {
+		t.Error("Load() = nil error, want one for an unknown sampler")
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate checks value ranges and combinations that Load parses but cannot judge on
+// their own, returning every problem found joined into one error.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("config: %s: "+format, append([]any{key}, args...)...))
+		}
+	}
+
+	check(c.ServiceName != "", "SERVICE_NAME", "must not be empty")
+	check(c.LogFileMaxSizeMB >= 0, "LOG_FILE_MAX_SIZE_MB", "must not be negative, got %d", c.LogFileMaxSizeMB)
+	check(c.LogFileMaxBackups >= 0, "LOG_FILE_MAX_BACKUPS", "must not be negative, got %d", c.LogFileMaxBackups)
+	check(c.LogFileMaxAgeDays >= 0, "LOG_FILE_MAX_AGE_DAYS", "must not be negative, got %d", c.LogFileMaxAgeDays)
+
+	check(c.TraceExporter == "" || c.TraceExporter == "otlp", "TRACE_EXPORTER", "unknown exporter %q", c.TraceExporter)
+	check(c.TraceExporterProtocol == "http" || c.TraceExporterProtocol == "grpc",
+		"TRACE_EXPORTER_PROTOCOL", "must be http or grpc, got %q", c.TraceExporterProtocol)
+	check(c.TraceSamplerRatio >= 0 && c.TraceSamplerRatio <= 1, "OTEL_TRACES_SAMPLER_ARG", "must be between 0 and 1, got %g", c.TraceSamplerRatio)
+
+	check(c.MetricsBackend == "prometheus" || c.MetricsBackend == "otlp" || c.MetricsBackend == "stdout",
+		"METRICS_BACKEND", "must be prometheus, otlp or stdout, got %q", c.MetricsBackend)
+
+	check(c.OutboxDispatchers >= 1, "OUTBOX_DISPATCHERS", "must be at least 1, got %d", c.OutboxDispatchers)
+	for name, n := range c.OutboxEventConcurrency {
+		check(n >= 1, "OUTBOX_EVENT_CONCURRENCY", "%s: must be at least 1, got %d", name, n)
+	}
+	check(c.OutboxGlobalConcurrency >= 0, "OUTBOX_GLOBAL_CONCURRENCY", "must not be negative, got %d", c.OutboxGlobalConcurrency)
+	check(c.OutboxRetryAttempts >= 1, "OUTBOX_RETRY_ATTEMPTS", "must be at least 1, got %d", c.OutboxRetryAttempts)
+	check(c.OutboxRetryMaxDelay == 0 || c.OutboxRetryBaseDelay <= c.OutboxRetryMaxDelay,
+		"OUTBOX_RETRY_BASE_DELAY", "%s exceeds OUTBOX_RETRY_MAX_DELAY %s", c.OutboxRetryBaseDelay, c.OutboxRetryMaxDelay)
+	check(c.OutboxRetryBudget >= 0, "OUTBOX_RETRY_BUDGET", "must not be negative, got %d", c.OutboxRetryBudget)
+	check(c.OutboxHandlerTimeout > 0, "OUTBOX_HANDLER_TIMEOUT", "must be positive, got %s", c.OutboxHandlerTimeout)
+	check(c.ShutdownGrace > 0, "SHUTDOWN_GRACE", "must be positive, got %s", c.ShutdownGrace)
+	check(c.OutboxHandlerTimeout <= c.ShutdownGrace,
+		"OUTBOX_HANDLER_TIMEOUT", "%s exceeds SHUTDOWN_GRACE %s", c.OutboxHandlerTimeout, c.ShutdownGrace)
+	check(c.OutboxMaxEventBytes > 0, "OUTBOX_MAX_EVENT_BYTES", "must be positive, got %d", c.OutboxMaxEventBytes)
+	check(c.OutboxDeadLetters >= 0, "OUTBOX_DEAD_LETTERS", "must not be negative, got %d", c.OutboxDeadLetters)
+	// A best-effort OrderCreated shed by a full queue is lost while the client gets 201;
+	// only a required policy or the transactional outbox's relay gets it published.
+	check(!c.OutboxNonBlockingPublish || c.OrderPublishPolicy == "required" || c.OutboxTransactional,
+		"OUTBOX_NONBLOCKING_PUBLISH", "requires ORDER_PUBLISH_POLICY=required or OUTBOX_TRANSACTIONAL")
+
+	check(c.InventoryLowStockThreshold >= 0, "INVENTORY_LOW_STOCK_THRESHOLD", "must not be negative, got %d", c.InventoryLowStockThreshold)
+	for product, n := range c.InventoryLowStockThresholds {
+		check(n >= 0, "INVENTORY_LOW_STOCK_THRESHOLDS", "%s: must not be negative, got %d", product, n)
+	}
+	check(!c.InventoryAutoProvision || c.InventoryAutoProvisionQuantity > 0,
+		"INVENTORY_AUTO_PROVISION_QUANTITY", "must be positive when INVENTORY_AUTO_PROVISION is on, got %d", c.InventoryAutoProvisionQuantity)
+
+	check(c.HTTPMaxInFlight >= 0, "HTTP_MAX_IN_FLIGHT", "must not be negative, got %d", c.HTTPMaxInFlight)
+	check(c.HTTPMaxBodyBytes > 0, "HTTP_MAX_BODY_BYTES", "must be positive, got %d", c.HTTPMaxBodyBytes)
+	check(c.DebugRecentEvents >= 0, "DEBUG_RECENT_EVENTS", "must not be negative, got %d", c.DebugRecentEvents)
+	check(!c.DebugSnapshot || c.DebugAddr != "", "DEBUG_ADDR", "must be set when DEBUG_SNAPSHOT is on")
+	check(c.DebugRecentEvents == 0 || c.DebugAddr != "", "DEBUG_ADDR", "must be set when DEBUG_RECENT_EVENTS is on")
+	return errors.Join(errs...)
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig passes Validate; each case below breaks exactly one rule.
+func validConfig() Config {
+	return Config{
+		ServiceName:                    "minishop",
+		TraceExporterProtocol:          "http",
+		TraceSamplerRatio:              0.1,
+		MetricsBackend:                 "prometheus",
+		OutboxDispatchers:              4,
+		OutboxRetryAttempts:            3,
+		OutboxRetryBaseDelay:           100 * time.Millisecond,
+		OutboxRetryMaxDelay:            2 * time.Second,
+		OutboxHandlerTimeout:           10 * time.Second,
+		ShutdownGrace:                  10 * time.Second,
+		InventoryAutoProvisionQuantity: 100,
+		HTTPMaxBodyBytes:               1 << 20,
+		OutboxMaxEventBytes:            1 << 20,
+	}
+}
+
+func TestValidateAcceptsDefaults(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+}
+
+func TestValidateRejectsEachInvalidSetting(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		key    string
+	}{
+		{"empty service name", func(c *Config) { c.ServiceName = "" }, "SERVICE_NAME"},
+		{"negative log file size", func(c *Config) { c.LogFileMaxSizeMB = -1 }, "LOG_FILE_MAX_SIZE_MB"},
+		{"negative log file backups", func(c *Config) { c.LogFileMaxBackups = -1 }, "LOG_FILE_MAX_BACKUPS"},
+		{"negative log file age", func(c *Config) { c.LogFileMaxAgeDays = -1 }, "LOG_FILE_MAX_AGE_DAYS"},
+		{"unknown trace exporter", func(c *Config) { c.TraceExporter = "jaeger" }, "TRACE_EXPORTER"},
+		{"unknown trace protocol", func(c *Config) { c.TraceExporterProtocol = "udp" }, "TRACE_EXPORTER_PROTOCOL"},
+		{"sampler ratio above 1", func(c *Config) { c.TraceSamplerRatio = 1.5 }, "OTEL_TRACES_SAMPLER_ARG"},
+		{"sampler ratio below 0", func(c *Config) { c.TraceSamplerRatio = -0.1 }, "OTEL_TRACES_SAMPLER_ARG"},
+		{"unknown metrics backend", func(c *Config) { c.MetricsBackend = "statsd" }, "METRICS_BACKEND"},
+		{"no dispatchers", func(c *Config) { c.OutboxDispatchers = 0 }, "OUTBOX_DISPATCHERS"},
+		{"zero event concurrency", func(c *Config) { c.OutboxEventConcurrency = map[string]int{"order.created": 0} }, "OUTBOX_EVENT_CONCURRENCY"},
+		{"negative global concurrency", func(c *Config) { c.OutboxGlobalConcurrency = -1 }, "OUTBOX_GLOBAL_CONCURRENCY"},
+		{"no retry attempts", func(c *Config) { c.OutboxRetryAttempts = 0 }, "OUTBOX_RETRY_ATTEMPTS"},
+		{"retry base above max", func(c *Config) { c.OutboxRetryBaseDelay = 3 * time.Second }, "OUTBOX_RETRY_BASE_DELAY"},
+		{"negative retry budget", func(c *Config) { c.OutboxRetryBudget = -1 }, "OUTBOX_RETRY_BUDGET"},
+		{"zero handler timeout", func(c *Config) { c.OutboxHandlerTimeout = 0 }, "OUTBOX_HANDLER_TIMEOUT"},
+		{"zero shutdown grace", func(c *Config) { c.ShutdownGrace = 0; c.OutboxHandlerTimeout = 0 }, "SHUTDOWN_GRACE"},
+		{"handler timeout beyond shutdown grace", func(c *Config) { c.OutboxHandlerTimeout = 30 * time.Second }, "exceeds SHUTDOWN_GRACE"},
+		{"zero max event size", func(c *Config) { c.OutboxMaxEventBytes = 0 }, "OUTBOX_MAX_EVENT_BYTES"},
+		{"negative dead letters", func(c *Config) { c.OutboxDeadLetters = -1 }, "OUTBOX_DEAD_LETTERS"},
+		{"non-blocking publish with best effort", func(c *Config) {
+			c.OutboxNonBlockingPublish = true
+			c.OrderPublishPolicy = "best_effort"
+		}, "OUTBOX_NONBLOCKING_PUBLISH"},
+		{"negative low stock threshold", func(c *Config) { c.InventoryLowStockThreshold = -1 }, "INVENTORY_LOW_STOCK_THRESHOLD"},
+		{"negative product threshold", func(c *Config) { c.InventoryLowStockThresholds = map[string]int{"sku-1": -1} }, "INVENTORY_LOW_STOCK_THRESHOLDS"},
+		{"auto provision without quantity", func(c *Config) {
+			c.InventoryAutoProvision = true
+			c.InventoryAutoProvisionQuantity = 0
+		}, "INVENTORY_AUTO_PROVISION_QUANTITY"},
+		{"negative max in flight", func(c *Config) { c.HTTPMaxInFlight = -1 }, "HTTP_MAX_IN_FLIGHT"},
+		{"zero max body", func(c *Config) { c.HTTPMaxBodyBytes = 0 }, "HTTP_MAX_BODY_BYTES"},
+		{"negative recent events", func(c *Config) { c.DebugRecentEvents = -1 }, "DEBUG_RECENT_EVENTS"},
+		{"snapshot without debug listener", func(c *Config) {
+			c.DebugSnapshot = true
+			c.DebugAddr = ""
+		}, "DEBUG_ADDR"},
+		{"recent events without debug listener", func(c *Config) {
+			c.DebugRecentEvents = 10
+			c.DebugAddr = ""
+		}, "DEBUG_ADDR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.mutate(&c)
+			err := c.Validate()
+			if err == nil {
+				t.Fatal("Validate() = nil, want an error")
+			}
+			if !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("Validate() = %q, want it to name %s", err, tt.key)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	c := validConfig()
+	c.ServiceName = ""
+	c.OutboxDispatchers = 0
+	c.HTTPMaxBodyBytes = 0
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want an error")
+	}
+	for _, key := range []string{"SERVICE_NAME", "OUTBOX_DISPATCHERS", "HTTP_MAX_BODY_BYTES"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("Validate() = %q, missing %s", err, key)
+		}
+	}
+}
+
+func TestValidateAcceptsNonBlockingPublishThatKeepsOrderCreated(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+	}{
+		{"required policy", func(c *Config) { c.OrderPublishPolicy = "required" }},
+		{"transactional outbox", func(c *Config) {
+			c.OrderPublishPolicy = "best_effort"
+			c.OutboxTransactional = true
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			c.OutboxNonBlockingPublish = true
+			tt.mutate(&c)
+			if err := c.Validate(); err != nil {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time to domain code.
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the production clock: wall time in UTC.
+type System struct{}
+
+func (System) Now() time.Time { return time.Now().UTC() }
+
+var (
+	mu      sync.RWMutex
+	current Clock = System{}
+)
+
+// Now returns the time from the package clock (System unless replaced via Set).
+func Now() time.Time {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.Now()
+}
+
+// Set replaces the package clock and returns a func restoring the previous one.
+func Set(c Clock) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	prev := current
+	current = c
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		current = prev
+	}
+}
+
+// Frozen is a Clock that only moves when told to; use it for deterministic tests.
+type Frozen struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (f *Frozen) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.t
+}
+
+// Advance moves the frozen time forward by d.
+func (f *Frozen) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.t = f.t.Add(d)
+}
+
+// Freeze installs a Frozen clock at t as the package clock, e.g.
+//
+//	fc, restore := clock.Freeze(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	defer restore()
+func Freeze(t time.Time) (*Frozen, func()) {
+	f := &Frozen{t: t.UTC()}
+	return f, Set(f)
+}
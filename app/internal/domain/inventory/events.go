@@ -1,11 +1,17 @@
 package inventory
 
-import "time"
+import (
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/domain/clock"
+)
 
 const (
 	FailureReasonNotFound          = "not_found"
 	FailureReasonInsufficientStock = "insufficient_stock"
 	FailureReasonPersistenceError  = "persist_error"
+	FailureReasonInvariant         = "invariant_violation"
+	FailureReasonExpired           = "reservation_expired"
 )
 
 // InventoryReservedEvent is emitted when stock is successfully reserved for an order.
@@ -23,7 +29,7 @@ func NewInventoryReservedEvent(orderID, productID string, quantity int) Inventor
 		OrderID:    orderID,
 		ProductID:  productID,
 		Quantity:   quantity,
-		OccurredAt: time.Now().UTC(),
+		OccurredAt: clock.Now(),
 	}
 }
 
@@ -44,6 +50,47 @@ func NewInventoryReservationFailedEvent(orderID, productID string, quantity int,
 		ProductID:  productID,
 		Quantity:   quantity,
 		Reason:     reason,
-		OccurredAt: time.Now().UTC(),
+		OccurredAt: clock.Now(),
+	}
+}
+
+// InventoryLowStockEvent is emitted when a reservation leaves a product's stock below its threshold.
+type InventoryLowStockEvent struct {
+	ProductID  string
+	Remaining  int
+	Threshold  int
+	OccurredAt time.Time
+}
+
+func (InventoryLowStockEvent) EventName() string { return "inventory.low_stock" }
+
+func NewInventoryLowStockEvent(productID string, remaining, threshold int) InventoryLowStockEvent {
+	return InventoryLowStockEvent{
+		ProductID:  productID,
+		Remaining:  remaining,
+		Threshold:  threshold,
+		OccurredAt: clock.Now(),
+	}
+}
+
+// InventoryReservationExpiredEvent is emitted when an unsettled reservation outlives its TTL
+// and its stock is returned.
+type InventoryReservationExpiredEvent struct {
+	OrderID    string
+	ProductID  string
+	Quantity   int
+	ReservedAt time.Time
+	OccurredAt time.Time
+}
+
+func (InventoryReservationExpiredEvent) EventName() string { return "inventory.reservation_expired" }
+
+func NewInventoryReservationExpiredEvent(r Reservation) InventoryReservationExpiredEvent {
+	return InventoryReservationExpiredEvent{
+		OrderID:    r.OrderID,
+		ProductID:  r.ProductID,
+		Quantity:   r.Quantity,
+		ReservedAt: r.ReservedAt,
+		OccurredAt: clock.Now(),
 	}
 }
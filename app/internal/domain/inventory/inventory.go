@@ -9,6 +9,15 @@ var (
 	ErrNotFound          = errors.New("inventory: product not found")
 	ErrInvalidQuantity   = errors.New("inventory: quantity must be greater than zero")
 	ErrInsufficientStock = errors.New("inventory: insufficient stock")
+	// ErrInvariantViolation means an operation would corrupt stock (e.g. drive it negative).
+	// It always indicates a bug upstream and is rejected rather than applied.
+	ErrInvariantViolation = errors.New("inventory: invariant violation")
+	// ErrReservationNotFound means no reservation is tracked for the order.
+	ErrReservationNotFound = errors.New("inventory: reservation not found")
+	// ErrAlreadyReserved means the order already holds its reservation, e.g. on event redelivery.
+	ErrAlreadyReserved = errors.New("inventory: already reserved for order")
+	// ErrInvalidStock rejects a seeded stock level without a product ID or below zero.
+	ErrInvalidStock = errors.New("inventory: stock needs a product id and a non-negative quantity")
 )
 
 type Item struct {
@@ -2,8 +2,69 @@ package inventory
 
 import (
 	"context"
+	"fmt"
+	"time"
 )
 
 type Repository interface {
-	Reserve(ctx context.Context, productID string, quantity int) error
+	// Reserve deducts quantity for operationID and returns the stock remaining afterwards.
+	// operationID is the order's OperationID (its idempotency key, else its ID). Reserve
+	// deducts at most once per operation: a repeat returns ErrAlreadyReserved and the
+	// current stock without deducting again. An empty operationID disables that check.
+	Reserve(ctx context.Context, operationID, productID string, quantity int) (remaining int, err error)
+}
+
+// StockReader exposes read-only stock lookups for projections such as the saga view.
+type StockReader interface {
+	Available(ctx context.Context, productID string) (int, error)
+}
+
+// Stock is the absolute on-hand quantity of a product, as set by seeding.
+type Stock struct {
+	ProductID string
+	Quantity  int
+}
+
+// Validate reports ErrInvalidStock for an empty product ID or a negative quantity.
+func (s Stock) Validate() error {
+	if s.ProductID == "" || s.Quantity < 0 {
+		return fmt.Errorf("%w: product %q quantity %d", ErrInvalidStock, s.ProductID, s.Quantity)
+	}
+	return nil
+}
+
+// Seeder sets stock levels in bulk for bootstrap and demos.
+type Seeder interface {
+	// SeedMany sets every listed product's stock, all or nothing, and returns how many
+	// distinct products the repository holds afterwards.
+	SeedMany(ctx context.Context, stock []Stock) (products int, err error)
+}
+
+// Provisioner creates unknown products on demand (demo auto-provisioning).
+type Provisioner interface {
+	// Provision stocks productID with quantity unless it already exists; created reports
+	// whether this call added it, so concurrent callers provision it only once.
+	Provision(ctx context.Context, productID string, quantity int) (created bool, err error)
+}
+
+// Reservation is stock held for an order until the order settles it or its TTL expires.
+type Reservation struct {
+	OrderID    string
+	ProductID  string
+	Quantity   int
+	ReservedAt time.Time
+}
+
+// ReservationRepository tracks reservations by order so abandoned ones can be released.
+type ReservationRepository interface {
+	// Track records a reservation made by Reserve.
+	Track(ctx context.Context, r Reservation) error
+	// Get returns the tracked reservation for orderID or ErrReservationNotFound.
+	Get(ctx context.Context, orderID string) (Reservation, error)
+	// ReservedBefore lists tracked reservations made before cutoff.
+	ReservedBefore(ctx context.Context, cutoff time.Time) ([]Reservation, error)
+	// Release returns the reserved stock to its product and forgets the reservation.
+	Release(ctx context.Context, orderID string) (Reservation, error)
+	// Forget drops the reservation without returning stock, because the order consumed it.
+	Forget(ctx context.Context, orderID string) error
 }
@@ -1,6 +1,10 @@
 package order
 
-import "time"
+import (
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/domain/clock"
+)
 
 // OrderCreatedEvent is a domain event emitted when a new order is created.
 // It is intended to be handled by other bounded contexts (e.g., Inventory).
@@ -10,34 +14,55 @@ type OrderCreatedEvent struct {
 	ProductID  string
 	Quantity   int
 	Amount     int64
-	OccurredAt time.Time
+	// IdempotencyKey is the client key the order was created with, if any.
+	IdempotencyKey string
+	OccurredAt     time.Time
 }
 
 func (OrderCreatedEvent) EventName() string { return "order.created" }
 
+// OperationID identifies the business operation behind the event: the idempotency key
+// when the client supplied one, otherwise the order ID. Consumers dedup on it so the
+// whole saga keys on the same value as order creation.
+func (e OrderCreatedEvent) OperationID() string {
+	if e.IdempotencyKey != "" {
+		return e.IdempotencyKey
+	}
+	return e.OrderID
+}
+
 func NewOrderCreatedEvent(o *Order) OrderCreatedEvent {
 	return OrderCreatedEvent{
-		OrderID:    o.ID,
-		CustomerID: o.CustomerID,
-		ProductID:  o.ProductID,
-		Quantity:   o.Quantity,
-		Amount:     o.Amount,
-		OccurredAt: time.Now().UTC(),
+		OrderID:        o.ID,
+		CustomerID:     o.CustomerID,
+		ProductID:      o.ProductID,
+		Quantity:       o.Quantity,
+		Amount:         o.Amount,
+		IdempotencyKey: o.IdempotencyKey,
+		OccurredAt:     clock.Now(),
 	}
 }
 
 // OrderInventoryReservedEvent is emitted when inventory reservation succeeds for an order.
+// Amount and CustomerID are carried so payment does not depend on re-reading the order for them;
+// IdempotencyKey carries the order's client key further down the saga.
 type OrderInventoryReservedEvent struct {
-	OrderID    string
-	OccurredAt time.Time
+	OrderID        string
+	CustomerID     string
+	Amount         int64
+	IdempotencyKey string
+	OccurredAt     time.Time
 }
 
 func (OrderInventoryReservedEvent) EventName() string { return "order.inventory_reserved" }
 
 func NewOrderInventoryReservedEvent(o *Order) OrderInventoryReservedEvent {
 	return OrderInventoryReservedEvent{
-		OrderID:    o.ID,
-		OccurredAt: time.Now().UTC(),
+		OrderID:        o.ID,
+		CustomerID:     o.CustomerID,
+		Amount:         o.Amount,
+		IdempotencyKey: o.IdempotencyKey,
+		OccurredAt:     clock.Now(),
 	}
 }
 
@@ -54,6 +79,6 @@ func NewOrderInventoryReservationFailedEvent(o *Order, reason string) OrderInven
 	return OrderInventoryReservationFailedEvent{
 		OrderID:    o.ID,
 		Reason:     reason,
-		OccurredAt: time.Now().UTC(),
+		OccurredAt: clock.Now(),
 	}
 }
@@ -3,6 +3,8 @@ package order
 import (
 	"errors"
 	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/domain/clock"
 )
 
 var (
@@ -12,6 +14,8 @@ var (
 	ErrInvalidStateTransition = errors.New("order: invalid state transition")
 	ErrInvalidStatus          = errors.New("order: invalid status")
 	ErrConflict               = errors.New("order: conflict")
+	// ErrVersionConflict means the order changed since it was loaded; reload and retry.
+	ErrVersionConflict = errors.New("order: version conflict")
 )
 
 type Status string
@@ -24,6 +28,17 @@ const (
 	StatusPaymentFailed     Status = "payment_failed"
 )
 
+// Terminal reports whether the saga is over for an order in status s: it either
+// completed or failed, and no pending step will consume its reserved stock.
+func (s Status) Terminal() bool {
+	switch s {
+	case StatusCompleted, StatusInventoryFailed, StatusPaymentFailed:
+		return true
+	default:
+		return false
+	}
+}
+
 type Order struct {
 	ID             string
 	CustomerID     string
@@ -35,6 +50,8 @@ type Order struct {
 	FailureReason  string
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	// Version is bumped by the repository on every successful Update (optimistic concurrency).
+	Version int64
 
 	state OrderState
 }
@@ -47,7 +64,7 @@ func New(id, customerID, productID, idempotencyKey string, quantity int, amount
 		return nil, ErrInvalidAmount
 	}
 
-	now := time.Now().UTC()
+	now := clock.Now()
 	order := &Order{
 		ID:             id,
 		CustomerID:     customerID,
@@ -97,6 +114,14 @@ func (o *Order) PaymentFailed(reason string) error {
 	return o.transition(next, err)
 }
 
+// ReservationExpired records that the reserved stock was released because the order
+// did not settle in time; the order ends as inventory_failed with reason.
+func (o *Order) ReservationExpired(reason string) error {
+	o.ensureState()
+	next, err := o.state.OnReservationExpired(o, reason)
+	return o.transition(next, err)
+}
+
 func (o *Order) CanProcessPayment() bool {
 	switch o.Status {
 	case StatusInventoryReserved, StatusPaymentFailed:
@@ -137,6 +162,7 @@ func (o *Order) ensureState() {
 	}
 }
 
+// touch stamps UpdatedAt from the domain clock so tests can freeze it (clock.Freeze).
 func (o *Order) touch() {
-	o.UpdatedAt = time.Now().UTC()
+	o.UpdatedAt = clock.Now()
 }
@@ -1,10 +1,30 @@
 package order
 
-import "context"
+import (
+	"context"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+)
 
 type Repository interface {
 	Insert(ctx context.Context, order *Order) error
 	Get(ctx context.Context, id string) (*Order, error)
+	// Update fails with ErrVersionConflict when order.Version no longer matches the stored
+	// version; on success the version is incremented on both the stored and passed order.
 	Update(ctx context.Context, order *Order) error
 	FindByIdempotency(ctx context.Context, customerID, key string) (*Order, error)
 }
+
+// Tx is the transactional view handed to a UnitOfWork callback. Writes made
+// through it become visible only when the unit of work commits.
+type Tx interface {
+	Insert(ctx context.Context, order *Order) error
+	// Enqueue stores e in the transactional outbox; a relay publishes it after commit.
+	Enqueue(ctx context.Context, e outbox.Event) error
+}
+
+// UnitOfWork commits an order write and the events it produced atomically, so a
+// crash can never leave an order without its event (or an event without its order).
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(tx Tx) error) error
+}
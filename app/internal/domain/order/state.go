@@ -7,6 +7,7 @@ type OrderState interface {
 	OnInventoryFailed(o *Order, reason string) (OrderState, error)
 	OnPaymentSucceeded(o *Order) (OrderState, error)
 	OnPaymentFailed(o *Order, reason string) (OrderState, error)
+	OnReservationExpired(o *Order, reason string) (OrderState, error)
 }
 
 type pendingState struct{}
@@ -31,6 +32,10 @@ func (pendingState) OnPaymentFailed(*Order, string) (OrderState, error) {
 	return nil, ErrInvalidStateTransition
 }
 
+func (pendingState) OnReservationExpired(*Order, string) (OrderState, error) {
+	return nil, ErrInvalidStateTransition
+}
+
 type inventoryReservedState struct{}
 
 func (inventoryReservedState) Status() Status { return StatusInventoryReserved }
@@ -54,6 +59,11 @@ func (inventoryReservedState) OnPaymentFailed(o *Order, reason string) (OrderSta
 	return paymentFailedState{}, nil
 }
 
+func (inventoryReservedState) OnReservationExpired(o *Order, reason string) (OrderState, error) {
+	o.FailureReason = reason
+	return inventoryFailedState{}, nil
+}
+
 type inventoryFailedState struct{}
 
 func (inventoryFailedState) Status() Status { return StatusInventoryFailed }
@@ -75,6 +85,11 @@ func (inventoryFailedState) OnPaymentFailed(*Order, string) (OrderState, error)
 	return nil, ErrInvalidStateTransition
 }
 
+func (inventoryFailedState) OnReservationExpired(o *Order, reason string) (OrderState, error) {
+	o.FailureReason = reason
+	return inventoryFailedState{}, nil
+}
+
 type completedState struct{}
 
 func (completedState) Status() Status { return StatusCompleted }
@@ -95,6 +110,10 @@ func (completedState) OnPaymentFailed(*Order, string) (OrderState, error) {
 	return nil, ErrInvalidStateTransition
 }
 
+func (completedState) OnReservationExpired(*Order, string) (OrderState, error) {
+	return nil, ErrInvalidStateTransition
+}
+
 type paymentFailedState struct{}
 
 func (paymentFailedState) Status() Status { return StatusPaymentFailed }
@@ -116,3 +135,8 @@ func (paymentFailedState) OnPaymentFailed(o *Order, reason string) (OrderState,
 	o.FailureReason = reason
 	return paymentFailedState{}, nil
 }
+
+func (paymentFailedState) OnReservationExpired(o *Order, reason string) (OrderState, error) {
+	o.FailureReason = reason
+	return inventoryFailedState{}, nil
+}
@@ -0,0 +1,30 @@
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+// Envelope carries an Event through a bus together with the metadata every event
+// shares, so dedup and trace propagation do not depend on individual event types.
+type Envelope struct {
+	EventID    string
+	OccurredAt time.Time // when the event was published
+	// TraceContext holds the publisher's propagated trace headers (W3C traceparent,
+	// tracestate and baggage), letting consumers continue the producer's trace.
+	TraceContext map[string]string
+	Event        Event
+}
+
+type envelopeKey struct{}
+
+// WithEnvelope stores env in ctx for the handlers it is delivered to.
+func WithEnvelope(ctx context.Context, env Envelope) context.Context {
+	return context.WithValue(ctx, envelopeKey{}, env)
+}
+
+// EnvelopeFrom returns the envelope of the event being handled, if any.
+func EnvelopeFrom(ctx context.Context) (Envelope, bool) {
+	env, ok := ctx.Value(envelopeKey{}).(Envelope)
+	return env, ok
+}
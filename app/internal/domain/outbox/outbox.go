@@ -1,6 +1,22 @@
 package outbox
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrUnknownEvent is returned by publishers that restrict events to a known set.
+	ErrUnknownEvent = errors.New("outbox: unknown event name")
+	// ErrBusStopped is returned when publishing after the bus has been stopped.
+	ErrBusStopped = errors.New("outbox: bus stopped")
+	// ErrQueueFull is returned by non-blocking publishers when the queue has no room;
+	// callers should shed load rather than retry immediately.
+	ErrQueueFull = errors.New("outbox: queue full")
+	// ErrEventTooLarge is returned by durable publishers when an event's serialized size
+	// exceeds their limit. It is permanent: publishing the same event again will fail too.
+	ErrEventTooLarge = errors.New("outbox: event too large")
+)
 
 // Event is any domain event with a name identifier.
 type Event interface {
@@ -15,7 +31,32 @@ type Publisher interface {
 	Publish(ctx context.Context, e Event) error
 }
 
+// SubscriptionToken identifies one registered handler so it can be unsubscribed.
+type SubscriptionToken uint64
+
 // Subscriber registers handlers for event names.
 type Subscriber interface {
-	Subscribe(eventName string, h Handler)
+	Subscribe(eventName string, h Handler) SubscriptionToken
+	// SubscribeNamed registers h under a human-readable name used to identify it in logs, metrics and spans.
+	SubscribeNamed(name, eventName string, h Handler) SubscriptionToken
+	// Unsubscribe removes the handler registered under token; events already being
+	// delivered to it are not interrupted. Unknown tokens are ignored.
+	Unsubscribe(eventName string, token SubscriptionToken)
+}
+
+// Record is an event persisted to the transactional outbox, awaiting relay.
+type Record struct {
+	ID    uint64
+	Event Event
+	// TraceContext is the enqueuing request's propagated trace context, so the relay can
+	// publish the event into the trace that produced it.
+	TraceContext map[string]string
+}
+
+// Store is the durable side of the transactional outbox. Records are appended in the
+// same transaction as the state change that produced them and removed once relayed.
+type Store interface {
+	// Pending returns up to limit unpublished records, oldest first.
+	Pending(ctx context.Context, limit int) ([]Record, error)
+	MarkPublished(ctx context.Context, ids ...uint64) error
 }
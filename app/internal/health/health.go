@@ -0,0 +1,99 @@
+// Package health tracks whether the long-lived components are ready to serve, for the
+// /ready probe. Components register when constructed and flip their flag as they start
+// and stop; the process is ready only when every registered component is.
+package health
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// State holds one readiness flag per component. A nil *State ignores every call, so
+// components can take it as an optional dependency. The zero value is ready to use.
+type State struct {
+	mu         sync.RWMutex
+	components map[string]*atomic.Bool
+	draining   atomic.Bool
+}
+
+// Component is one entry of a readiness report.
+type Component struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+}
+
+// Register declares name as not ready yet. Registering it again keeps its flag.
+func (s *State) Register(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.components == nil {
+		s.components = make(map[string]*atomic.Bool)
+	}
+	if _, ok := s.components[name]; !ok {
+		s.components[name] = new(atomic.Bool)
+	}
+}
+
+// Set marks name ready or not, registering it if needed.
+func (s *State) Set(name string, ready bool) {
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	flag, ok := s.components[name]
+	s.mu.RUnlock()
+	if !ok {
+		s.Register(name)
+		s.mu.RLock()
+		flag = s.components[name]
+		s.mu.RUnlock()
+	}
+	flag.Store(ready)
+}
+
+// Drain reports the process as not ready from now on, whatever its components say, so
+// load balancers stop routing to it while shutdown runs.
+func (s *State) Drain() {
+	if s == nil {
+		return
+	}
+	s.draining.Store(true)
+}
+
+// Ready reports whether shutdown has not begun and every registered component is ready.
+func (s *State) Ready() bool {
+	if s == nil {
+		return false
+	}
+	if s.draining.Load() {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, flag := range s.components {
+		if !flag.Load() {
+			return false
+		}
+	}
+	return true
+}
+
+// Components lists every registered component with its flag, sorted by name.
+func (s *State) Components() []Component {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	out := make([]Component, 0, len(s.components))
+	for name, flag := range s.components {
+		out = append(out, Component{Name: name, Ready: flag.Load()})
+	}
+	s.mu.RUnlock()
+	slices.SortFunc(out, func(a, b Component) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -9,24 +10,28 @@ import (
 )
 
 type InventoryRepository struct {
-	mu    sync.Mutex
-	items map[string]*domain.Item
+	mu           sync.Mutex
+	items        map[string]*domain.Item
+	reservations map[string]domain.Reservation // by order ID
+	reserved     map[string]struct{}           // operation IDs Reserve has deducted for
 }
 
 func NewInventoryRepository() *InventoryRepository {
 	return &InventoryRepository{
-		items: make(map[string]*domain.Item),
+		items:        make(map[string]*domain.Item),
+		reservations: make(map[string]domain.Reservation),
+		reserved:     make(map[string]struct{}),
 	}
 }
 
-func (r *InventoryRepository) Reserve(ctx context.Context, productID string, quantity int) error {
+func (r *InventoryRepository) Reserve(ctx context.Context, operationID, productID string, quantity int) (int, error) {
 	_ = ctx
 
 	if productID == "" {
-		return domain.ErrNotFound
+		return 0, domain.ErrNotFound
 	}
 	if quantity <= 0 {
-		return domain.ErrInvalidQuantity
+		return 0, domain.ErrInvalidQuantity
 	}
 
 	r.mu.Lock()
@@ -34,15 +39,47 @@ func (r *InventoryRepository) Reserve(ctx context.Context, productID string, qua
 
 	item, ok := r.items[productID]
 	if !ok {
-		return domain.ErrNotFound
+		return 0, domain.ErrNotFound
+	}
+	if _, dup := r.reserved[operationID]; dup && operationID != "" {
+		return item.Quantity, domain.ErrAlreadyReserved
+	}
+	if item.Quantity < 0 {
+		return item.Quantity, fmt.Errorf("%w: product %s has negative stock %d", domain.ErrInvariantViolation, productID, item.Quantity)
 	}
 	if quantity > item.Quantity {
-		return domain.ErrInsufficientStock
+		return item.Quantity, domain.ErrInsufficientStock
+	}
+	if next := item.Quantity - quantity; next < 0 {
+		// Unreachable with the check above; guards future mutation paths (release, compensation).
+		return item.Quantity, fmt.Errorf("%w: reserving %d of %s would leave %d", domain.ErrInvariantViolation, quantity, productID, next)
 	}
 
 	item.Quantity -= quantity
 	item.UpdatedAt = time.Now().UTC()
-	return nil
+	if operationID != "" {
+		r.reserved[operationID] = struct{}{}
+	}
+	return item.Quantity, nil
+}
+
+// Available returns the current stock of productID.
+func (r *InventoryRepository) Available(ctx context.Context, productID string) (int, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.items[productID]
+	if !ok {
+		return 0, domain.ErrNotFound
+	}
+	return item.Quantity, nil
+}
+
+// Products returns how many distinct products are stocked.
+func (r *InventoryRepository) Products() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.items)
 }
 
 // Seed allows tests or bootstrap code to populate inventory quantities directly.
@@ -55,3 +92,110 @@ func (r *InventoryRepository) Seed(productID string, quantity int) {
 		UpdatedAt: time.Now().UTC(),
 	}
 }
+
+// SeedMany validates every entry before applying any, so a bad entry leaves stock untouched.
+// A product listed twice ends up with its last quantity.
+func (r *InventoryRepository) SeedMany(ctx context.Context, stock []domain.Stock) (int, error) {
+	_ = ctx
+	for _, s := range stock {
+		if err := s.Validate(); err != nil {
+			return 0, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UTC()
+	for _, s := range stock {
+		r.items[s.ProductID] = &domain.Item{
+			ProductID: s.ProductID,
+			Quantity:  s.Quantity,
+			UpdatedAt: now,
+		}
+	}
+	return len(r.items), nil
+}
+
+func (r *InventoryRepository) Provision(ctx context.Context, productID string, quantity int) (bool, error) {
+	_ = ctx
+	if err := (domain.Stock{ProductID: productID, Quantity: quantity}).Validate(); err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[productID]; ok {
+		return false, nil
+	}
+	r.items[productID] = &domain.Item{
+		ProductID: productID,
+		Quantity:  quantity,
+		UpdatedAt: time.Now().UTC(),
+	}
+	return true, nil
+}
+
+// Track records a reservation; a repeated order ID replaces the earlier entry.
+func (r *InventoryRepository) Track(ctx context.Context, res domain.Reservation) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reservations[res.OrderID] = res
+	return nil
+}
+
+// Get returns the reservation tracked for orderID.
+func (r *InventoryRepository) Get(ctx context.Context, orderID string) (domain.Reservation, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.reservations[orderID]
+	if !ok {
+		return domain.Reservation{}, domain.ErrReservationNotFound
+	}
+	return res, nil
+}
+
+// ReservedBefore lists reservations made before cutoff.
+func (r *InventoryRepository) ReservedBefore(ctx context.Context, cutoff time.Time) ([]domain.Reservation, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.Reservation
+	for _, res := range r.reservations {
+		if res.ReservedAt.Before(cutoff) {
+			out = append(out, res)
+		}
+	}
+	return out, nil
+}
+
+// Release returns the reservation's stock to its product and forgets it.
+func (r *InventoryRepository) Release(ctx context.Context, orderID string) (domain.Reservation, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.reservations[orderID]
+	if !ok {
+		return domain.Reservation{}, domain.ErrReservationNotFound
+	}
+	item, ok := r.items[res.ProductID]
+	if !ok {
+		return res, fmt.Errorf("%w: release for order %s", domain.ErrNotFound, orderID)
+	}
+	item.Quantity += res.Quantity
+	item.UpdatedAt = time.Now().UTC()
+	delete(r.reservations, orderID)
+	return res, nil
+}
+
+// Forget drops the reservation without returning its stock.
+func (r *InventoryRepository) Forget(ctx context.Context, orderID string) error {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.reservations[orderID]; !ok {
+		return domain.ErrReservationNotFound
+	}
+	delete(r.reservations, orderID)
+	return nil
+}
@@ -72,10 +72,15 @@ func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.orders[order.ID]; !exists {
+	stored, exists := r.orders[order.ID]
+	if !exists {
 		return domain.ErrNotFound
 	}
+	if stored.Version != order.Version {
+		return domain.ErrVersionConflict
+	}
 
+	order.Version++
 	r.orders[order.ID] = cloneOrder(order)
 	if key := order.IdempotencyKey; key != "" {
 		r.idempotency[key] = order.ID
@@ -83,6 +88,17 @@ func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error
 	return nil
 }
 
+// CountByStatus returns how many stored orders are in each status.
+func (r *OrderRepository) CountByStatus() map[domain.Status]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[domain.Status]int)
+	for _, o := range r.orders {
+		out[o.Status]++
+	}
+	return out
+}
+
 func (r *OrderRepository) FindByIdempotency(ctx context.Context, customerID, key string) (*domain.Order, error) {
 	_ = ctx
 	_ = customerID
@@ -106,6 +122,19 @@ func (r *OrderRepository) FindByIdempotency(ctx context.Context, customerID, key
 	return cloneOrder(order), nil
 }
 
+// remove rolls back inserts made earlier in a failed unit of work.
+func (r *OrderRepository) remove(orders ...*domain.Order) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, o := range orders {
+		delete(r.orders, o.ID)
+		if key := o.IdempotencyKey; key != "" && r.idempotency[key] == o.ID {
+			delete(r.idempotency, key)
+		}
+	}
+}
+
 func cloneOrder(order *domain.Order) *domain.Order {
 	if order == nil {
 		return nil
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+)
+
+// OutboxStore is an in-memory transactional outbox. It is only as durable as the
+// process, but preserves the insert+enqueue atomicity the relay relies on.
+type OutboxStore struct {
+	mu      sync.Mutex
+	nextID  uint64
+	records []domoutbox.Record
+}
+
+func NewOutboxStore() *OutboxStore {
+	return &OutboxStore{}
+}
+
+func (s *OutboxStore) Pending(ctx context.Context, limit int) ([]domoutbox.Record, error) {
+	_ = ctx
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.records)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	out := make([]domoutbox.Record, n)
+	copy(out, s.records[:n])
+	return out, nil
+}
+
+func (s *OutboxStore) MarkPublished(ctx context.Context, ids ...uint64) error {
+	_ = ctx
+	if len(ids) == 0 {
+		return nil
+	}
+	done := make(map[uint64]struct{}, len(ids))
+	for _, id := range ids {
+		done[id] = struct{}{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.records[:0]
+	for _, rec := range s.records {
+		if _, ok := done[rec.ID]; !ok {
+			kept = append(kept, rec)
+		}
+	}
+	clear(s.records[len(kept):])
+	s.records = kept
+	return nil
+}
+
+// appendLocked adds records, assigning their IDs; the caller must hold s.mu.
+func (s *OutboxStore) appendLocked(records ...domoutbox.Record) {
+	for _, rec := range records {
+		s.nextID++
+		rec.ID = s.nextID
+		s.records = append(s.records, rec)
+	}
+}
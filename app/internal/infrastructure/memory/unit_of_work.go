@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+
+	domain "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// OrderUnitOfWork commits order inserts and outbox events as one atomic section.
+type OrderUnitOfWork struct {
+	orders *OrderRepository
+	outbox *OutboxStore
+}
+
+func NewOrderUnitOfWork(orders *OrderRepository, outbox *OutboxStore) *OrderUnitOfWork {
+	return &OrderUnitOfWork{orders: orders, outbox: outbox}
+}
+
+// Do stages writes made by fn and applies them only if fn succeeds. Holding the
+// outbox lock across the commit keeps the relay from observing a half-applied unit.
+func (u *OrderUnitOfWork) Do(ctx context.Context, fn func(tx domain.Tx) error) error {
+	tx := &orderTx{}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	u.outbox.mu.Lock()
+	defer u.outbox.mu.Unlock()
+
+	for i, o := range tx.orders {
+		if err := u.orders.Insert(ctx, o); err != nil {
+			u.orders.remove(tx.orders[:i]...)
+			return err
+		}
+	}
+	u.outbox.appendLocked(tx.records...)
+	return nil
+}
+
+type orderTx struct {
+	orders  []*domain.Order
+	records []domoutbox.Record
+}
+
+func (t *orderTx) Insert(ctx context.Context, order *domain.Order) error {
+	_ = ctx
+	t.orders = append(t.orders, cloneOrder(order))
+	return nil
+}
+
+func (t *orderTx) Enqueue(ctx context.Context, e domoutbox.Event) error {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	t.records = append(t.records, domoutbox.Record{Event: e, TraceContext: carrier})
+	return nil
+}
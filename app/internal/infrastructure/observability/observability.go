@@ -13,6 +13,27 @@ type provider struct {
 type registeredMetrics struct {
 	counters   map[observability.MetricKey]observability.Counter
 	histograms map[observability.MetricKey]observability.Histogram
+	gauges     map[observability.MetricKey]observability.Gauge
+	catalog    observability.Cataloger // optional, see WithCatalog
+}
+
+// Catalog delegates to the cataloger passed with WithCatalog, if any.
+func (m *registeredMetrics) Catalog() []observability.Instrument {
+	if m == nil || m.catalog == nil {
+		return nil
+	}
+	return m.catalog.Catalog()
+}
+
+// Option customises the provider.
+type Option func(*registeredMetrics)
+
+// WithCatalog lets observability.CatalogOf enumerate the instruments of the backend the
+// metric maps were registered in, including ones not mapped to a MetricKey.
+func WithCatalog(c observability.Cataloger) Option {
+	return func(m *registeredMetrics) {
+		m.catalog = c
+	}
 }
 
 func (m *registeredMetrics) Counter(name observability.MetricKey) observability.Counter {
@@ -35,12 +56,24 @@ func (m *registeredMetrics) Histogram(name observability.MetricKey) observabilit
 	return observability.NopHistogram()
 }
 
-// New assembles a Telemetry provider backed by the supplied tracer, logger, and metric instruments.
+func (m *registeredMetrics) Gauge(name observability.MetricKey) observability.Gauge {
+	if m == nil || m.gauges == nil {
+		return observability.NopGauge()
+	}
+	if g, ok := m.gauges[name]; ok && g != nil {
+		return g
+	}
+	return observability.NopGauge()
+}
+
+// New assembles the observability.Observability provider backed by the supplied tracer, logger, and metric instruments.
 func New(
 	tracer observability.Tracer,
 	logger observability.Logger,
 	counters map[observability.MetricKey]observability.Counter,
 	histograms map[observability.MetricKey]observability.Histogram,
+	gauges map[observability.MetricKey]observability.Gauge,
+	opts ...Option,
 ) observability.Observability {
 	if tracer == nil {
 		tracer = observability.NopTracer()
@@ -50,10 +83,11 @@ func New(
 	}
 
 	var metrics observability.Metrics = observability.NopMetrics()
-	if len(counters) > 0 || len(histograms) > 0 {
+	if len(counters) > 0 || len(histograms) > 0 || len(gauges) > 0 {
 		m := &registeredMetrics{
 			counters:   make(map[observability.MetricKey]observability.Counter, len(counters)),
 			histograms: make(map[observability.MetricKey]observability.Histogram, len(histograms)),
+			gauges:     make(map[observability.MetricKey]observability.Gauge, len(gauges)),
 		}
 		for k, v := range counters {
 			if v == nil {
@@ -67,6 +101,15 @@ func New(
 			}
 			m.histograms[k] = v
 		}
+		for k, v := range gauges {
+			if v == nil {
+				continue
+			}
+			m.gauges[k] = v
+		}
+		for _, opt := range opts {
+			opt(m)
+		}
 		metrics = m
 	}
 
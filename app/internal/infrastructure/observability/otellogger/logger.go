@@ -0,0 +1,143 @@
+package otellogger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	fieldTraceID = "trace_id"
+	fieldSpanID  = "span_id"
+)
+
+// logger adapts an OTel log.Logger to observability.Logger.
+// Fields become record attributes, except trace_id/span_id which are lifted
+// into the record's trace context so backends can correlate logs with spans.
+type logger struct {
+	l      otellog.Logger
+	fields []otellog.KeyValue
+	// ctx carries the span context derived from bound trace_id/span_id fields.
+	ctx context.Context
+}
+
+// New wraps an OTel logger. It can be used standalone or combined with other
+// loggers through observability.MultiLogger.
+func New(l otellog.Logger, fixed ...observability.Field) observability.Logger {
+	base := &logger{l: l, ctx: context.Background()}
+	return base.With(fixed...)
+}
+
+func (o *logger) With(fields ...observability.Field) observability.Logger {
+	next := &logger{
+		l:      o.l,
+		fields: append([]otellog.KeyValue(nil), o.fields...),
+		ctx:    o.ctx,
+	}
+	next.fields, next.ctx = appendFields(next.fields, next.ctx, fields)
+	return next
+}
+
+func (o *logger) Debug(msg string, fields ...observability.Field) {
+	o.emit(otellog.SeverityDebug, "debug", msg, fields)
+}
+
+func (o *logger) Info(msg string, fields ...observability.Field) {
+	o.emit(otellog.SeverityInfo, "info", msg, fields)
+}
+
+func (o *logger) Warn(msg string, fields ...observability.Field) {
+	o.emit(otellog.SeverityWarn, "warn", msg, fields)
+}
+
+func (o *logger) Error(msg string, fields ...observability.Field) {
+	o.emit(otellog.SeverityError, "error", msg, fields)
+}
+
+func (o *logger) emit(sev otellog.Severity, sevText, msg string, fields []observability.Field) {
+	attrs, ctx := appendFields(append([]otellog.KeyValue(nil), o.fields...), o.ctx, fields)
+
+	var rec otellog.Record
+	rec.SetTimestamp(time.Now())
+	rec.SetSeverity(sev)
+	rec.SetSeverityText(sevText)
+	rec.SetBody(otellog.StringValue(msg))
+	rec.AddAttributes(attrs...)
+
+	o.l.Emit(ctx, rec)
+}
+
+// appendFields converts fields to attributes, folding trace_id/span_id into ctx.
+func appendFields(attrs []otellog.KeyValue, ctx context.Context, fields []observability.Field) ([]otellog.KeyValue, context.Context) {
+	for _, f := range fields {
+		switch f.Key {
+		case fieldTraceID, fieldSpanID:
+			if next, ok := withCorrelation(ctx, f); ok {
+				ctx = next
+				continue
+			}
+		}
+		attrs = append(attrs, otellog.KeyValue{Key: f.Key, Value: toValue(f.Value)})
+	}
+	return attrs, ctx
+}
+
+func withCorrelation(ctx context.Context, f observability.Field) (context.Context, bool) {
+	s, ok := f.Value.(string)
+	if !ok {
+		return ctx, false
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	switch f.Key {
+	case fieldTraceID:
+		id, err := trace.TraceIDFromHex(s)
+		if err != nil {
+			return ctx, false
+		}
+		sc = sc.WithTraceID(id)
+	case fieldSpanID:
+		id, err := trace.SpanIDFromHex(s)
+		if err != nil {
+			return ctx, false
+		}
+		sc = sc.WithSpanID(id)
+	}
+	return trace.ContextWithSpanContext(ctx, sc), true
+}
+
+func toValue(v any) otellog.Value {
+	switch val := v.(type) {
+	case nil:
+		return otellog.Value{}
+	case string:
+		return otellog.StringValue(val)
+	case bool:
+		return otellog.BoolValue(val)
+	case int:
+		return otellog.IntValue(val)
+	case int32:
+		return otellog.Int64Value(int64(val))
+	case int64:
+		return otellog.Int64Value(val)
+	case uint32:
+		return otellog.Int64Value(int64(val))
+	case float32:
+		return otellog.Float64Value(float64(val))
+	case float64:
+		return otellog.Float64Value(val)
+	case time.Duration:
+		return otellog.StringValue(val.String())
+	case time.Time:
+		return otellog.StringValue(val.Format(time.RFC3339Nano))
+	case error:
+		return otellog.StringValue(val.Error())
+	case fmt.Stringer:
+		return otellog.StringValue(val.String())
+	default:
+		return otellog.StringValue(fmt.Sprint(val))
+	}
+}
@@ -0,0 +1,54 @@
+package otellogger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const instrumentationName = "github.com/Zhima-Mochi/minishop-observability/app"
+
+// Provider owns the OTel LoggerProvider and its OTLP exporter.
+// Endpoint and headers follow the standard OTEL_EXPORTER_OTLP_* environment variables.
+type Provider struct {
+	lp *sdklog.LoggerProvider
+}
+
+// NewProvider builds a batching LoggerProvider exporting over OTLP/HTTP.
+func NewProvider(ctx context.Context, serviceName, version, env string) (*Provider, error) {
+	exp, err := otlploghttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("otellogger: create exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(version),
+		semconv.DeploymentEnvironmentName(env),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("otellogger: build resource: %w", err)
+	}
+
+	lp := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exp)),
+	)
+	return &Provider{lp: lp}, nil
+}
+
+// Logger returns an observability.Logger emitting through this provider.
+func (p *Provider) Logger(fixed ...observability.Field) observability.Logger {
+	return New(p.lp.Logger(instrumentationName), fixed...)
+}
+
+// Shutdown flushes buffered records and stops the exporter. Safe to call on shutdown.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.lp.Shutdown(ctx)
+}
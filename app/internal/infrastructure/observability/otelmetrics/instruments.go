@@ -0,0 +1,109 @@
+package otelmetrics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// labelSchema holds the label keys an instrument was registered with.
+type labelSchema struct {
+	name string
+	keys []string
+}
+
+func newLabelSchema(name string, keys []string) labelSchema {
+	return labelSchema{name: name, keys: slices.Clone(keys)}
+}
+
+// checkBound panics if labels use a key the instrument was not registered with, as
+// prometheus.MustCurryWith does.
+func (s labelSchema) checkBound(labels []observability.Label) {
+	for _, l := range labels {
+		if !slices.Contains(s.keys, l.Key) {
+			panic(fmt.Sprintf("otelmetrics: %s: label %q not in registered keys %v", s.name, l.Key, s.keys))
+		}
+	}
+}
+
+// set builds the attribute set for bound plus labels, panicking unless together they
+// supply every registered key exactly once, as a Prometheus vector would.
+func (s labelSchema) set(bound, labels []observability.Label) attribute.Set {
+	kvs := make([]attribute.KeyValue, 0, len(bound)+len(labels))
+	seen := make(map[string]bool, len(s.keys))
+	for _, l := range slices.Concat(bound, labels) {
+		if !slices.Contains(s.keys, l.Key) || seen[l.Key] {
+			panic(fmt.Sprintf("otelmetrics: %s: labels %v do not match registered keys %v", s.name, slices.Concat(bound, labels), s.keys))
+		}
+		seen[l.Key] = true
+		kvs = append(kvs, attribute.String(l.Key, l.Value))
+	}
+	if len(seen) != len(s.keys) {
+		panic(fmt.Sprintf("otelmetrics: %s: labels %v do not match registered keys %v", s.name, slices.Concat(bound, labels), s.keys))
+	}
+	return attribute.NewSet(kvs...)
+}
+
+type counter struct {
+	c      metric.Float64Counter
+	labels labelSchema
+	bound  []observability.Label
+}
+
+func (c *counter) Add(d float64, labels ...observability.Label) {
+	c.c.Add(context.Background(), d, metric.WithAttributeSet(c.labels.set(c.bound, labels)))
+}
+
+// Bind fixes labels so only the remaining ones are supplied per call.
+func (c *counter) Bind(labels ...observability.Label) observability.BoundCounter {
+	c.labels.checkBound(labels)
+	return &counter{c: c.c, labels: c.labels, bound: slices.Concat(c.bound, labels)}
+}
+
+type histogram struct {
+	h      metric.Float64Histogram
+	labels labelSchema
+	bound  []observability.Label
+}
+
+func (h *histogram) Observe(v float64, labels ...observability.Label) {
+	h.h.Record(context.Background(), v, metric.WithAttributeSet(h.labels.set(h.bound, labels)))
+}
+
+// Bind fixes labels so only the remaining ones are supplied per call.
+func (h *histogram) Bind(labels ...observability.Label) observability.BoundHistogram {
+	h.labels.checkBound(labels)
+	return &histogram{h: h.h, labels: h.labels, bound: slices.Concat(h.bound, labels)}
+}
+
+// gauge keeps the current value of each series so Add can be reported as an absolute
+// value; OTel gauges only record what they are given.
+type gauge struct {
+	g      metric.Float64Gauge
+	labels labelSchema
+
+	mu     sync.Mutex
+	values map[attribute.Distinct]float64
+}
+
+func (g *gauge) Set(v float64, labels ...observability.Label) {
+	g.update(labels, func(float64) float64 { return v })
+}
+
+func (g *gauge) Add(d float64, labels ...observability.Label) {
+	g.update(labels, func(cur float64) float64 { return cur + d })
+}
+
+func (g *gauge) update(labels []observability.Label, next func(float64) float64) {
+	set := g.labels.set(nil, labels)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	v := next(g.values[set.Equivalent()])
+	g.values[set.Equivalent()] = v
+	g.g.Record(context.Background(), v, metric.WithAttributeSet(set))
+}
@@ -0,0 +1,229 @@
+// Package otelmetrics is a push-based metrics backend on the OTel metric SDK, for
+// environments without a Prometheus scrape. It mirrors prometrics: instruments get the
+// same fully qualified names and, like Prometheus vectors, panic when a call's label
+// keys differ from the ones registered, so call sites behave the same on either backend.
+package otelmetrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const instrumentationName = "github.com/Zhima-Mochi/minishop-observability/app"
+
+var _ observability.Cataloger = (*Registry)(nil)
+
+// Config selects the exporter and the resource the metrics are reported under.
+type Config struct {
+	// Namespace and Subsystem prefix every instrument name, as in prometrics.
+	Namespace   string
+	Subsystem   string
+	ServiceName string
+	Version     string
+	Env         string
+	// Exporter is "otlp" (OTLP/HTTP, configured by the standard OTEL_EXPORTER_OTLP_*
+	// variables) or "stdout" (pretty-printed JSON, for local runs).
+	Exporter string
+	// Interval is how often metrics are pushed; 0 uses the SDK default of one minute.
+	Interval time.Duration
+	// Output receives the stdout exporter's JSON; nil means os.Stdout.
+	Output io.Writer
+}
+
+// Registry creates instruments on an OTel MeterProvider that periodically pushes
+// them to the configured exporter.
+type Registry struct {
+	mp        *sdkmetric.MeterProvider
+	meter     metric.Meter
+	namespace string
+	subsystem string
+
+	counters   sync.Map // name -> *counter
+	histograms sync.Map // name -> *histogram
+	gauges     sync.Map // name -> *gauge
+	catalogMu  sync.Mutex
+	catalog    []observability.Instrument
+}
+
+// New builds a Registry exporting through cfg.Exporter.
+func New(ctx context.Context, cfg Config) (*Registry, error) {
+	exp, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("otelmetrics: create exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.Version),
+		semconv.DeploymentEnvironmentName(cfg.Env),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("otelmetrics: build resource: %w", err)
+	}
+
+	var readerOpts []sdkmetric.PeriodicReaderOption
+	if cfg.Interval > 0 {
+		readerOpts = append(readerOpts, sdkmetric.WithInterval(cfg.Interval))
+	}
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp, readerOpts...)),
+	)
+	return &Registry{
+		mp:        mp,
+		meter:     mp.Meter(instrumentationName),
+		namespace: cfg.Namespace,
+		subsystem: cfg.Subsystem,
+	}, nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdkmetric.Exporter, error) {
+	switch cfg.Exporter {
+	case "otlp":
+		return otlpmetrichttp.New(ctx)
+	case "stdout":
+		opts := []stdoutmetric.Option{stdoutmetric.WithPrettyPrint()}
+		if cfg.Output != nil {
+			opts = append(opts, stdoutmetric.WithWriter(cfg.Output))
+		}
+		return stdoutmetric.New(opts...)
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", cfg.Exporter)
+	}
+}
+
+// ForceFlush pushes everything recorded so far without waiting for the next interval.
+func (r *Registry) ForceFlush(ctx context.Context) error {
+	return r.mp.ForceFlush(ctx)
+}
+
+// Shutdown flushes pending metrics and stops the exporter. Safe to call on shutdown.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	return r.mp.Shutdown(ctx)
+}
+
+func (r *Registry) Counter(name string, help string, labelKeys ...string) observability.Counter {
+	if v, ok := r.counters.Load(name); ok {
+		return v.(*counter)
+	}
+	fq := r.fqName(name)
+	c, err := r.meter.Float64Counter(fq, metric.WithDescription(help))
+	if err != nil {
+		panic(fmt.Sprintf("otelmetrics: %s: %v", fq, err))
+	}
+	v, loaded := r.counters.LoadOrStore(name, &counter{c: c, labels: newLabelSchema(fq, labelKeys)})
+	if !loaded {
+		r.record("counter", name, help, labelKeys)
+	}
+	return v.(*counter)
+}
+
+func (r *Registry) Histogram(name string, help string, buckets []float64, labelKeys ...string) observability.Histogram {
+	if v, ok := r.histograms.Load(name); ok {
+		return v.(*histogram)
+	}
+	fq := r.fqName(name)
+	opts := []metric.Float64HistogramOption{metric.WithDescription(help)}
+	if len(buckets) > 0 {
+		opts = append(opts, metric.WithExplicitBucketBoundaries(buckets...))
+	}
+	h, err := r.meter.Float64Histogram(fq, opts...)
+	if err != nil {
+		panic(fmt.Sprintf("otelmetrics: %s: %v", fq, err))
+	}
+	v, loaded := r.histograms.LoadOrStore(name, &histogram{h: h, labels: newLabelSchema(fq, labelKeys)})
+	if !loaded {
+		r.record("histogram", name, help, labelKeys)
+	}
+	return v.(*histogram)
+}
+
+func (r *Registry) Gauge(name string, help string, labelKeys ...string) observability.Gauge {
+	if v, ok := r.gauges.Load(name); ok {
+		return v.(*gauge)
+	}
+	fq := r.fqName(name)
+	g, err := r.meter.Float64Gauge(fq, metric.WithDescription(help))
+	if err != nil {
+		panic(fmt.Sprintf("otelmetrics: %s: %v", fq, err))
+	}
+	v, loaded := r.gauges.LoadOrStore(name, &gauge{
+		g:      g,
+		labels: newLabelSchema(fq, labelKeys),
+		values: make(map[attribute.Distinct]float64),
+	})
+	if !loaded {
+		r.record("gauge", name, help, labelKeys)
+	}
+	return v.(*gauge)
+}
+
+// BuildInfo registers a constant build_info gauge (value 1) labeled with the running
+// version, commit and Go version, matching prometrics.
+func (r *Registry) BuildInfo(version, commit string) {
+	const name, help = "build_info", "A metric with a constant '1' value labeled by version, commit and goversion."
+	attrs := metric.WithAttributes(
+		attribute.String("version", version),
+		attribute.String("commit", commit),
+		attribute.String("goversion", runtime.Version()),
+	)
+	_, err := r.meter.Int64ObservableGauge(r.fqName(name),
+		metric.WithDescription(help),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(1, attrs)
+			return nil
+		}),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("otelmetrics: %s: %v", name, err))
+	}
+	r.record("gauge", name, help, []string{"version", "commit", "goversion"})
+}
+
+// Catalog lists every instrument registered through r, sorted by name.
+func (r *Registry) Catalog() []observability.Instrument {
+	r.catalogMu.Lock()
+	out := slices.Clone(r.catalog)
+	r.catalogMu.Unlock()
+	slices.SortFunc(out, func(a, b observability.Instrument) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
+
+func (r *Registry) record(typ, name, help string, labelKeys []string) {
+	r.catalogMu.Lock()
+	defer r.catalogMu.Unlock()
+	r.catalog = append(r.catalog, observability.Instrument{
+		Name:   r.fqName(name),
+		Key:    name,
+		Help:   help,
+		Type:   typ,
+		Labels: append([]string{}, labelKeys...),
+	})
+}
+
+// fqName joins the non-empty namespace, subsystem and name like prometheus.BuildFQName.
+func (r *Registry) fqName(name string) string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{r.namespace, r.subsystem, name} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, "_")
+}
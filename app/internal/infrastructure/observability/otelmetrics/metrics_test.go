@@ -0,0 +1,96 @@
+package otelmetrics_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/otelmetrics"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+)
+
+// exported is the part of the stdout exporter's JSON the tests read.
+type exported struct {
+	ScopeMetrics []struct {
+		Metrics []struct {
+			Name string
+			Data struct {
+				DataPoints []struct {
+					Attributes []struct {
+						Key   string
+						Value struct{ Value any }
+					}
+					Value float64
+				}
+			}
+		}
+	}
+}
+
+func newStdoutRegistry(t *testing.T, out *bytes.Buffer) *otelmetrics.Registry {
+	t.Helper()
+	r, err := otelmetrics.New(context.Background(), otelmetrics.Config{
+		Namespace:   "minishop",
+		Subsystem:   "app",
+		ServiceName: "minishop",
+		Exporter:    "stdout",
+		Output:      out,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = r.Shutdown(context.Background()) })
+	return r
+}
+
+func TestStdoutExportEmitsCounterIncrement(t *testing.T) {
+	var out bytes.Buffer
+	r := newStdoutRegistry(t, &out)
+
+	c := r.Counter("orders_total", "Orders by result.", "result")
+	c.Add(1, observability.L("result", "ok"))
+	c.Bind(observability.L("result", "ok")).Add(2)
+	if err := r.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	var got exported
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("decode export: %v\n%s", err, out.String())
+	}
+	var found bool
+	for _, sm := range got.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			// Same fully qualified name prometrics would register.
+			if m.Name != "minishop_app_orders_total" {
+				continue
+			}
+			found = true
+			if len(m.Data.DataPoints) != 1 {
+				t.Fatalf("data points = %d, want 1", len(m.Data.DataPoints))
+			}
+			dp := m.Data.DataPoints[0]
+			if dp.Value != 3 {
+				t.Errorf("counter value = %v, want 3", dp.Value)
+			}
+			if len(dp.Attributes) != 1 || dp.Attributes[0].Key != "result" || dp.Attributes[0].Value.Value != "ok" {
+				t.Errorf("attributes = %+v, want result=ok", dp.Attributes)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("minishop_app_orders_total not exported:\n%s", out.String())
+	}
+}
+
+func TestUnregisteredLabelKeyPanics(t *testing.T) {
+	r := newStdoutRegistry(t, &bytes.Buffer{})
+	c := r.Counter("orders_total", "Orders by result.", "result")
+	defer func() {
+		if recover() == nil {
+			t.Error("Add with an unregistered label key did not panic")
+		}
+	}()
+	c.Add(1, observability.L("status", "ok"))
+}
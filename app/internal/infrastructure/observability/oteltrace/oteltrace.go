@@ -11,6 +11,8 @@ import (
 
 type tracer struct{ t trace.Tracer }
 
+// New returns a tracer from the global TracerProvider; spans are no-ops until
+// InitProvider installs a real one.
 func New(name string) observability.Tracer {
 	if name == "" {
 		name = "minishop"
@@ -21,5 +23,3 @@ func New(name string) observability.Tracer {
 func (t *tracer) Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
 	return t.t.Start(ctx, name, trace.WithAttributes(attrs...))
 }
-
-// you need to initialize sdktrace.TracerProvider + exporter, then set otel.SetTracerProvider(tp)
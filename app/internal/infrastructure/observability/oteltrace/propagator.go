@@ -0,0 +1,43 @@
+package oteltrace
+
+import (
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// PropagatorOption adds formats to the propagator installed by SetPropagator.
+type PropagatorOption func(*propagatorOptions)
+
+type propagatorOptions struct {
+	b3 bool
+}
+
+// WithB3 also injects B3 (both the single b3 header and the X-B3-* headers) and
+// extracts either, for interop with services that do not speak W3C Trace Context.
+func WithB3() PropagatorOption {
+	return func(o *propagatorOptions) { o.b3 = true }
+}
+
+// SetPropagator installs W3C Trace Context and Baggage, plus any formats enabled by
+// opts, as the global propagator used for HTTP requests and events crossing the bus.
+// Without it otel's default propagator is a no-op and incoming traceparent headers are
+// silently ignored.
+func SetPropagator(opts ...PropagatorOption) {
+	otel.SetTextMapPropagator(NewPropagator(opts...))
+}
+
+// NewPropagator builds the propagator SetPropagator installs. B3 is extracted first so
+// a request carrying both formats continues the W3C trace.
+func NewPropagator(opts ...PropagatorOption) propagation.TextMapPropagator {
+	var o propagatorOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var props []propagation.TextMapPropagator
+	if o.b3 {
+		props = append(props, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader|b3.B3MultipleHeader)))
+	}
+	props = append(props, propagation.TraceContext{}, propagation.Baggage{})
+	return propagation.NewCompositeTextMapPropagator(props...)
+}
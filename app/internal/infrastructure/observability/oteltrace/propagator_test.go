@@ -0,0 +1,66 @@
+package oteltrace_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/oteltrace"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	testTraceID = "0af7651916cd43dd8448eb211c80319c"
+	testSpanID  = "b7ad6b7169203331"
+)
+
+func TestSetPropagatorExtractsTraceparent(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	oteltrace.SetPropagator()
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	h := http.Header{}
+	h.Set("traceparent", "00-"+testTraceID+"-"+testSpanID+"-01")
+	h.Set("baggage", "tenant=acme")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(h))
+
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || !sc.IsRemote() || !sc.IsSampled() {
+		t.Fatalf("span context = %+v, want a valid, remote, sampled one", sc)
+	}
+	if sc.TraceID().String() != testTraceID || sc.SpanID().String() != testSpanID {
+		t.Errorf("extracted %s/%s, want %s/%s", sc.TraceID(), sc.SpanID(), testTraceID, testSpanID)
+	}
+	if got := baggage.FromContext(ctx).Member("tenant").Value(); got != "acme" {
+		t.Errorf("baggage tenant = %q, want acme", got)
+	}
+}
+
+func TestB3IsOptIn(t *testing.T) {
+	h := http.Header{}
+	h.Set("b3", testTraceID+"-"+testSpanID+"-1")
+
+	plain := oteltrace.NewPropagator().Extract(context.Background(), propagation.HeaderCarrier(h))
+	if trace.SpanContextFromContext(plain).IsValid() {
+		t.Error("default propagator extracted a B3 header")
+	}
+
+	withB3 := oteltrace.NewPropagator(oteltrace.WithB3())
+	sc := trace.SpanContextFromContext(withB3.Extract(context.Background(), propagation.HeaderCarrier(h)))
+	if sc.TraceID().String() != testTraceID {
+		t.Fatalf("B3 trace ID = %s, want %s", sc.TraceID(), testTraceID)
+	}
+
+	// Outgoing requests carry both formats, so either kind of peer can continue the trace.
+	out := http.Header{}
+	withB3.Inject(trace.ContextWithSpanContext(context.Background(), sc), propagation.HeaderCarrier(out))
+	for _, key := range []string{"traceparent", "b3", "X-B3-Traceid"} {
+		if out.Get(key) == "" {
+			t.Errorf("injected headers %v lack %s", out, key)
+		}
+	}
+}
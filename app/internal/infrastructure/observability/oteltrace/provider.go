@@ -0,0 +1,78 @@
+package oteltrace
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// Config selects where InitProvider exports spans and how many it keeps.
+type Config struct {
+	ServiceName string
+	Version     string
+	Env         string
+	// Endpoint is the collector URL, e.g. "http://otel-collector:4318"; empty falls back
+	// to the standard OTEL_EXPORTER_OTLP_* environment variables.
+	Endpoint string
+	// Protocol is "http" (OTLP/HTTP, the default) or "grpc".
+	Protocol string
+	// Sampling selects which spans are recorded; the zero value is DefaultSampling.
+	Sampling SamplingConfig
+}
+
+// InitProvider installs a batching TracerProvider exporting over OTLP as the global
+// provider, so tracers from New start recording. The returned func flushes buffered
+// spans and stops the exporter; register it with the shutdown hooks.
+func InitProvider(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	sampler, err := cfg.Sampling.Sampler()
+	if err != nil {
+		return nil, err
+	}
+	exp, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("oteltrace: create exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.Version),
+		semconv.DeploymentEnvironmentName(cfg.Env),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("oteltrace: build resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithSampler(sampler),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Protocol {
+	case "", "http":
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	case "grpc":
+		var opts []otlptracegrpc.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown protocol %q", cfg.Protocol)
+	}
+}
@@ -0,0 +1,79 @@
+package oteltrace
+
+import (
+	"fmt"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// debugSampler samples every span whose context carries debug=1 baggage and defers
+// to base for everything else, so one tenant can be traced fully without raising the
+// global ratio.
+type debugSampler struct {
+	base sdktrace.Sampler
+}
+
+// NewDebugSampler wraps base (e.g. ParentBased(TraceIDRatioBased(r))) with the debug
+// baggage override. The override also wins over an unsampled parent.
+func NewDebugSampler(base sdktrace.Sampler) sdktrace.Sampler {
+	return debugSampler{base: base}
+}
+
+func (s debugSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if baggage.FromContext(p.ParentContext).Member(observability.BaggageDebug).Value() == observability.BaggageDebugOn {
+		psc := trace.SpanContextFromContext(p.ParentContext)
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: psc.TraceState(),
+		}
+	}
+	return s.base.ShouldSample(p)
+}
+
+func (s debugSampler) Description() string {
+	return "DebugBaggage{" + s.base.Description() + "}"
+}
+
+// Sampling modes accepted by SamplingConfig.
+const (
+	SampleAlways           = "always"
+	SampleNever            = "never"
+	SampleRatio            = "ratio"
+	SampleParentBasedRatio = "parentbased_ratio"
+)
+
+// SamplingConfig picks the sampler InitProvider installs. SampleRatio decides every
+// span by trace ID alone; SampleParentBasedRatio applies the ratio to new traces only
+// and otherwise follows the parent, including the sampled flag of an incoming
+// traceparent header.
+type SamplingConfig struct {
+	Mode  string
+	Ratio float64
+}
+
+// DefaultSampling keeps 10% of new traces and follows the parent's decision otherwise.
+var DefaultSampling = SamplingConfig{Mode: SampleParentBasedRatio, Ratio: 0.1}
+
+// Sampler builds the configured sampler wrapped with NewDebugSampler, so debug
+// baggage is traced whatever the mode. An empty Mode means DefaultSampling.
+func (c SamplingConfig) Sampler() (sdktrace.Sampler, error) {
+	var base sdktrace.Sampler
+	switch c.Mode {
+	case SampleAlways:
+		base = sdktrace.AlwaysSample()
+	case SampleNever:
+		base = sdktrace.NeverSample()
+	case SampleRatio:
+		base = sdktrace.TraceIDRatioBased(c.Ratio)
+	case SampleParentBasedRatio:
+		base = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.Ratio))
+	case "":
+		return DefaultSampling.Sampler()
+	default:
+		return nil, fmt.Errorf("oteltrace: unknown sampling mode %q", c.Mode)
+	}
+	return NewDebugSampler(base), nil
+}
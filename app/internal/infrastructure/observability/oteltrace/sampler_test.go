@@ -0,0 +1,87 @@
+package oteltrace_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/oteltrace"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startUnder starts a span under parent (none when invalid) with the sampler cfg builds.
+// The span is left open, since ended spans never report recording.
+func startUnder(t *testing.T, cfg oteltrace.SamplingConfig, parent trace.SpanContext) trace.Span {
+	t.Helper()
+	sampler, err := cfg.Sampler()
+	if err != nil {
+		t.Fatalf("Sampler(%+v): %v", cfg, err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx := context.Background()
+	if parent.IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, parent)
+	}
+	_, span := tp.Tracer("test").Start(ctx, "span")
+	t.Cleanup(func() { span.End() })
+	return span
+}
+
+func remoteParent(flags trace.TraceFlags) trace.SpanContext {
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x01},
+		TraceFlags: flags,
+		Remote:     true,
+	})
+}
+
+func TestSamplingModes(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    oteltrace.SamplingConfig
+		parent trace.SpanContext
+		want   bool
+	}{
+		{"always", oteltrace.SamplingConfig{Mode: oteltrace.SampleAlways}, trace.SpanContext{}, true},
+		{"never", oteltrace.SamplingConfig{Mode: oteltrace.SampleNever}, trace.SpanContext{}, false},
+		{"never ignores a sampled parent", oteltrace.SamplingConfig{Mode: oteltrace.SampleNever}, remoteParent(trace.FlagsSampled), false},
+		{"ratio 1", oteltrace.SamplingConfig{Mode: oteltrace.SampleRatio, Ratio: 1}, trace.SpanContext{}, true},
+		{"ratio 0", oteltrace.SamplingConfig{Mode: oteltrace.SampleRatio, Ratio: 0}, trace.SpanContext{}, false},
+		// A plain ratio decides by trace ID alone, whatever the caller chose.
+		{"ratio 0 ignores a sampled parent", oteltrace.SamplingConfig{Mode: oteltrace.SampleRatio, Ratio: 0}, remoteParent(trace.FlagsSampled), false},
+		{"parent-based follows a sampled parent", oteltrace.SamplingConfig{Mode: oteltrace.SampleParentBasedRatio, Ratio: 0}, remoteParent(trace.FlagsSampled), true},
+		{"parent-based follows an unsampled parent", oteltrace.SamplingConfig{Mode: oteltrace.SampleParentBasedRatio, Ratio: 1}, remoteParent(0), false},
+		{"parent-based applies the ratio to new traces", oteltrace.SamplingConfig{Mode: oteltrace.SampleParentBasedRatio, Ratio: 0}, trace.SpanContext{}, false},
+		{"default follows a sampled parent", oteltrace.SamplingConfig{}, remoteParent(trace.FlagsSampled), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := startUnder(t, tt.cfg, tt.parent)
+			if got := span.SpanContext().IsSampled(); got != tt.want {
+				t.Errorf("sampled = %v, want %v", got, tt.want)
+			}
+			if got := span.IsRecording(); got != tt.want {
+				t.Errorf("recording = %v, want %v", got, tt.want)
+			}
+			if tt.parent.IsValid() && span.SpanContext().TraceID() != tt.parent.TraceID() {
+				t.Errorf("trace ID = %s, want the parent's %s", span.SpanContext().TraceID(), tt.parent.TraceID())
+			}
+		})
+	}
+}
+
+func TestNeverSamplerSpansDoNotRecord(t *testing.T) {
+	span := startUnder(t, oteltrace.SamplingConfig{Mode: oteltrace.SampleNever}, trace.SpanContext{})
+	if span.IsRecording() {
+		t.Error("span is recording under the never sampler")
+	}
+}
+
+func TestUnknownSamplingModeIsRejected(t *testing.T) {
+	if _, err := (oteltrace.SamplingConfig{Mode: "sometimes"}).Sampler(); err == nil {
+		t.Error("Sampler() = nil error, want one for an unknown mode")
+	}
+}
@@ -0,0 +1,25 @@
+package prometrics
+
+import (
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BuildInfo registers a constant build_info gauge (value 1) labeled with the running
+// version, commit and Go version, so series can be joined against it per deploy.
+func (r *registry) BuildInfo(version, commit string) {
+	const name, help = "build_info", "A metric with a constant '1' value labeled by version, commit and goversion."
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: r.namespace,
+		Subsystem: r.subsystem,
+		Name:      name,
+		Help:      help,
+		ConstLabels: prometheus.Labels{
+			"version":   version,
+			"commit":    commit,
+			"goversion": runtime.Version(),
+		},
+	}, func() float64 { return 1 }))
+	r.record("gauge", name, help, []string{"version", "commit", "goversion"})
+}
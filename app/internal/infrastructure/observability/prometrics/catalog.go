@@ -0,0 +1,31 @@
+package prometrics
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// record adds a newly registered instrument to the catalog.
+func (r *registry) record(typ, name, help string, labelKeys []string) {
+	r.catalogMu.Lock()
+	defer r.catalogMu.Unlock()
+	r.catalog = append(r.catalog, observability.Instrument{
+		Name:   prometheus.BuildFQName(r.namespace, r.subsystem, name),
+		Key:    name,
+		Help:   help,
+		Type:   typ,
+		Labels: append([]string{}, labelKeys...),
+	})
+}
+
+// Catalog lists every instrument registered through r, sorted by name.
+func (r *registry) Catalog() []observability.Instrument {
+	r.catalogMu.Lock()
+	out := slices.Clone(r.catalog)
+	r.catalogMu.Unlock()
+	slices.SortFunc(out, func(a, b observability.Instrument) int { return strings.Compare(a.Name, b.Name) })
+	return out
+}
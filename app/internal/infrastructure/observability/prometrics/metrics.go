@@ -11,17 +11,54 @@ import (
 type Registry interface {
 	Counter(name string, help string, labelKeys ...string) observability.Counter
 	Histogram(name string, help string, buckets []float64, labelKeys ...string) observability.Histogram
+	HistogramWithOpts(name string, help string, opts HistogramOpts, labelKeys ...string) observability.Histogram
+	Gauge(name string, help string, labelKeys ...string) observability.Gauge
+	// BuildInfo registers the build_info gauge for the running binary; call it once.
+	BuildInfo(version, commit string)
+	// Catalog lists the registered instruments with their help text and label keys.
+	observability.Cataloger
+}
+
+// HistogramOpts configures a single histogram. Classic Buckets and native (sparse)
+// buckets can be combined; Prometheus then exposes both representations.
+type HistogramOpts struct {
+	Buckets []float64
+	// NativeBucketFactor > 1 enables native histograms; 1.1 is a common choice.
+	NativeBucketFactor float64
+	// NativeMaxBucketNumber bounds the number of native buckets (0 = unlimited).
+	NativeMaxBucketNumber uint32
+}
+
+// Option customises the registry.
+type Option func(*registry)
+
+// WithNativeHistograms enables native histograms for every histogram created via Histogram.
+func WithNativeHistograms(bucketFactor float64, maxBucketNumber uint32) Option {
+	return func(r *registry) {
+		r.nativeBucketFactor = bucketFactor
+		r.nativeMaxBucketNumber = maxBucketNumber
+	}
 }
 
 type registry struct {
 	counters   sync.Map // name -> *prometheus.CounterVec
 	histograms sync.Map // name -> *prometheus.HistogramVec
+	gauges     sync.Map // name -> *prometheus.GaugeVec
+	catalogMu  sync.Mutex
+	catalog    []observability.Instrument
 	namespace  string
 	subsystem  string
+
+	nativeBucketFactor    float64
+	nativeMaxBucketNumber uint32
 }
 
-func New(namespace, subsystem string) Registry {
-	return &registry{namespace: namespace, subsystem: subsystem}
+func New(namespace, subsystem string, opts ...Option) Registry {
+	r := &registry{namespace: namespace, subsystem: subsystem}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 type counter struct{ v *prometheus.CounterVec }
@@ -30,20 +67,31 @@ func (c *counter) Add(d float64, labels ...observability.Label) {
 	c.v.With(labelMap(labels)).Add(d)
 }
 
+// Bind curries labels into the vector so only the remaining labels are resolved per call.
+// When labels covers every key, the series is resolved here once instead.
 func (c *counter) Bind(labels ...observability.Label) observability.BoundCounter {
-	return &boundCounter{v: c.v, labels: labelMap(labels)}
+	v := c.v.MustCurryWith(labelMap(labels))
+	b := &boundCounter{v: v}
+	if m, err := v.GetMetricWith(nil); err == nil {
+		b.c = m
+	}
+	return b
 }
 
 type boundCounter struct {
-	v      *prometheus.CounterVec
-	labels prometheus.Labels
+	v *prometheus.CounterVec
+	c prometheus.Counter // set when Bind fixed every label
 }
 
-func (c *boundCounter) Add(d float64) {
+func (c *boundCounter) Add(d float64, remaining ...observability.Label) {
 	if c == nil || c.v == nil {
 		return
 	}
-	c.v.With(c.labels).Add(d)
+	if c.c != nil && len(remaining) == 0 {
+		c.c.Add(d)
+		return
+	}
+	c.v.With(labelMap(remaining)).Add(d)
 }
 
 type histogram struct{ v *prometheus.HistogramVec }
@@ -52,20 +100,41 @@ func (h *histogram) Observe(v float64, labels ...observability.Label) {
 	h.v.With(labelMap(labels)).Observe(v)
 }
 
+// Bind curries labels into the vector so only the remaining labels are resolved per call.
+// When labels covers every key, the series is resolved here once instead.
 func (h *histogram) Bind(labels ...observability.Label) observability.BoundHistogram {
-	return &boundHistogram{v: h.v, labels: labelMap(labels)}
+	v := h.v.MustCurryWith(labelMap(labels))
+	b := &boundHistogram{v: v}
+	if o, err := v.GetMetricWith(nil); err == nil {
+		b.o = o
+	}
+	return b
 }
 
 type boundHistogram struct {
-	v      *prometheus.HistogramVec
-	labels prometheus.Labels
+	v prometheus.ObserverVec
+	o prometheus.Observer // set when Bind fixed every label
 }
 
-func (h *boundHistogram) Observe(v float64) {
+func (h *boundHistogram) Observe(v float64, remaining ...observability.Label) {
 	if h == nil || h.v == nil {
 		return
 	}
-	h.v.With(h.labels).Observe(v)
+	if h.o != nil && len(remaining) == 0 {
+		h.o.Observe(v)
+		return
+	}
+	h.v.With(labelMap(remaining)).Observe(v)
+}
+
+type gauge struct{ v *prometheus.GaugeVec }
+
+func (g *gauge) Set(v float64, labels ...observability.Label) {
+	g.v.With(labelMap(labels)).Set(v)
+}
+
+func (g *gauge) Add(d float64, labels ...observability.Label) {
+	g.v.With(labelMap(labels)).Add(d)
 }
 
 func labelMap(ls []observability.Label) prometheus.Labels {
@@ -86,17 +155,42 @@ func (r *registry) Counter(name string, help string, labelKeys ...string) observ
 	}, labelKeys)
 	prometheus.MustRegister(cv)
 	r.counters.Store(name, cv)
+	r.record("counter", name, help, labelKeys)
 	return &counter{v: cv}
 }
 
 func (r *registry) Histogram(name string, help string, buckets []float64, labelKeys ...string) observability.Histogram {
+	return r.HistogramWithOpts(name, help, HistogramOpts{
+		Buckets:               buckets,
+		NativeBucketFactor:    r.nativeBucketFactor,
+		NativeMaxBucketNumber: r.nativeMaxBucketNumber,
+	}, labelKeys...)
+}
+
+func (r *registry) HistogramWithOpts(name string, help string, opts HistogramOpts, labelKeys ...string) observability.Histogram {
 	if v, ok := r.histograms.Load(name); ok {
 		return &histogram{v: v.(*prometheus.HistogramVec)}
 	}
 	hv := prometheus.NewHistogramVec(prometheus.HistogramOpts{
-		Namespace: r.namespace, Subsystem: r.subsystem, Name: name, Help: help, Buckets: buckets,
+		Namespace: r.namespace, Subsystem: r.subsystem, Name: name, Help: help, Buckets: opts.Buckets,
+		NativeHistogramBucketFactor:    opts.NativeBucketFactor,
+		NativeHistogramMaxBucketNumber: opts.NativeMaxBucketNumber,
 	}, labelKeys)
 	prometheus.MustRegister(hv)
 	r.histograms.Store(name, hv)
+	r.record("histogram", name, help, labelKeys)
 	return &histogram{v: hv}
 }
+
+func (r *registry) Gauge(name string, help string, labelKeys ...string) observability.Gauge {
+	if v, ok := r.gauges.Load(name); ok {
+		return &gauge{v: v.(*prometheus.GaugeVec)}
+	}
+	gv := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: r.namespace, Subsystem: r.subsystem, Name: name, Help: help,
+	}, labelKeys)
+	prometheus.MustRegister(gv)
+	r.gauges.Store(name, gv)
+	r.record("gauge", name, help, labelKeys)
+	return &gauge{v: gv}
+}
@@ -0,0 +1,90 @@
+package prometrics_test
+
+import (
+	"testing"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/prometrics"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// reg is shared so repeated benchmark runs get the already registered vectors back.
+var reg = prometrics.New("minishop", "prometrics_test")
+
+func counterValue(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range f.GetMetric() {
+			for _, lp := range m.GetLabel() {
+				if labels[lp.GetName()] != lp.GetValue() {
+					continue metrics
+				}
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestBoundCounterAddsToTheBoundSeries(t *testing.T) {
+	c := reg.Counter("bound_test_total", "test", "route", "status")
+
+	full := c.Bind(observability.Label{Key: "route", Value: "/a"}, observability.Label{Key: "status", Value: "200"})
+	full.Add(2)
+	partial := c.Bind(observability.Label{Key: "route", Value: "/a"})
+	partial.Add(1, observability.Label{Key: "status", Value: "500"})
+
+	const name = "minishop_prometrics_test_bound_test_total"
+	if got := counterValue(t, name, map[string]string{"route": "/a", "status": "200"}); got != 2 {
+		t.Fatalf("fully bound series = %v, want 2", got)
+	}
+	if got := counterValue(t, name, map[string]string{"route": "/a", "status": "500"}); got != 1 {
+		t.Fatalf("partially bound series = %v, want 1", got)
+	}
+}
+
+func BenchmarkBoundCounterAllLabels(b *testing.B) {
+	c := reg.Counter("bench_counter_total", "bench", "route", "status").
+		Bind(observability.Label{Key: "route", Value: "/a"}, observability.Label{Key: "status", Value: "200"})
+	b.ReportAllocs()
+	for b.Loop() {
+		c.Add(1)
+	}
+}
+
+func BenchmarkBoundCounterRemainingLabel(b *testing.B) {
+	c := reg.Counter("bench_counter_total", "bench", "route", "status").
+		Bind(observability.Label{Key: "route", Value: "/a"})
+	status := observability.Label{Key: "status", Value: "200"}
+	b.ReportAllocs()
+	for b.Loop() {
+		c.Add(1, status)
+	}
+}
+
+func BenchmarkBoundHistogramAllLabels(b *testing.B) {
+	h := reg.Histogram("bench_seconds", "bench", nil, "route", "status").
+		Bind(observability.Label{Key: "route", Value: "/a"}, observability.Label{Key: "status", Value: "200"})
+	b.ReportAllocs()
+	for b.Loop() {
+		h.Observe(0.01)
+	}
+}
+
+func BenchmarkBoundHistogramRemainingLabel(b *testing.B) {
+	h := reg.Histogram("bench_seconds", "bench", nil, "route", "status").
+		Bind(observability.Label{Key: "route", Value: "/a"})
+	status := observability.Label{Key: "status", Value: "200"}
+	b.ReportAllocs()
+	for b.Loop() {
+		h.Observe(0.01, status)
+	}
+}
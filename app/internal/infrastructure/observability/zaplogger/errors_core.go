@@ -0,0 +1,43 @@
+package zaplogger
+
+import (
+	"errors"
+	"syscall"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"go.uber.org/zap/zapcore"
+)
+
+// countingCore reports write and sync failures of the wrapped core to a counter.
+type countingCore struct {
+	zapcore.Core
+	errs observability.Counter
+}
+
+func (c *countingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &countingCore{Core: c.Core.With(fields), errs: c.errs}
+}
+
+func (c *countingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *countingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(ent, fields)
+	if err != nil {
+		c.errs.Add(1, observability.L("op", "write"))
+	}
+	return err
+}
+
+func (c *countingCore) Sync() error {
+	err := c.Core.Sync()
+	// stdout/stderr attached to a pipe or terminal can't be fsynced; that isn't a lost log.
+	if err != nil && !errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOTTY) {
+		c.errs.Add(1, observability.L("op", "sync"))
+	}
+	return err
+}
@@ -4,23 +4,88 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 type logger struct{ l *zap.Logger }
 
-func New(fixed ...observability.Field) observability.Logger {
+// New builds a JSON logger writing to stdout and, when LOG_FILE is set, to that file
+// (rotated when WithRotation is given); WithOutputPaths and WithWriteSyncers choose other destinations.
+// If the log file can't be prepared (e.g. a read-only root filesystem) it warns on stdout
+// and continues stdout-only rather than failing startup; use NewStrict to fail instead.
+func New(opts ...Option) observability.Logger {
+	o := newOptions(opts)
+	logFile := o.logFile()
+	if logFile == "" {
+		return mustBuild("", o)
+	}
+	l, err := NewStrict(opts...)
+	if err == nil {
+		return l
+	}
+	l = mustBuild("", o)
+	l.Warn("log_file_unavailable",
+		observability.F("log_file", logFile),
+		observability.F("error", err),
+	)
+	return l
+}
+
+// NewStrict is like New but returns an error when LOG_FILE can't be prepared or opened.
+func NewStrict(opts ...Option) (observability.Logger, error) {
+	o := newOptions(opts)
+	logFile := o.logFile()
+	if logFile != "" {
+		if err := ensureLogFile(logFile); err != nil {
+			return nil, fmt.Errorf("prepare log file: %w", err)
+		}
+	}
+	return build(logFile, o)
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func mustBuild(logFile string, o options) observability.Logger {
+	l, err := build(logFile, o)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+func build(logFile string, o options) (observability.Logger, error) {
 	cfg := zap.NewProductionConfig()
 	cfg.OutputPaths = []string{"stdout"}
 	cfg.ErrorOutputPaths = []string{"stdout"}
-
-	if logFile := os.Getenv("LOG_FILE"); logFile != "" {
-		if err := ensureLogFile(logFile); err != nil {
-			panic(fmt.Errorf("prepare log file: %w", err))
+	if len(o.outputPaths) > 0 {
+		cfg.OutputPaths = o.outputPaths
+	}
+	writers := o.writers
+	if logFile != "" && o.rotation.enabled() {
+		// zap opens output paths as plain files, so the rotating file joins as a writer.
+		out, _, err := zap.Open(cfg.OutputPaths...)
+		if err != nil {
+			return nil, err
 		}
+		writers = []zapcore.WriteSyncer{out, zapcore.AddSync(&lumberjack.Logger{
+			Filename:   logFile,
+			MaxSize:    o.rotation.MaxSizeMB,
+			MaxBackups: o.rotation.MaxBackups,
+			MaxAge:     o.rotation.MaxAgeDays,
+			Compress:   o.rotation.Compress,
+		})}
+	} else if logFile != "" {
 		cfg.OutputPaths = append(cfg.OutputPaths, logFile)
 		cfg.ErrorOutputPaths = append(cfg.ErrorOutputPaths, logFile)
 	}
@@ -31,16 +96,31 @@ func New(fixed ...observability.Field) observability.Logger {
 	cfg.EncoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
 	cfg.EncoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
 
-	cfg.InitialFields = map[string]any{}
-	for _, f := range fixed {
-		cfg.InitialFields[f.Key] = f.Value
+	var buildOpts []zap.Option
+	if len(writers) > 0 {
+		sink := zapcore.NewMultiWriteSyncer(writers...)
+		buildOpts = append(buildOpts, zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			core := zapcore.NewCore(zapcore.NewJSONEncoder(cfg.EncoderConfig), sink, cfg.Level)
+			if s := cfg.Sampling; s != nil {
+				// Mirror the sampling cfg.Build applies to the core being replaced.
+				core = zapcore.NewSamplerWithOptions(core, time.Second, s.Initial, s.Thereafter)
+			}
+			return core
+		}))
 	}
+	if o.writeErrors != nil {
+		buildOpts = append(buildOpts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return &countingCore{Core: core, errs: o.writeErrors}
+		}))
+	}
+	// Added after the core is replaced, unlike cfg.InitialFields, so custom sinks keep them.
+	buildOpts = append(buildOpts, zap.Fields(toZapFields(o.fixed)...))
 
-	l, err := cfg.Build()
+	l, err := cfg.Build(buildOpts...)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &logger{l: l}
+	return &logger{l: l}, nil
 }
 
 func (z *logger) With(fields ...observability.Field) observability.Logger {
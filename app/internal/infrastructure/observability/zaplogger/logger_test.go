@@ -0,0 +1,82 @@
+package zaplogger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/zaplogger"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// decodeLines parses every JSON line written to data.
+func decodeLines(t *testing.T, data string) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("line is not JSON: %q: %v", line, err)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func TestWriteSyncerCapturesJSONEntries(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv("LOG_FILE", logFile)
+
+	var buf bytes.Buffer
+	l := zaplogger.New(
+		zaplogger.WithWriteSyncers(zapcore.AddSync(&buf)),
+		zaplogger.WithFields(observability.F(observability.FieldService, "minishop")),
+	)
+	l.With(observability.F("order_id", "o-1")).Warn("order_stuck", observability.F("attempts", 3))
+
+	entries := decodeLines(t, buf.String())
+	if len(entries) != 1 {
+		t.Fatalf("captured %d entries, want 1:\n%s", len(entries), buf.String())
+	}
+	want := map[string]any{
+		"level":    "warn",
+		"msg":      "order_stuck",
+		"service":  "minishop",
+		"order_id": "o-1",
+		"attempts": float64(3),
+	}
+	for k, v := range want {
+		if entries[0][k] != v {
+			t.Errorf("%s = %v, want %v", k, entries[0][k], v)
+		}
+	}
+	if _, ok := entries[0]["ts"].(string); !ok {
+		t.Errorf("ts = %v, want an RFC 3339 timestamp", entries[0]["ts"])
+	}
+	// Explicit writers replace the default destinations, LOG_FILE included.
+	if _, err := os.Stat(logFile); !os.IsNotExist(err) {
+		t.Errorf("LOG_FILE was written despite WithWriteSyncers (stat error %v)", err)
+	}
+}
+
+func TestOutputPathsChooseTheSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.log")
+	l, err := zaplogger.NewStrict(zaplogger.WithOutputPaths(path))
+	if err != nil {
+		t.Fatalf("NewStrict: %v", err)
+	}
+	l.Info("hello", observability.F("n", 1))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if entries := decodeLines(t, string(data)); len(entries) != 1 || entries[0]["msg"] != "hello" {
+		t.Errorf("output entries = %v, want one hello line", entries)
+	}
+}
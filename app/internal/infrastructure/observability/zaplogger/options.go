@@ -0,0 +1,80 @@
+package zaplogger
+
+import (
+	"os"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"go.uber.org/zap/zapcore"
+)
+
+// Option customises a logger built by New or NewStrict.
+type Option func(*options)
+
+type options struct {
+	fixed       []observability.Field
+	writeErrors observability.Counter
+	outputPaths []string
+	writers     []zapcore.WriteSyncer
+	rotation    Rotation
+}
+
+// WithFields sets fields attached to every entry (e.g. service, env).
+func WithFields(fixed ...observability.Field) Option {
+	return func(o *options) {
+		o.fixed = append(o.fixed, fixed...)
+	}
+}
+
+// WithWriteErrorCounter counts failed writes and syncs (disk full, closed file) on c,
+// labelled op=write|sync, so silent logging failures can be alerted on.
+func WithWriteErrorCounter(c observability.Counter) Option {
+	return func(o *options) {
+		o.writeErrors = c
+	}
+}
+
+// WithOutputPaths replaces the default stdout+LOG_FILE destinations with zap output
+// paths: "stdout", "stderr" or file paths. LOG_FILE is then ignored.
+func WithOutputPaths(paths ...string) Option {
+	return func(o *options) {
+		o.outputPaths = append(o.outputPaths, paths...)
+	}
+}
+
+// WithWriteSyncers sends entries to ws instead of the default destinations (or the ones
+// given to WithOutputPaths), e.g. an in-memory buffer in tests or a rotating file.
+// LOG_FILE is then ignored.
+func WithWriteSyncers(ws ...zapcore.WriteSyncer) Option {
+	return func(o *options) {
+		o.writers = append(o.writers, ws...)
+	}
+}
+
+// Rotation bounds LOG_FILE with a size-based rotating writer. The zero value disables
+// rotation and LOG_FILE grows unbounded.
+type Rotation struct {
+	MaxSizeMB  int  // rotate once the file reaches this size; 0 means 100 MB
+	MaxBackups int  // rotated files kept; 0 keeps all (subject to MaxAgeDays)
+	MaxAgeDays int  // days rotated files are kept; 0 keeps them regardless of age
+	Compress   bool // gzip rotated files
+}
+
+func (r Rotation) enabled() bool {
+	return r.MaxSizeMB > 0 || r.MaxBackups > 0 || r.MaxAgeDays > 0 || r.Compress
+}
+
+// WithRotation rotates LOG_FILE according to r. It has no effect without LOG_FILE or
+// when WithOutputPaths / WithWriteSyncers replace the default destinations.
+func WithRotation(r Rotation) Option {
+	return func(o *options) {
+		o.rotation = r
+	}
+}
+
+// logFile returns LOG_FILE unless explicit outputs replace the default destinations.
+func (o options) logFile() string {
+	if len(o.outputPaths) > 0 || len(o.writers) > 0 {
+		return ""
+	}
+	return os.Getenv("LOG_FILE")
+}
@@ -0,0 +1,64 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+)
+
+// DeadLetterHandler receives an event whose handler still failed after every retry,
+// together with the error of the last attempt. ctx carries the handler's trace and
+// logger but is never cancelled.
+type DeadLetterHandler func(ctx context.Context, e domoutbox.Event, handlerErr error)
+
+// SetDeadLetterHandler routes permanently failed deliveries to h; nil turns it off.
+// One event is dead-lettered once per failing handler.
+func (b *Bus) SetDeadLetterHandler(h DeadLetterHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.deadLetter = h
+}
+
+// DeadLetterEntry is one dead-lettered event with its last handler error.
+type DeadLetterEntry struct {
+	EventName string          `json:"event"`
+	Event     domoutbox.Event `json:"payload"`
+	Error     string          `json:"error"`
+	At        time.Time       `json:"at"`
+}
+
+// MemoryDeadLetters is an in-memory dead-letter sink that keeps the most recent
+// entries; use its Handle method with SetDeadLetterHandler.
+type MemoryDeadLetters struct {
+	mu      sync.Mutex
+	entries []DeadLetterEntry
+	max     int
+}
+
+// NewMemoryDeadLetters retains up to capacity entries, dropping the oldest beyond that.
+func NewMemoryDeadLetters(capacity int) *MemoryDeadLetters {
+	return &MemoryDeadLetters{max: max(capacity, 1)}
+}
+
+// Handle is a DeadLetterHandler.
+func (d *MemoryDeadLetters) Handle(_ context.Context, e domoutbox.Event, handlerErr error) {
+	entry := DeadLetterEntry{EventName: e.EventName(), Event: e, At: time.Now()}
+	if handlerErr != nil {
+		entry.Error = handlerErr.Error()
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.entries) == d.max {
+		d.entries = append(d.entries[:0], d.entries[1:]...)
+	}
+	d.entries = append(d.entries, entry)
+}
+
+// DeadLetters returns the retained entries, oldest first.
+func (d *MemoryDeadLetters) DeadLetters() []DeadLetterEntry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]DeadLetterEntry(nil), d.entries...)
+}
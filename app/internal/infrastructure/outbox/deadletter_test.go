@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+)
+
+func TestAlwaysFailingHandlerLandsInDeadLetters(t *testing.T) {
+	b := NewBus(nil, nil, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	dlq := NewMemoryDeadLetters(10)
+	b.SetDeadLetterHandler(dlq.Handle)
+	var calls atomic.Int32
+	b.Subscribe("inventory.reserved", func(context.Context, domoutbox.Event) error {
+		return fmt.Errorf("attempt %d: payment provider down", calls.Add(1))
+	})
+	b.Subscribe("inventory.reserved", func(context.Context, domoutbox.Event) error { return nil })
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	evt := testEvent{name: "inventory.reserved", seq: 7}
+	if err := b.Publish(context.Background(), evt); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := b.WaitIdle(context.Background()); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+
+	entries := dlq.DeadLetters()
+	if len(entries) != 1 {
+		t.Fatalf("DeadLetters() = %d entries, want 1 (only the failing handler)", len(entries))
+	}
+	got := entries[0]
+	if got.EventName != "inventory.reserved" || got.Event != evt {
+		t.Errorf("dead letter = %s %v, want inventory.reserved %v", got.EventName, got.Event, evt)
+	}
+	// The last attempt's error is kept, not the first.
+	if want := "attempt 3: payment provider down"; got.Error != want {
+		t.Errorf("dead letter error = %q, want %q", got.Error, want)
+	}
+	if got.At.IsZero() {
+		t.Error("dead letter has no timestamp")
+	}
+}
+
+func TestSucceedingHandlerIsNotDeadLettered(t *testing.T) {
+	b := NewBus(nil, nil)
+	dlq := NewMemoryDeadLetters(10)
+	b.SetDeadLetterHandler(dlq.Handle)
+	b.Subscribe("test.event", func(context.Context, domoutbox.Event) error { return nil })
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := b.WaitIdle(context.Background()); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+	if n := len(dlq.DeadLetters()); n != 0 {
+		t.Errorf("DeadLetters() = %d entries, want 0", n)
+	}
+}
+
+func TestMemoryDeadLettersKeepsTheMostRecent(t *testing.T) {
+	dlq := NewMemoryDeadLetters(2)
+	for i := range 3 {
+		dlq.Handle(context.Background(), testEvent{name: "test.event", seq: i}, errors.New("failed"))
+	}
+	entries := dlq.DeadLetters()
+	if len(entries) != 2 {
+		t.Fatalf("DeadLetters() = %d entries, want 2", len(entries))
+	}
+	for i, want := range []int{1, 2} {
+		if seq := entries[i].Event.(testEvent).seq; seq != want {
+			t.Errorf("entry %d is event %d, want %d", i, seq, want)
+		}
+	}
+}
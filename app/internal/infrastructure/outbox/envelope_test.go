@@ -0,0 +1,149 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/testkit/wait"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPublishWrapsEventsInEnvelope(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	b := NewBus(nil, nil, WithSynchronousDispatch())
+	var got []domoutbox.Envelope
+	var spans []trace.SpanContext
+	b.Subscribe("test.event", func(ctx context.Context, e domoutbox.Event) error {
+		env, ok := domoutbox.EnvelopeFrom(ctx)
+		if !ok {
+			t.Error("handler context carries no envelope")
+		}
+		if env.Event != e {
+			t.Errorf("envelope event = %v, want the delivered %v", env.Event, e)
+		}
+		got = append(got, env)
+		spans = append(spans, trace.SpanContextFromContext(ctx))
+		return nil
+	})
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	publisher := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0a, 0x0b, 0x0c},
+		SpanID:     trace.SpanID{0x01, 0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), publisher)
+	for seq := range 2 {
+		if err := b.Publish(ctx, testEvent{name: "test.event", seq: seq}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("handled %d events, want 2", len(got))
+	}
+	if got[0].EventID == "" || got[0].EventID == got[1].EventID {
+		t.Errorf("event IDs = %q, %q; want distinct non-empty IDs", got[0].EventID, got[1].EventID)
+	}
+	for i, env := range got {
+		if env.OccurredAt.IsZero() {
+			t.Errorf("envelope %d has no OccurredAt", i)
+		}
+		if env.TraceContext["traceparent"] == "" {
+			t.Errorf("envelope %d trace context = %v, want a traceparent", i, env.TraceContext)
+		}
+		if spans[i].TraceID() != publisher.TraceID() {
+			t.Errorf("handler %d trace ID = %s, want the publisher's %s", i, spans[i].TraceID(), publisher.TraceID())
+		}
+	}
+}
+
+func TestDetachedDispatchLinksTheDispatchingSpan(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	rec := obstest.New()
+	b := NewBus(nil, rec)
+	done := make(chan struct{})
+	b.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+		close(done)
+		return nil
+	})
+	// The dispatchers inherit this span, which belongs to whoever started the bus rather
+	// than to the publisher.
+	starter := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0d},
+		SpanID:     trace.SpanID{0x0d},
+		TraceFlags: trace.FlagsSampled,
+	})
+	b.Start(trace.ContextWithSpanContext(context.Background(), starter))
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	ctx, producer := rec.Tracer().Start(context.Background(), "producer")
+	if err := b.Publish(ctx, testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	producer.End()
+	<-done
+	wait.For(t, time.Second, func() bool { return len(rec.Spans(spanDispatch)) == 1 })
+
+	dispatch := rec.Spans(spanDispatch)[0]
+	if got, want := dispatch.Parent().SpanID(), producer.SpanContext().SpanID(); got != want {
+		t.Errorf("bus.dispatch parent = %s, want the producer span %s", got, want)
+	}
+	links := dispatch.Links()
+	if len(links) != 1 || !links[0].SpanContext.Equal(starter) {
+		t.Errorf("bus.dispatch links = %v, want one link to the dispatching span %s", links, starter.SpanID())
+	}
+}
+
+func TestRelayPublishesInTheEnqueuingTrace(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	store := memory.NewOutboxStore()
+	uow := memory.NewOrderUnitOfWork(memory.NewOrderRepository(), store)
+	request := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0e},
+		SpanID:     trace.SpanID{0x0e},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), request)
+	err := uow.Do(ctx, func(tx domorder.Tx) error {
+		return tx.Enqueue(ctx, testEvent{name: "test.event"})
+	})
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	b := NewBus(nil, nil, WithSynchronousDispatch())
+	var got trace.SpanContext
+	b.Subscribe("test.event", func(ctx context.Context, _ domoutbox.Event) error {
+		got = trace.SpanContextFromContext(ctx)
+		return nil
+	})
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	// The relay flushes from its own context, long after the request ended.
+	if err := NewRelay(store, b, nil).Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got.TraceID() != request.TraceID() {
+		t.Errorf("handler trace ID = %s, want the enqueuing request's %s", got.TraceID(), request.TraceID())
+	}
+}
@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/testkit/wait"
+)
+
+func TestFanoutRecordsSemaphoreSaturation(t *testing.T) {
+	rec := obstest.New()
+	b := NewBus(nil, rec)
+	limit := b.concurrency
+	extra := 2
+	release := make(chan struct{})
+	for range limit + extra {
+		b.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+			<-release
+			return nil
+		})
+	}
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	event := observability.L("event", "test.event")
+	// The first limit handlers take every slot; the next one finds the semaphore full and
+	// blocks there until a slot frees up.
+	wait.For(t, time.Second, func() bool {
+		return rec.Count(observability.MOutboxFanoutAtCapacity, event) == 1
+	})
+	const held = 20 * time.Millisecond
+	time.Sleep(held)
+	// Free a single slot: the blocked handler takes it, and the last one again finds
+	// every slot held.
+	release <- struct{}{}
+	wait.For(t, time.Second, func() bool {
+		return rec.Count(observability.MOutboxFanoutAtCapacity, event) == 2
+	})
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.WaitIdle(ctx); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+	if got := rec.Count(observability.MOutboxFanoutAtCapacity, event); got != float64(extra) {
+		t.Errorf("outbox_fanout_at_capacity_total = %v, want %d", got, extra)
+	}
+	if got := rec.Observations(observability.MOutboxFanoutWait, event); got != limit+extra {
+		t.Errorf("outbox_fanout_semaphore_wait_seconds observations = %d, want %d", got, limit+extra)
+	}
+	// Count sums the observed waits; the blocked acquisition waited at least as long
+	// as the slots were held.
+	if waited := rec.Count(observability.MOutboxFanoutWait, event); waited < held.Seconds() {
+		t.Errorf("total semaphore wait = %.3fs, want >= %.3fs", waited, held.Seconds())
+	}
+}
+
+func TestFanoutBelowCapacityNeverWaits(t *testing.T) {
+	rec := obstest.New()
+	b := NewBus(nil, rec, WithSynchronousDispatch())
+	for range b.concurrency {
+		b.Subscribe("test.event", func(context.Context, domoutbox.Event) error { return nil })
+	}
+	b.Start(context.Background())
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if got := rec.Count(observability.MOutboxFanoutAtCapacity); got != 0 {
+		t.Errorf("outbox_fanout_at_capacity_total = %v, want 0", got)
+	}
+	if got := rec.Observations(observability.MOutboxFanoutWait); got != b.concurrency {
+		t.Errorf("outbox_fanout_semaphore_wait_seconds observations = %d, want %d", got, b.concurrency)
+	}
+}
+
+func TestSequentialFanoutRunsHandlersInSubscriptionOrder(t *testing.T) {
+	b := NewBus(nil, nil, WithSynchronousDispatch(), WithSequentialFanout())
+	var (
+		mu    sync.Mutex
+		order []int
+	)
+	const handlers = 5
+	for i := range handlers {
+		b.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+			// Earlier handlers sleep longer, so concurrent fanout would finish them last.
+			time.Sleep(time.Duration(handlers-i) * time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, i)
+			return nil
+		})
+	}
+	b.Start(context.Background())
+
+	for range 3 {
+		order = nil
+		if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+		if want := []int{0, 1, 2, 3, 4}; !slices.Equal(order, want) {
+			t.Fatalf("handlers ran in order %v, want %v", order, want)
+		}
+	}
+}
+
+func TestSequentialFanoutKeepsDifferentEventsConcurrent(t *testing.T) {
+	b := NewBus(nil, nil, WithSequentialFanout())
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	for _, name := range []string{"a.event", "b.event"} {
+		b.Subscribe(name, func(_ context.Context, e domoutbox.Event) error {
+			started <- e.EventName()
+			<-release
+			return nil
+		})
+	}
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	for _, name := range []string{"a.event", "b.event"} {
+		if err := b.Publish(context.Background(), testEvent{name: name}); err != nil {
+			t.Fatalf("Publish(%s): %v", name, err)
+		}
+	}
+	// Both handlers must be running at once, each blocked on release.
+	for range 2 {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("handlers for different events did not run concurrently")
+		}
+	}
+	close(release)
+}
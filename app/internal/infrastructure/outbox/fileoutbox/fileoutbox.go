@@ -0,0 +1,534 @@
+// Package fileoutbox is a durable Publisher/Subscriber for local development: events are
+// appended to a JSON-lines file and fsynced before Publish returns, and events not yet
+// acknowledged by every handler are redelivered when the file is reopened and started.
+// A failed delivery is also retried in-run, with backoff, for the handlers that failed.
+// Delivery is at-least-once and otherwise in publish order, so handlers must be
+// idempotent. Each event keeps its envelope (ID, time and publisher trace context), so
+// a redelivered handler still continues the publisher's trace.
+package fileoutbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/domain/clock"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var (
+	_ domoutbox.Publisher  = (*Outbox)(nil)
+	_ domoutbox.Subscriber = (*Outbox)(nil)
+)
+
+const (
+	// DefaultMaxEventBytes is the default limit on an event's serialized payload,
+	// matching Kafka's default message.max.bytes.
+	DefaultMaxEventBytes = 1 << 20
+	// maxLineBytes is the longest line load can read back; WithMaxEventBytes is capped
+	// below it so every accepted event can be replayed.
+	maxLineBytes = 16 << 20
+
+	rejectReasonTooLarge = "too_large"
+
+	defaultRetryBase = 100 * time.Millisecond
+	defaultRetryMax  = 30 * time.Second
+)
+
+// line is one entry in the file: a published event with its envelope, or an ack of an
+// earlier one.
+type line struct {
+	Seq        uint64            `json:"seq,omitempty"`
+	ID         string            `json:"id,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at,omitzero"`
+	Trace      map[string]string `json:"trace,omitempty"`
+	Event      string            `json:"event,omitempty"`
+	Payload    json.RawMessage   `json:"payload,omitempty"`
+	Ack        uint64            `json:"ack,omitempty"`
+}
+
+// retryState tracks an event's failed deliveries within this run.
+type retryState struct {
+	attempts int
+	done     map[string]struct{} // handlers that already succeeded, skipped on retry
+}
+
+type subscription struct {
+	name    string
+	token   domoutbox.SubscriptionToken
+	handler domoutbox.Handler
+}
+
+// Outbox persists events to a file and delivers them to subscribers from a single
+// dispatcher goroutine.
+type Outbox struct {
+	path          string
+	registry      *Registry
+	log           observability.Logger
+	maxEventBytes int
+	retryBase     time.Duration
+	retryMax      time.Duration
+	rejected      observability.Counter // outbox_events_rejected_total{event,reason}
+
+	mu        sync.Mutex
+	file      *os.File
+	seq       uint64
+	unacked   map[uint64]line // written but not yet acknowledged
+	queue     []uint64        // unacked entries awaiting delivery, oldest first
+	retries   map[uint64]*retryState
+	subs      map[string][]subscription
+	lastToken domoutbox.SubscriptionToken
+	started   bool
+	closed    bool
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Option customises an Outbox at Open time.
+type Option func(*Outbox)
+
+// WithMaxEventBytes rejects events whose serialized payload exceeds n bytes (default
+// DefaultMaxEventBytes) with domoutbox.ErrEventTooLarge. n is capped just below the
+// 16 MiB line limit of replay; n <= 0 keeps the default.
+func WithMaxEventBytes(n int) Option {
+	return func(o *Outbox) {
+		if n > 0 {
+			o.maxEventBytes = min(n, maxLineBytes-1024)
+		}
+	}
+}
+
+// WithRetryBackoff sets how long a failed delivery waits before it is retried in-run:
+// base after the first failure, doubling up to max (defaults 100ms and 30s). Events are
+// retried until every handler succeeds or the outbox is closed; values <= 0 keep the
+// defaults.
+func WithRetryBackoff(base, max time.Duration) Option {
+	return func(o *Outbox) {
+		if base > 0 {
+			o.retryBase = base
+		}
+		if max > 0 {
+			o.retryMax = max
+		}
+	}
+}
+
+// WithObservability counts rejected events in outbox_events_rejected_total through tel.
+func WithObservability(tel observability.Observability) Option {
+	return func(o *Outbox) {
+		o.rejected = observability.MetricsOf(tel).Counter(observability.MOutboxEventsRejected)
+	}
+}
+
+// Open reads path (creating it if needed) and queues every unacknowledged event for
+// redelivery once Start is called. Events must be registered in registry.
+func Open(path string, registry *Registry, logger observability.Logger, opts ...Option) (*Outbox, error) {
+	if logger == nil {
+		logger = observability.NopLogger()
+	}
+	o := &Outbox{
+		path:          path,
+		registry:      registry,
+		log:           logger.With(observability.F(observability.FieldComponent, observability.ComponentOutbox)),
+		maxEventBytes: DefaultMaxEventBytes,
+		retryBase:     defaultRetryBase,
+		retryMax:      defaultRetryMax,
+		rejected:      observability.MetricsOf(nil).Counter(observability.MOutboxEventsRejected),
+		unacked:       make(map[uint64]line),
+		retries:       make(map[uint64]*retryState),
+		subs:          make(map[string][]subscription),
+		wake:          make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if err := o.load(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("fileoutbox: open %s: %w", path, err)
+	}
+	o.file = f
+	return o, nil
+}
+
+// load replays the file into unacked and queue.
+func (o *Outbox) load() error {
+	f, err := os.Open(o.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fileoutbox: open %s: %w", o.path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+	for n := 1; scanner.Scan(); n++ {
+		var l line
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			// A torn final write from a crash; everything before it is intact.
+			o.log.Warn("file_outbox_corrupt_line", observability.F("line", n), observability.F("error", err))
+			continue
+		}
+		switch {
+		case l.Ack != 0:
+			delete(o.unacked, l.Ack)
+		case l.Seq != 0:
+			o.unacked[l.Seq] = l
+			o.seq = max(o.seq, l.Seq)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("fileoutbox: read %s: %w", o.path, err)
+	}
+	o.queue = o.pendingSeqs()
+	return nil
+}
+
+func (o *Outbox) pendingSeqs() []uint64 {
+	seqs := make([]uint64, 0, len(o.unacked))
+	for seq := range o.unacked {
+		seqs = append(seqs, seq)
+	}
+	slices.Sort(seqs)
+	return seqs
+}
+
+// Subscribe registers an anonymous handler; it is identified as "<event>#<token>", its
+// subscription token, which is never reused so names stay unique after Unsubscribe.
+func (o *Outbox) Subscribe(eventName string, h domoutbox.Handler) domoutbox.SubscriptionToken {
+	return o.SubscribeNamed("", eventName, h)
+}
+
+// SubscribeNamed registers h for eventName under name.
+func (o *Outbox) SubscribeNamed(name, eventName string, h domoutbox.Handler) domoutbox.SubscriptionToken {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.lastToken++
+	if name == "" {
+		name = fmt.Sprintf("%s#%d", eventName, o.lastToken)
+	}
+	o.subs[eventName] = append(o.subs[eventName], subscription{name: name, token: o.lastToken, handler: h})
+	return o.lastToken
+}
+
+// Unsubscribe removes the handler registered under token.
+func (o *Outbox) Unsubscribe(eventName string, token domoutbox.SubscriptionToken) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.subs[eventName] = slices.DeleteFunc(slices.Clone(o.subs[eventName]), func(s subscription) bool {
+		return s.token == token
+	})
+}
+
+// Publish appends e to the file, with the trace context of ctx, and fsyncs it before
+// queueing delivery. Events larger than the configured limit are rejected with
+// domoutbox.ErrEventTooLarge.
+func (o *Outbox) Publish(ctx context.Context, e domoutbox.Event) error {
+	if e == nil {
+		return nil
+	}
+	name := e.EventName()
+	if !o.registry.Known(name) {
+		return fmt.Errorf("%w: %q", domoutbox.ErrUnknownEvent, name)
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("fileoutbox: encode %s: %w", name, err)
+	}
+	if len(payload) > o.maxEventBytes {
+		o.rejected.Add(1,
+			observability.L("event", name),
+			observability.L("reason", rejectReasonTooLarge),
+		)
+		logctx.FromOr(ctx, o.log).Warn("event_rejected_too_large",
+			observability.F("event", name),
+			observability.F("bytes", len(payload)),
+			observability.F("max_bytes", o.maxEventBytes),
+		)
+		return fmt.Errorf("%w: %s is %d bytes, limit %d", domoutbox.ErrEventTooLarge, name, len(payload), o.maxEventBytes)
+	}
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return domoutbox.ErrBusStopped
+	}
+	l := line{
+		Seq:        o.seq + 1,
+		ID:         uuid.NewString(),
+		OccurredAt: clock.Now(),
+		Trace:      carrier,
+		Event:      name,
+		Payload:    payload,
+	}
+	if err := o.append(l, true); err != nil {
+		return err
+	}
+	o.seq = l.Seq
+	o.unacked[l.Seq] = l
+	o.queue = append(o.queue, l.Seq)
+	o.notify()
+	return nil
+}
+
+// append writes l as one line; callers hold mu.
+func (o *Outbox) append(l line, sync bool) error {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("fileoutbox: encode line: %w", err)
+	}
+	if _, err := o.file.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("fileoutbox: write %s: %w", o.path, err)
+	}
+	if sync {
+		if err := o.file.Sync(); err != nil {
+			return fmt.Errorf("fileoutbox: fsync %s: %w", o.path, err)
+		}
+	}
+	return nil
+}
+
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start launches the dispatcher, which first redelivers events left unacknowledged by
+// a previous run. Subscribe every handler before calling it.
+func (o *Outbox) Start(ctx context.Context) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.started || o.closed {
+		return
+	}
+	o.started = true
+	ctx, o.cancel = context.WithCancel(ctx)
+	o.log.Info("file_outbox_started",
+		observability.F("path", o.path),
+		observability.F("redelivering", len(o.queue)),
+	)
+	o.notify()
+	go o.run(ctx)
+}
+
+func (o *Outbox) run(ctx context.Context) {
+	defer close(o.done)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.wake:
+		}
+		for ctx.Err() == nil {
+			o.mu.Lock()
+			if len(o.queue) == 0 {
+				o.mu.Unlock()
+				break
+			}
+			seq := o.queue[0]
+			o.queue = o.queue[1:]
+			l, ok := o.unacked[seq]
+			handlers := slices.Clone(o.subs[l.Event])
+			o.mu.Unlock()
+
+			if ok {
+				o.deliver(ctx, l, handlers)
+			}
+		}
+	}
+}
+
+// deliver runs every handler for l that has not yet succeeded in this run, under the
+// publisher's trace context and l's envelope. l is acked once all of them have; until
+// then it stays in the file and is retried after a backoff.
+func (o *Outbox) deliver(ctx context.Context, l line, handlers []subscription) {
+	e, err := o.registry.Decode(l.Event, l.Payload)
+	if err != nil {
+		o.log.Error("file_outbox_decode_failed", observability.F("seq", l.Seq), observability.F("error", err))
+		return
+	}
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(l.Trace))
+	ctx = domoutbox.WithEnvelope(ctx, domoutbox.Envelope{
+		EventID:      l.ID,
+		OccurredAt:   l.OccurredAt,
+		TraceContext: l.Trace,
+		Event:        e,
+	})
+
+	o.mu.Lock()
+	state := o.retries[l.Seq]
+	o.mu.Unlock()
+	var failed []string
+	succeeded := make([]string, 0, len(handlers))
+	for _, sub := range handlers {
+		if state != nil {
+			if _, ok := state.done[sub.name]; ok {
+				continue
+			}
+		}
+		if err := invoke(ctx, sub.handler, e); err != nil {
+			failed = append(failed, sub.name)
+			o.log.Warn("event_handler_error",
+				observability.F("event", l.Event),
+				observability.F("handler", sub.name),
+				observability.F("seq", l.Seq),
+				observability.F("error", err),
+			)
+			continue
+		}
+		succeeded = append(succeeded, sub.name)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return
+	}
+	if len(failed) > 0 {
+		o.scheduleRetry(l, succeeded, failed)
+		return
+	}
+	delete(o.retries, l.Seq)
+	// Acks are not fsynced: losing one only causes a redelivery.
+	if err := o.append(line{Ack: l.Seq}, false); err != nil {
+		o.log.Error("file_outbox_ack_failed", observability.F("seq", l.Seq), observability.F("error", err))
+		return
+	}
+	delete(o.unacked, l.Seq)
+}
+
+// scheduleRetry requeues l after a backoff that doubles with each failed attempt,
+// remembering which handlers already succeeded. Callers hold mu.
+func (o *Outbox) scheduleRetry(l line, succeeded, failed []string) {
+	state := o.retries[l.Seq]
+	if state == nil {
+		state = &retryState{done: make(map[string]struct{})}
+		o.retries[l.Seq] = state
+	}
+	for _, name := range succeeded {
+		state.done[name] = struct{}{}
+	}
+	state.attempts++
+	delay := o.retryBase << min(state.attempts-1, 30)
+	if delay <= 0 || delay > o.retryMax {
+		delay = o.retryMax
+	}
+	o.log.Warn("file_outbox_retry_scheduled",
+		observability.F("event", l.Event),
+		observability.F("seq", l.Seq),
+		observability.F("attempt", state.attempts),
+		observability.F("failed_handlers", failed),
+		observability.F("delay", delay.String()),
+	)
+	time.AfterFunc(delay, func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if _, ok := o.unacked[l.Seq]; !ok || o.closed {
+			return
+		}
+		o.queue = append(o.queue, l.Seq)
+		o.notify()
+	})
+}
+
+func invoke(ctx context.Context, h domoutbox.Handler, e domoutbox.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("fileoutbox: handler panicked: %v", r)
+		}
+	}()
+	return h(ctx, e)
+}
+
+// Compact rewrites the file to hold only unacknowledged events, replacing it atomically.
+func (o *Outbox) Compact() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return domoutbox.ErrBusStopped
+	}
+	tmp := o.path + ".compact"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("fileoutbox: compact: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, seq := range o.pendingSeqs() {
+		if err = enc.Encode(o.unacked[seq]); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, o.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("fileoutbox: compact: %w", err)
+	}
+
+	next, err := os.OpenFile(o.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("fileoutbox: reopen %s: %w", o.path, err)
+	}
+	_ = o.file.Close()
+	o.file = next
+	return nil
+}
+
+// Close stops the dispatcher, waiting for the event being delivered (or ctx), and
+// closes the file. Undelivered events are redelivered after the next Open and Start.
+func (o *Outbox) Close(ctx context.Context) error {
+	o.mu.Lock()
+	if o.closed {
+		o.mu.Unlock()
+		return nil
+	}
+	o.closed = true
+	started := o.started
+	o.mu.Unlock()
+
+	if started {
+		o.cancel()
+		select {
+		case <-o.done:
+		case <-ctx.Done():
+		}
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.file.Close()
+}
@@ -0,0 +1,255 @@
+package fileoutbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/testkit/wait"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type testEvent struct {
+	N    int    `json:"n"`
+	Note string `json:"note,omitempty"`
+}
+
+func (testEvent) EventName() string { return "test.event" }
+
+func testRegistry() *Registry {
+	r := NewRegistry()
+	Register[testEvent](r)
+	return r
+}
+
+// open opens the outbox at path and closes it on cleanup.
+func open(t *testing.T, path string, opts ...Option) *Outbox {
+	t.Helper()
+	o, err := Open(path, testRegistry(), nil, opts...)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { o.Close(context.Background()) })
+	return o
+}
+
+// received collects the events a handler was given.
+type received struct {
+	mu  sync.Mutex
+	got []int
+}
+
+func (r *received) handle(_ context.Context, e domoutbox.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.got = append(r.got, e.(testEvent).N)
+	return nil
+}
+
+func (r *received) events() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.got)
+}
+
+func TestUnackedEventsAreRedeliveredAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+
+	// First run: publish without ever starting, as if the process died before delivery.
+	first := open(t, path)
+	for n := 1; n <= 3; n++ {
+		if err := first.Publish(context.Background(), testEvent{N: n}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	if err := first.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	second := open(t, path)
+	var r received
+	second.Subscribe("test.event", r.handle)
+	second.Start(context.Background())
+	wait.For(t, 2*time.Second, func() bool { return len(r.events()) == 3 })
+	if got := r.events(); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("redelivered %v, want [1 2 3] in publish order", got)
+	}
+	if err := second.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Everything was acked, so a third run has nothing to redeliver.
+	third := open(t, path)
+	if n := len(third.queue); n != 0 {
+		t.Errorf("third run queued %d events, want 0", n)
+	}
+}
+
+func TestFailedDeliveryIsRetriedInRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o := open(t, path, WithRetryBackoff(time.Millisecond, 5*time.Millisecond))
+	var ok received
+	var failures atomic.Int32
+	o.Subscribe("test.event", ok.handle)
+	o.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+		if failures.Add(1) <= 2 {
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+	o.Start(context.Background())
+
+	if err := o.Publish(context.Background(), testEvent{N: 1}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	wait.For(t, 2*time.Second, func() bool {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		return len(o.unacked) == 0
+	})
+	if got := failures.Load(); got != 3 {
+		t.Errorf("failing handler ran %d times, want 3", got)
+	}
+	// Only the failed handler is retried; the one that succeeded is not run again.
+	if got := ok.events(); !slices.Equal(got, []int{1}) {
+		t.Errorf("succeeding handler received %v, want [1]", got)
+	}
+	if err := o.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if reopened := open(t, path); len(reopened.queue) != 0 {
+		t.Errorf("acked event queued again after restart: %v", reopened.queue)
+	}
+}
+
+func TestRedeliveredEventKeepsPublisherTraceContext(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	publisher := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0a, 0x0b, 0x0c},
+		SpanID:     trace.SpanID{0x01, 0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	first := open(t, path)
+	ctx := trace.ContextWithSpanContext(context.Background(), publisher)
+	if err := first.Publish(ctx, testEvent{N: 1}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	first.Close(context.Background())
+
+	second := open(t, path)
+	type delivery struct {
+		span trace.SpanContext
+		env  domoutbox.Envelope
+	}
+	got := make(chan delivery, 1)
+	second.Subscribe("test.event", func(ctx context.Context, _ domoutbox.Event) error {
+		env, _ := domoutbox.EnvelopeFrom(ctx)
+		got <- delivery{span: trace.SpanContextFromContext(ctx), env: env}
+		return nil
+	})
+	second.Start(context.Background())
+
+	var d delivery
+	select {
+	case d = <-got:
+	case <-time.After(2 * time.Second):
+		t.Fatal("event was not redelivered")
+	}
+	if d.span.TraceID() != publisher.TraceID() || d.span.SpanID() != publisher.SpanID() {
+		t.Errorf("handler span context = %s/%s, want the publisher's %s/%s",
+			d.span.TraceID(), d.span.SpanID(), publisher.TraceID(), publisher.SpanID())
+	}
+	if d.env.EventID == "" || d.env.OccurredAt.IsZero() {
+		t.Errorf("envelope = %+v, want a persisted event ID and time", d.env)
+	}
+}
+
+func TestCompactKeepsOnlyUnackedEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o := open(t, path)
+	var r received
+	o.Subscribe("test.event", r.handle)
+	o.Start(context.Background())
+	for n := 1; n <= 3; n++ {
+		if err := o.Publish(context.Background(), testEvent{N: n}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	wait.For(t, 2*time.Second, func() bool { return len(r.events()) == 3 })
+	o.Close(context.Background())
+
+	// Reopen without starting: one more event stays unacked across the compaction.
+	o = open(t, path)
+	if err := o.Publish(context.Background(), testEvent{N: 4}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := o.Compact(); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read outbox: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Errorf("compacted file has %d lines, want 1:\n%s", lines, data)
+	}
+	o.Close(context.Background())
+	if reopened := open(t, path); !slices.Equal(reopened.queue, []uint64{4}) {
+		t.Errorf("queue after compaction = %v, want [4]", reopened.queue)
+	}
+}
+
+func TestPublishRejectsOversizedEvent(t *testing.T) {
+	rec := obstest.New()
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o := open(t, path, WithMaxEventBytes(64), WithObservability(rec))
+
+	if err := o.Publish(context.Background(), testEvent{N: 1, Note: "fits"}); err != nil {
+		t.Fatalf("Publish within the limit: %v", err)
+	}
+	err := o.Publish(context.Background(), testEvent{N: 2, Note: strings.Repeat("x", 64)})
+	if !errors.Is(err, domoutbox.ErrEventTooLarge) {
+		t.Fatalf("Publish error = %v, want %v", err, domoutbox.ErrEventTooLarge)
+	}
+	rejected := rec.Count(observability.MOutboxEventsRejected,
+		observability.L("event", "test.event"),
+		observability.L("reason", "too_large"),
+	)
+	if rejected != 1 {
+		t.Errorf("outbox_events_rejected_total{reason=\"too_large\"} = %v, want 1", rejected)
+	}
+	// The rejected event never reached the file.
+	o.Close(context.Background())
+	if reopened := open(t, path); !slices.Equal(reopened.queue, []uint64{1}) {
+		t.Errorf("persisted events = %v, want only [1]", reopened.queue)
+	}
+}
+
+func TestPublishRejectsUnregisteredEvent(t *testing.T) {
+	o := open(t, filepath.Join(t.TempDir(), "outbox.jsonl"))
+	err := o.Publish(context.Background(), unregisteredEvent{})
+	if !errors.Is(err, domoutbox.ErrUnknownEvent) {
+		t.Errorf("Publish error = %v, want %v", err, domoutbox.ErrUnknownEvent)
+	}
+}
+
+type unregisteredEvent struct{}
+
+func (unregisteredEvent) EventName() string { return "unregistered.event" }
@@ -0,0 +1,49 @@
+package fileoutbox
+
+import (
+	"encoding/json"
+	"fmt"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+)
+
+// Registry maps event names to decoders so persisted events can be rebuilt as their
+// concrete types on replay.
+type Registry struct {
+	decoders map[string]func(json.RawMessage) (domoutbox.Event, error)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{decoders: make(map[string]func(json.RawMessage) (domoutbox.Event, error))}
+}
+
+// Register makes events of type E decodable under E's EventName.
+func Register[E domoutbox.Event](r *Registry) {
+	var zero E
+	r.decoders[zero.EventName()] = func(raw json.RawMessage) (domoutbox.Event, error) {
+		var e E
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+}
+
+// Known reports whether name has a decoder.
+func (r *Registry) Known(name string) bool {
+	_, ok := r.decoders[name]
+	return ok
+}
+
+// Decode rebuilds the event persisted under name.
+func (r *Registry) Decode(name string, raw json.RawMessage) (domoutbox.Event, error) {
+	decode, ok := r.decoders[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", domoutbox.ErrUnknownEvent, name)
+	}
+	e, err := decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("fileoutbox: decode %s: %w", name, err)
+	}
+	return e, nil
+}
@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+)
+
+// Middleware wraps every subscribed handler, e.g. for timeouts. Retries are not a
+// middleware: the bus applies its RetryPolicy around the whole chain.
+// Middlewares run inside the bus's per-handler span, so they see the handler's
+// trace, logger (logctx) and cancellation.
+type Middleware func(next domoutbox.Handler) domoutbox.Handler
+
+// Use appends middlewares to the handler chain. The first registered middleware is the
+// outermost. The chain applies to every handler, including ones subscribed earlier.
+func (b *Bus) Use(mws ...Middleware) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.middlewares = append(b.middlewares, mws...)
+}
+
+func chain(h domoutbox.Handler, mws []Middleware) domoutbox.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Recover turns a handler panic into an error inside the chain, so the RetryPolicy
+// retries it like any returned error. Without it the bus still records a panic as the
+// handler's failure, but does not retry it.
+func Recover() Middleware {
+	return func(next domoutbox.Handler) domoutbox.Handler {
+		return func(ctx context.Context, e domoutbox.Event) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("outbox: handler panic: %v\n%s", r, debug.Stack())
+				}
+			}()
+			return next(ctx, e)
+		}
+	}
+}
+
+// Timeout bounds each handler invocation to d, on top of the bus's configured
+// handler timeout.
+func Timeout(d time.Duration) Middleware {
+	return func(next domoutbox.Handler) domoutbox.Handler {
+		return func(ctx context.Context, e domoutbox.Event) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx, e)
+		}
+	}
+}
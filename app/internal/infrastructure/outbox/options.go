@@ -0,0 +1,150 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/health"
+)
+
+// BusOption customises a Bus at construction time.
+type BusOption func(*Bus)
+
+// WithDispatchers sets how many events are dispatched concurrently (default 4).
+// Handlers of a single event are still fanned out under the per-event concurrency cap.
+func WithDispatchers(n int) BusOption {
+	return func(b *Bus) {
+		if n > 0 {
+			b.dispatchers = n
+		}
+	}
+}
+
+// WithEventConcurrency caps how many handlers of a given event name may run at once,
+// across all in-flight events of that name. Event names without a limit are unbounded
+// (beyond the dispatcher count and per-fanout cap). Non-positive limits are ignored.
+func WithEventConcurrency(limits map[string]int) BusOption {
+	return func(b *Bus) {
+		for name, n := range limits {
+			if n <= 0 {
+				continue
+			}
+			b.eventSems[name] = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithGlobalConcurrency caps concurrently running handlers across every event name, to
+// bound total load on shared downstreams. A handler first takes its per-event slot
+// (WithEventConcurrency), then a global one, so an event type waiting on its own limit
+// never holds global slots; the effective cap per event is the smaller of the two.
+// Time spent waiting for a global slot is recorded in bus_handler_wait_seconds.
+// Non-positive n leaves handlers globally unbounded.
+func WithGlobalConcurrency(n int) BusOption {
+	return func(b *Bus) {
+		if n > 0 {
+			b.globalSem = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithRequiredEvents declares event names that must have at least one subscriber;
+// CheckSubscriptions reports any that are missing so wiring bugs fail startup.
+func WithRequiredEvents(names ...string) BusOption {
+	return func(b *Bus) {
+		b.required = append(b.required, names...)
+	}
+}
+
+// WithKnownEvents registers the event names the bus expects. Subscribing to or publishing
+// an unregistered name logs a warning, which catches typos between publishers and
+// subscribers. With no known events registered, every name is accepted silently.
+func WithKnownEvents(names ...string) BusOption {
+	return func(b *Bus) {
+		for _, name := range names {
+			b.known[name] = struct{}{}
+		}
+	}
+}
+
+// WithStrictEvents turns unknown event names into errors: Publish returns
+// domoutbox.ErrUnknownEvent and CheckSubscriptions reports unknown subscriptions.
+func WithStrictEvents() BusOption {
+	return func(b *Bus) {
+		b.strictEvents = true
+	}
+}
+
+// WithNonBlockingPublish makes Publish fail fast with domoutbox.ErrQueueFull when the
+// queue is full, instead of waiting for room until the caller's context is done.
+func WithNonBlockingPublish() BusOption {
+	return func(b *Bus) {
+		b.nonBlocking = true
+	}
+}
+
+// WithSequentialFanout runs one event's handlers one after another in subscription
+// order, for handlers that depend on each other. Different events are still
+// dispatched concurrently by the dispatcher pool.
+func WithSequentialFanout() BusOption {
+	return func(b *Bus) {
+		b.sequential = true
+	}
+}
+
+// WithSynchronousDispatch makes Publish fan the event out on the caller's goroutine and
+// return the handlers' joined errors, so tests can follow a whole event chain without
+// Start, Stop or polling. Handlers that publish recurse inline; per-event concurrency
+// limits still apply and can deadlock such chains if set to 1.
+func WithSynchronousDispatch() BusOption {
+	return func(b *Bus) {
+		b.synchronous = true
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy for failing handlers. Handlers must be
+// idempotent; MaxAttempts 1 disables retries.
+func WithRetryPolicy(p RetryPolicy) BusOption {
+	return func(b *Bus) {
+		b.retry = p
+	}
+}
+
+// WithHandlerTimeout replaces DefaultHandlerTimeout as the deadline for one handler's
+// delivery, retries included. Keep it within the shutdown grace period, or Stop
+// force-cancels handlers that would otherwise have finished. Ignored when d <= 0.
+func WithHandlerTimeout(d time.Duration) BusOption {
+	return func(b *Bus) {
+		if d > 0 {
+			b.timeout = d
+		}
+	}
+}
+
+// WithRetryBudget caps retries across the whole bus at perSecond, allowing bursts of
+// up to burst. While the budget is spent, failing handlers are not retried and their
+// events go straight to the dead-letter handler. Disabled when perSecond <= 0.
+func WithRetryBudget(perSecond float64, burst int) BusOption {
+	return func(b *Bus) {
+		if perSecond > 0 {
+			b.retryBudget = newRetryBudget(perSecond, burst)
+		}
+	}
+}
+
+// WithRecentEvents retains the last n dispatched events (capped at 1000) with their
+// handler outcomes, exposed through Recent for debugging. Disabled when n <= 0.
+func WithRecentEvents(n int) BusOption {
+	return func(b *Bus) {
+		if n > 0 {
+			b.recent = newRecentEvents(n)
+		}
+	}
+}
+
+// WithHealth reports the bus in state as "bus": ready once Start has run, and not
+// ready again as soon as Stop begins draining.
+func WithHealth(state *health.State) BusOption {
+	return func(b *Bus) {
+		b.health = state
+	}
+}
@@ -2,80 +2,381 @@ package outbox
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"runtime/debug"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/domain/clock"
 	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/health"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// errHandlerPanic marks a handler failure recovered from a panic.
+var errHandlerPanic = errors.New("panicked")
+
+// queued is an event's envelope plus the publish-side metadata the dispatcher needs.
+type queued struct {
+	env         domoutbox.Envelope
+	traceID     string
+	publishedAt time.Time
+}
+
+// subscription pairs a handler with the name used to identify it in logs, metrics and spans.
+type subscription struct {
+	name    string
+	token   domoutbox.SubscriptionToken
+	handler domoutbox.Handler
+}
+
+// pattern is a wildcard ("*") or prefix ("order.*") subscription key.
+type pattern struct {
+	key    string
+	prefix string
+}
+
+// patternPrefix reports whether eventName is a pattern and returns the prefix it matches.
+func patternPrefix(eventName string) (string, bool) {
+	if eventName == "*" {
+		return "", true
+	}
+	if prefix, ok := strings.CutSuffix(eventName, "*"); ok && strings.HasSuffix(prefix, ".") {
+		return prefix, true
+	}
+	return "", false
+}
+
 // Bus is an in-memory event bus suitable for demo/testing and simple outbox-like fanout.
 // It is not durable; for production use, persist events (true Outbox pattern) and dispatch from a worker.
+//
+// Events are dispatched by a fixed pool of dispatchers, so different events may be handled
+// concurrently, as may one event's handlers unless WithSequentialFanout. Per-event-name
+// limits (WithEventConcurrency) keep hot event types from saturating their downstreams,
+// and an optional global limit (WithGlobalConcurrency) bounds handlers across all event
+// names.
 type Bus struct {
-	mu          sync.RWMutex
-	subs        map[string][]domoutbox.Handler
-	queue       chan domoutbox.Event
-	startOnce   sync.Once
-	stopOnce    sync.Once
-	cancel      context.CancelFunc
-	concurrency int
+	mu           sync.RWMutex
+	subs         map[string][]subscription   // by event name or pattern
+	patterns     []pattern                   // subscribed patterns, in first-subscription order
+	lastToken    domoutbox.SubscriptionToken // guarded by mu
+	middlewares  []Middleware
+	deadLetter   DeadLetterHandler // nil unless SetDeadLetterHandler
+	queue        chan queued
+	stopOnce     sync.Once
+	cancel       context.CancelFunc
+	concurrency  int
+	dispatchers  int
+	eventSems    map[string]chan struct{} // per-event-name handler limits; read-only after NewBus
+	globalSem    chan struct{}            // bus-wide handler limit; nil unless WithGlobalConcurrency
+	required     []string
+	known        map[string]struct{} // read-only after NewBus; empty accepts every name
+	strictEvents bool
+	nonBlocking  bool          // Publish returns ErrQueueFull instead of waiting
+	sequential   bool          // an event's handlers run one at a time, in subscription order
+	synchronous  bool          // Publish fans out inline and returns the handlers' errors
+	retry        RetryPolicy   // per-handler retries; DefaultRetryPolicy unless WithRetryPolicy
+	timeout      time.Duration // per-handler deadline covering all its attempts; see WithHandlerTimeout
+	retryBudget  *retryBudget  // bus-wide retry rate; nil unless WithRetryBudget
+	unknownSubs  []string      // strict mode: subscriptions to unknown names, reported by CheckSubscriptions
+	pending      atomic.Int64  // events enqueued but not yet fully fanned out
+	dispatched   atomic.Int64  // events fully fanned out since NewBus
+	state        atomic.Int32  // State
+	// handlerCtx is cancelled only when Stop's deadline expires, force-cancelling in-flight handlers.
+	handlerCtx  context.Context
+	forceCancel context.CancelFunc
 	log         observability.Logger
-	tel         observability.Observability
+	tracer      observability.Tracer
+	recent      *recentEvents // nil unless WithRecentEvents
+	health      *health.State // nil unless WithHealth
+
+	handlerDuration observability.Histogram // outbox_handler_duration_seconds{event,handler}
+	handlerWait     observability.Histogram // bus_handler_wait_seconds{event}
+	fanoutWait      observability.Histogram // outbox_fanout_semaphore_wait_seconds{event}
+	fanoutAtCap     observability.Counter   // outbox_fanout_at_capacity_total{event}
+	droppedCounter  observability.Counter   // outbox_events_dropped_total{event,reason}
+	forceCancelled  observability.Counter   // outbox_handlers_force_cancelled_total{event,handler}
+	exhausted       observability.Counter   // outbox_handlers_exhausted_total{event,handler}
+	budgetExhausted observability.Counter   // outbox_retry_budget_exhausted_total{event}
+
+	shutdownDrained   observability.Counter // outbox_shutdown_drained_total
+	shutdownAbandoned observability.Counter // outbox_shutdown_abandoned_total
+
+	queueDepth       observability.Gauge // outbox_queue_depth, set on every enqueue and dequeue
+	handlersInFlight observability.Gauge // outbox_handlers_inflight
 }
 
+// DefaultHandlerTimeout bounds each handler, retries included, unless WithHandlerTimeout.
+const DefaultHandlerTimeout = 30 * time.Second
+
+const (
+	spanHandler  = "Outbox.Handler"
+	spanDispatch = "bus.dispatch"
+
+	healthComponent = "bus"
+
+	dropReasonNoSubscriber = "no_subscriber"
+	dropReasonQueueFull    = "queue_full"
+)
+
 // NewBus creates a bus with a buffered queue and a concurrency cap.
-const componentOutbox = "outbox"
+func NewBus(logger observability.Logger, tel observability.Observability, opts ...BusOption) *Bus {
+	if logger == nil {
+		logger = observability.LoggerOf(tel)
+	}
+	metricsProvider := observability.MetricsOf(tel)
+	b := &Bus{
+		subs:            make(map[string][]subscription),
+		queue:           make(chan queued, 1024), // buffer for backpressure
+		concurrency:     8,                       // per-event handler fanout cap
+		dispatchers:     4,
+		eventSems:       make(map[string]chan struct{}),
+		known:           make(map[string]struct{}),
+		log:             logger.With(observability.F(observability.FieldComponent, observability.ComponentOutbox)),
+		tracer:          observability.TracerOf(tel),
+		handlerDuration: metricsProvider.Histogram(observability.MOutboxHandlerDuration),
+		handlerWait:     metricsProvider.Histogram(observability.MBusHandlerWait),
+		fanoutWait:      metricsProvider.Histogram(observability.MOutboxFanoutWait),
+		fanoutAtCap:     metricsProvider.Counter(observability.MOutboxFanoutAtCapacity),
+		droppedCounter:  metricsProvider.Counter(observability.MOutboxEventsDropped),
+		forceCancelled:  metricsProvider.Counter(observability.MOutboxHandlersForceCancelled),
+		exhausted:       metricsProvider.Counter(observability.MOutboxHandlersExhausted),
+		budgetExhausted: metricsProvider.Counter(observability.MOutboxRetryBudgetExhausted),
+		retry:           DefaultRetryPolicy,
+		timeout:         DefaultHandlerTimeout,
 
-func NewBus(logger observability.Logger, tel observability.Observability) *Bus {
-	return &Bus{
-		subs:        make(map[string][]domoutbox.Handler),
-		queue:       make(chan domoutbox.Event, 1024), // buffer for backpressure
-		concurrency: 8,                                // per-event handler fanout cap
-		log:         logger.With(observability.F("component", componentOutbox)),
-		tel:         tel,
+		shutdownDrained:   metricsProvider.Counter(observability.MOutboxShutdownDrained),
+		shutdownAbandoned: metricsProvider.Counter(observability.MOutboxShutdownAbandoned),
+
+		queueDepth:       metricsProvider.Gauge(observability.MOutboxQueueDepth),
+		handlersInFlight: metricsProvider.Gauge(observability.MOutboxHandlersInFlight),
+	}
+	b.handlerCtx, b.forceCancel = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(b)
 	}
+	b.health.Register(healthComponent)
+	return b
+}
+
+// Subscribe registers an anonymous handler; it is identified as "<event>#<token>", its
+// subscription token, which is never reused so names stay unique after Unsubscribe.
+//
+// eventName may also be "*" (every event) or a prefix pattern such as "order.*". An
+// event reaches its exact-name handlers first, in subscription order, then the handlers
+// of each matching pattern, grouped by pattern in the order patterns were first
+// subscribed. Handlers still run concurrently unless WithSequentialFanout.
+func (b *Bus) Subscribe(eventName string, h domoutbox.Handler) domoutbox.SubscriptionToken {
+	return b.SubscribeNamed("", eventName, h)
 }
 
-func (b *Bus) Subscribe(eventName string, h domoutbox.Handler) {
+// SubscribeNamed registers h under name so it is identifiable in logs, metrics and spans.
+// The returned token removes it again via Unsubscribe.
+func (b *Bus) SubscribeNamed(name, eventName string, h domoutbox.Handler) domoutbox.SubscriptionToken {
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	b.subs[eventName] = append(b.subs[eventName], h)
+	b.lastToken++
+	if name == "" {
+		name = fmt.Sprintf("%s#%d", eventName, b.lastToken)
+	}
+	prefix, isPattern := patternPrefix(eventName)
+	if isPattern {
+		if _, ok := b.subs[eventName]; !ok {
+			b.patterns = append(b.patterns, pattern{key: eventName, prefix: prefix})
+		}
+	} else if b.isUnknown(eventName) {
+		b.log.Warn("event_subscribe_unknown",
+			observability.F("event", eventName),
+			observability.F("handler", name),
+		)
+		if b.strictEvents {
+			b.unknownSubs = append(b.unknownSubs, name)
+		}
+	}
+	b.subs[eventName] = append(b.subs[eventName], subscription{name: name, token: b.lastToken, handler: h})
+	return b.lastToken
+}
+
+// Unsubscribe removes the handler registered under token. Events already handed to it
+// finish normally; events fanned out afterwards no longer reach it.
+func (b *Bus) Unsubscribe(eventName string, token domoutbox.SubscriptionToken) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs := b.subs[eventName]
+	for i, sub := range subs {
+		if sub.token != token {
+			continue
+		}
+		// fanout works on a copy, so a fresh slice keeps in-flight snapshots intact.
+		rest := append(append([]subscription(nil), subs[:i]...), subs[i+1:]...)
+		if len(rest) == 0 {
+			delete(b.subs, eventName)
+			b.patterns = slices.DeleteFunc(slices.Clone(b.patterns), func(p pattern) bool { return p.key == eventName })
+		} else {
+			b.subs[eventName] = rest
+		}
+		return
+	}
+}
+
+// isUnknown reports whether known events are registered and name is not one of them.
+func (b *Bus) isUnknown(name string) bool {
+	if len(b.known) == 0 {
+		return false
+	}
+	_, ok := b.known[name]
+	return !ok
 }
 
+// CheckSubscriptions returns an error naming every required event without a subscriber.
+// Call it after all workers have subscribed.
+func (b *Bus) CheckSubscriptions() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var errs []error
+	for _, name := range b.required {
+		if len(b.matching(name)) == 0 {
+			errs = append(errs, fmt.Errorf("outbox: required event %q has no subscriber", name))
+		}
+	}
+	for _, handler := range b.unknownSubs {
+		errs = append(errs, fmt.Errorf("outbox: handler %q subscribes to an unknown event", handler))
+	}
+	return errors.Join(errs...)
+}
+
+// Start launches the dispatchers, which first drain anything published before Start.
+// Calling Start on a running or stopped bus logs a warning and does nothing.
 func (b *Bus) Start(ctx context.Context) {
-	b.startOnce.Do(func() {
-		bg, cancel := context.WithCancel(ctx)
-		b.cancel = cancel
+	logger := logctx.FromOr(ctx, b.log)
+	if !b.state.CompareAndSwap(int32(StateNew), int32(StateRunning)) {
+		logger.Warn("event_bus_start_ignored", observability.F("state", b.State().String()))
+		return
+	}
+	bg, cancel := context.WithCancel(ctx)
+	b.cancel = cancel
+	for i := 0; i < b.dispatchers; i++ {
 		go b.dispatchLoop(bg)
-		logger := logctx.FromOr(ctx, b.log)
-		logger.Info("event_bus_started")
-	})
+	}
+	b.health.Set(healthComponent, true)
+	logger.Info("event_bus_started",
+		observability.F("buffered", len(b.queue)),
+	)
 }
 
-func (b *Bus) Stop(ctx context.Context) {
+// Stop drains queued and in-flight events (including events handlers publish while
+// draining) until ctx is done. If ctx expires first, in-flight handler contexts are
+// cancelled and Stop returns without waiting for them, bounding shutdown time.
+// A bus that was never started has nothing dispatching, so buffered events are abandoned.
+//
+// drained counts events fully fanned out during Stop; remaining counts events still
+// queued or in flight when Stop gave up, and err is ctx's error in that case. Both are
+// also recorded in outbox_shutdown_drained_total / outbox_shutdown_abandoned_total.
+// Only the first call has effect; later calls log a warning and return zeros.
+func (b *Bus) Stop(ctx context.Context) (drained, remaining int, err error) {
+	logger := logctx.FromOr(ctx, b.log)
+	first := false
 	b.stopOnce.Do(func() {
-		if b.cancel != nil {
-			b.cancel()
+		first = true
+		b.health.Set(healthComponent, false)
+		defer func() {
+			b.shutdownDrained.Add(float64(drained))
+			b.shutdownAbandoned.Add(float64(remaining))
+		}()
+		if b.state.CompareAndSwap(int32(StateNew), int32(StateStopped)) {
+			remaining = int(b.pending.Load())
+			logger.Warn("event_bus_stopped_before_start",
+				observability.F("pending", remaining),
+			)
+			return
 		}
-
-		close(b.queue)
-		logger := logctx.FromOr(ctx, b.log)
-		logger.Info("event_bus_stopped")
+		before := b.dispatched.Load()
+		if err = b.WaitIdle(ctx); err != nil {
+			b.forceCancel()
+			logger.Warn("event_bus_drain_timeout",
+				observability.F("pending", b.pending.Load()),
+				observability.F("error", err),
+			)
+		}
+		b.state.Store(int32(StateStopped))
+		b.cancel()
+		drained = int(b.dispatched.Load() - before)
+		remaining = int(b.pending.Load())
+		logger.Info("event_bus_stopped",
+			observability.F("drained", drained),
+			observability.F("remaining", remaining),
+		)
 	})
+	if !first {
+		logger.Warn("event_bus_stop_ignored", observability.F("state", b.State().String()))
+	}
+	return drained, remaining, err
 }
 
 func (b *Bus) Publish(ctx context.Context, e domoutbox.Event) error {
 	if e == nil {
 		return nil
 	}
+	if b.State() == StateStopped {
+		return domoutbox.ErrBusStopped
+	}
+	if name := e.EventName(); b.isUnknown(name) {
+		logctx.FromOr(ctx, b.log).Warn("event_publish_unknown", observability.F("event", name))
+		if b.strictEvents {
+			return fmt.Errorf("%w: %q", domoutbox.ErrUnknownEvent, name)
+		}
+	}
+	q := queued{env: newEnvelope(ctx, e), publishedAt: time.Now()}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		q.traceID = sc.TraceID().String()
+	}
+	b.pending.Add(1)
+	if b.synchronous {
+		err := b.fanout(ctx, q)
+		b.dispatched.Add(1)
+		b.pending.Add(-1)
+		return err
+	}
+	if b.nonBlocking {
+		select {
+		case b.queue <- q:
+			b.queueDepth.Set(float64(len(b.queue)))
+			logctx.FromOr(ctx, b.log).Debug("event_enqueued", observability.F("event", e.EventName()))
+			return nil
+		default:
+			b.pending.Add(-1)
+			logctx.FromOr(ctx, b.log).Warn("event_enqueue_rejected",
+				observability.F("event", e.EventName()),
+				observability.F("reason", dropReasonQueueFull),
+			)
+			b.droppedCounter.Add(1,
+				observability.L("event", e.EventName()),
+				observability.L("reason", dropReasonQueueFull),
+			)
+			return domoutbox.ErrQueueFull
+		}
+	}
 	select {
-	case b.queue <- e:
+	case b.queue <- q:
+		b.queueDepth.Set(float64(len(b.queue)))
 		logger := logctx.FromOr(ctx, b.log).With(observability.F("event", e.EventName()))
 		logger.Debug("event_enqueued")
 		return nil
 	case <-ctx.Done():
+		b.pending.Add(-1)
 		logger := logctx.FromOr(ctx, b.log).With(observability.F("event", e.EventName()))
 		logger.Warn("event_enqueue_aborted",
 			observability.F("error", ctx.Err()),
@@ -84,73 +385,233 @@ func (b *Bus) Publish(ctx context.Context, e domoutbox.Event) error {
 	}
 }
 
+// WaitIdle blocks until every published event (including events published by handlers
+// while draining) has been fanned out, or ctx is done. Intended for tests and tooling.
+func (b *Bus) WaitIdle(ctx context.Context) error {
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for b.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// newEnvelope wraps e with a fresh ID and the trace context of the publishing span.
+func newEnvelope(ctx context.Context, e domoutbox.Event) domoutbox.Envelope {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return domoutbox.Envelope{
+		EventID:      uuid.NewString(),
+		OccurredAt:   clock.Now(),
+		TraceContext: carrier,
+		Event:        e,
+	}
+}
+
+// matching returns a copy of the handlers an event named name reaches, in delivery
+// order: exact subscriptions, then pattern ones. Callers hold mu.
+func (b *Bus) matching(name string) []subscription {
+	handlers := append([]subscription(nil), b.subs[name]...)
+	for _, p := range b.patterns {
+		if strings.HasPrefix(name, p.prefix) {
+			handlers = append(handlers, b.subs[p.key]...)
+		}
+	}
+	return handlers
+}
+
 func (b *Bus) dispatchLoop(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case e, ok := <-b.queue:
-			if !ok {
-				return
-			}
-			b.fanout(ctx, e)
+		case q := <-b.queue:
+			b.queueDepth.Set(float64(len(b.queue)))
+			b.fanout(ctx, q)
+			b.dispatched.Add(1)
+			b.pending.Add(-1)
 		}
 	}
 }
 
-func (b *Bus) fanout(ctx context.Context, e domoutbox.Event) {
+// fanout runs every handler subscribed to the event and returns their errors joined;
+// dispatchers only log them, synchronous Publish hands them to the caller.
+func (b *Bus) fanout(ctx context.Context, q queued) error {
+	e := q.env.Event
 	name := e.EventName()
 
 	b.mu.RLock()
-	handlers := append([]domoutbox.Handler(nil), b.subs[name]...)
+	handlers := b.matching(name)
+	mws := b.middlewares
+	deadLetter := b.deadLetter
 	b.mu.RUnlock()
 
+	// Each handler goroutine writes only its own slot; wg.Wait orders the writes before the read.
+	outcomes := make([]HandlerOutcome, len(handlers))
+	errs := make([]error, len(handlers))
+	if b.recent != nil {
+		defer func() {
+			b.recent.add(EventRecord{Event: name, PublishedAt: q.publishedAt, TraceID: q.traceID, Handlers: outcomes})
+		}()
+	}
+
 	if len(handlers) == 0 {
+		// Almost always a wiring bug: the event is lost.
 		logger := logctx.FromOr(ctx, b.log).With(observability.F("event", name))
-		logger.Debug("event_dropped_no_subscriber")
-		return
+		logger.Warn("event_dropped_no_subscriber",
+			observability.F("reason", dropReasonNoSubscriber),
+		)
+		b.droppedCounter.Add(1,
+			observability.L("event", name),
+			observability.L("reason", dropReasonNoSubscriber),
+		)
+		return nil
 	}
 
 	ctx = context.WithoutCancel(ctx)
+	// Handlers continue the publisher's trace and can read the envelope. A span already
+	// in ctx belongs to whoever dispatches (detached from the publisher); it is kept as
+	// a link so that trace still reaches this fanout.
+	local := trace.SpanContextFromContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(q.env.TraceContext))
+	producer := trace.SpanContextFromContext(ctx)
+	ctx = domoutbox.WithEnvelope(ctx, q.env)
+	// One span per fanout, between the producer's span and the handler spans; it also
+	// shows how long the event sat in the queue.
+	ctx, dispatchSpan := b.tracer.Start(ctx, spanDispatch,
+		attribute.String("event", name),
+		attribute.String("messaging.message.id", q.env.EventID),
+		attribute.Int("outbox.handlers", len(handlers)),
+		attribute.Float64("outbox.queue_seconds", time.Since(q.publishedAt).Seconds()),
+	)
+	if local.IsValid() && !local.Equal(producer) {
+		dispatchSpan.AddLink(trace.Link{SpanContext: local})
+	}
 	baseLogger := b.log
 	ctx = logctx.With(ctx, baseLogger)
 
 	sem := make(chan struct{}, b.concurrency)
+	eventSem := b.eventSems[name]
 	var wg sync.WaitGroup
 
-	for _, h := range handlers {
-		sem <- struct{}{}
+	for i, sub := range handlers {
+		// Every acquisition is observed, so the histogram count is the number of handler
+		// starts and the at-capacity counter the share of them that had to wait.
+		waitStart := time.Now()
+		select {
+		case sem <- struct{}{}:
+		default:
+			b.fanoutAtCap.Add(1, observability.L("event", name))
+			sem <- struct{}{}
+		}
+		b.fanoutWait.Observe(time.Since(waitStart).Seconds(), observability.L("event", name))
 		wg.Add(1)
-		go func() {
+		b.handlersInFlight.Add(1)
+		// i and sub are passed explicitly so each goroutine keeps its own handler even
+		// under pre-1.22 loop variable semantics.
+		go func(i int, sub subscription) {
 			defer func() {
-				if r := recover(); r != nil {
-					logger := logctx.FromOr(ctx, b.log).With(observability.F("event", name))
-					logger.Error("event_handler_panic",
-						observability.F("event", name),
-						observability.F("panic", r),
-						observability.F("stack", string(debug.Stack())),
-					)
-				}
+				b.handlersInFlight.Add(-1)
 				<-sem
 				wg.Done()
 			}()
 
-			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-			ctx = logctx.With(ctx, baseLogger.With(observability.F("event", name)))
-			err := h(ctx, e)
-			cancel()
-			if err != nil {
-				baseLogger.Warn("event_handler_error",
-					observability.F("error", err),
+			if eventSem != nil {
+				eventSem <- struct{}{}
+				defer func() { <-eventSem }()
+			}
+			if b.globalSem != nil {
+				waitStart := time.Now()
+				b.globalSem <- struct{}{}
+				b.handlerWait.Observe(time.Since(waitStart).Seconds(), observability.L("event", name))
+				defer func() { <-b.globalSem }()
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, b.timeout)
+			defer cancel()
+			// Runs only if Stop's deadline expires while this handler is still in flight.
+			stopForce := context.AfterFunc(b.handlerCtx, func() {
+				cancel()
+				b.forceCancelled.Add(1,
+					observability.L("event", name),
+					observability.L("handler", sub.name),
 				)
+				baseLogger.Warn("event_handler_force_cancelled",
+					observability.F("event", name),
+					observability.F("handler", sub.name),
+				)
+			})
+			defer stopForce()
+			handlerLogger := baseLogger.With(
+				observability.F("event", name),
+				observability.F("handler", sub.name),
+			)
+			ctx = logctx.With(ctx, handlerLogger)
+			ctx, span := b.tracer.Start(ctx, spanHandler,
+				attribute.String("event", name),
+				attribute.String("outbox.handler", sub.name),
+				attribute.String("messaging.message.id", q.env.EventID),
+			)
+			defer span.End()
+
+			start := time.Now()
+			err := func() (err error) {
+				// A panic is recorded as this handler's failure, with the same duration,
+				// span status and dead letter as a returned error.
+				defer func() {
+					if r := recover(); r != nil {
+						handlerLogger.Error("event_handler_panic",
+							observability.F("panic", r),
+							observability.F("stack", string(debug.Stack())),
+						)
+						err = fmt.Errorf("outbox: handler %s: %w: %v", sub.name, errHandlerPanic, r)
+					}
+				}()
+				return b.invoke(ctx, chain(sub.handler, mws), e, sub.name)
+			}()
+			b.handlerDuration.Observe(time.Since(start).Seconds(),
+				observability.L("event", name),
+				observability.L("handler", sub.name),
+			)
+			outcomes[i] = HandlerOutcome{Handler: sub.name, Outcome: "ok"}
+			if err == nil {
+				return
 			}
-		}()
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "HANDLER_FAILED")
+			outcomes[i].Outcome, outcomes[i].Error = "error", err.Error()
+			if errors.Is(err, errHandlerPanic) {
+				outcomes[i].Outcome = "panic"
+			}
+			errs[i] = err
+			baseLogger.Warn("event_handler_error",
+				observability.F("event", name),
+				observability.F("handler", sub.name),
+				observability.F("error", err),
+			)
+			if deadLetter != nil {
+				deadLetter(context.WithoutCancel(ctx), e, err)
+			}
+		}(i, sub)
+		if b.sequential {
+			wg.Wait()
+		}
 	}
 
 	wg.Wait()
 
+	err := errors.Join(errs...)
+	if err != nil {
+		dispatchSpan.SetStatus(codes.Error, "HANDLER_FAILED")
+	}
+	dispatchSpan.End()
 	baseLogger.Debug("event_fanned_out",
 		observability.F("event", name),
 		observability.F("handlers", len(handlers)),
 	)
+	return err
 }
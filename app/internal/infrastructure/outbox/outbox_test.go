@@ -0,0 +1,156 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/testkit/wait"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// testEvent is an event whose name is chosen per test.
+type testEvent struct {
+	name string
+	seq  int
+}
+
+func (e testEvent) EventName() string { return e.name }
+
+func TestQueueDepthAndInFlightGaugesRiseAndFall(t *testing.T) {
+	rec := obstest.New()
+	b := NewBus(nil, rec, WithDispatchers(2))
+	release := make(chan struct{})
+	b.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+		<-release
+		return nil
+	})
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	const events = 10
+	for i := range events {
+		if err := b.Publish(context.Background(), testEvent{name: "test.event", seq: i}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	// Both dispatchers are stuck in the slow handler; everything else waits in the queue.
+	wait.For(t, time.Second, func() bool {
+		return rec.GaugeValue(observability.MOutboxHandlersInFlight) == 2 &&
+			rec.GaugeValue(observability.MOutboxQueueDepth) == events-2
+	})
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.WaitIdle(ctx); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+	if got := rec.GaugeValue(observability.MOutboxQueueDepth); got != 0 {
+		t.Errorf("outbox_queue_depth = %v after draining, want 0", got)
+	}
+	wait.For(t, time.Second, func() bool { return rec.GaugeValue(observability.MOutboxHandlersInFlight) == 0 })
+}
+
+func TestHandlerTimeoutBoundsEachDelivery(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	b := NewBus(nil, nil, WithHandlerTimeout(timeout))
+	remaining := make(chan time.Duration, 1)
+	b.Subscribe("test.event", func(ctx context.Context, _ domoutbox.Event) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return errors.New("handler context has no deadline")
+		}
+		remaining <- time.Until(deadline)
+		return nil
+	})
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	select {
+	case got := <-remaining:
+		if got <= 0 || got > timeout {
+			t.Errorf("handler deadline in %v, want within (0, %v]", got, timeout)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler did not run, or its context had no deadline")
+	}
+}
+
+func TestFanoutRunsEachDistinctHandlerExactlyOnce(t *testing.T) {
+	// More handlers than the default fanout cap, so goroutines are started while
+	// earlier ones are still running.
+	const handlers = 20
+	b := NewBus(nil, nil)
+	var runs [handlers]atomic.Int32
+	for i := range handlers {
+		b.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+			runs[i].Add(1)
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+	}
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.WaitIdle(ctx); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+	for i := range runs {
+		if n := runs[i].Load(); n != 1 {
+			t.Errorf("handler %d ran %d times, want 1", i, n)
+		}
+	}
+}
+
+func TestPanickingHandlerIsRecordedAsAFailure(t *testing.T) {
+	rec := obstest.New()
+	b := NewBus(nil, rec, WithRecentEvents(1))
+	dlq := NewMemoryDeadLetters(1)
+	b.SetDeadLetterHandler(dlq.Handle)
+	b.SubscribeNamed("broken", "test.event", func(context.Context, domoutbox.Event) error {
+		panic("nil map")
+	})
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := b.WaitIdle(context.Background()); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+
+	handler := []observability.Label{observability.L("event", "test.event"), observability.L("handler", "broken")}
+	if n := rec.Observations(observability.MOutboxHandlerDuration, handler...); n != 1 {
+		t.Errorf("outbox_handler_duration_seconds observations = %d, want 1", n)
+	}
+	if n := len(dlq.DeadLetters()); n != 1 {
+		t.Errorf("DeadLetters() = %d entries, want 1", n)
+	}
+	if got := rec.GaugeValue(observability.MOutboxHandlersInFlight); got != 0 {
+		t.Errorf("outbox_handlers_inflight = %v after the panic, want 0", got)
+	}
+	spans := rec.Spans(spanHandler)
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+		t.Errorf("handler spans = %d, want 1 ended with status Error", len(spans))
+	}
+	recent := b.Recent()
+	if len(recent) != 1 || recent[0].Handlers[0].Outcome != "panic" {
+		t.Errorf("recent events = %+v, want the handler's outcome recorded as panic", recent)
+	}
+}
@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"sync"
+	"time"
+)
+
+const maxRecentEvents = 1000
+
+// EventRecord describes one dispatched event for debugging.
+type EventRecord struct {
+	Event       string           `json:"event"`
+	PublishedAt time.Time        `json:"published_at"`
+	TraceID     string           `json:"trace_id,omitempty"`
+	Handlers    []HandlerOutcome `json:"handlers"`
+}
+
+// HandlerOutcome is the result of a single handler invocation: "ok", "error" or "panic".
+type HandlerOutcome struct {
+	Handler string `json:"handler"`
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+// recentEvents is a fixed-size ring buffer of dispatched events.
+type recentEvents struct {
+	mu   sync.Mutex
+	buf  []EventRecord
+	next int
+	full bool
+}
+
+func newRecentEvents(n int) *recentEvents {
+	return &recentEvents{buf: make([]EventRecord, min(n, maxRecentEvents))}
+}
+
+func (r *recentEvents) add(rec EventRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.buf[r.next] = rec
+	r.next = (r.next + 1) % len(r.buf)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// snapshot returns the retained records, oldest first.
+func (r *recentEvents) snapshot() []EventRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]EventRecord(nil), r.buf[:r.next]...)
+	}
+	out := make([]EventRecord, 0, len(r.buf))
+	out = append(out, r.buf[r.next:]...)
+	return append(out, r.buf[:r.next]...)
+}
+
+// Recent returns the last dispatched events, oldest first, or nil unless the bus was
+// created WithRecentEvents.
+func (b *Bus) Recent() []EventRecord {
+	if b.recent == nil {
+		return nil
+	}
+	return b.recent.snapshot()
+}
@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	defaultRelayInterval = 50 * time.Millisecond
+	defaultRelayBatch    = 100
+)
+
+// Relay moves committed records from a transactional outbox Store to a Publisher.
+// Records are removed only after they were published, so delivery is at-least-once.
+type Relay struct {
+	store     domoutbox.Store
+	publisher domoutbox.Publisher
+	interval  time.Duration
+	batch     int
+	log       observability.Logger
+}
+
+// RelayOption customises a Relay at construction time.
+type RelayOption func(*Relay)
+
+// WithRelayInterval sets how often the store is polled (default 50ms).
+func WithRelayInterval(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		if d > 0 {
+			r.interval = d
+		}
+	}
+}
+
+// WithRelayBatch caps how many records are published per poll (default 100).
+func WithRelayBatch(n int) RelayOption {
+	return func(r *Relay) {
+		if n > 0 {
+			r.batch = n
+		}
+	}
+}
+
+func NewRelay(store domoutbox.Store, publisher domoutbox.Publisher, logger observability.Logger, opts ...RelayOption) *Relay {
+	if logger == nil {
+		logger = observability.NopLogger()
+	}
+	r := &Relay{
+		store:     store,
+		publisher: publisher,
+		interval:  defaultRelayInterval,
+		batch:     defaultRelayBatch,
+		log:       logger.With(observability.F(observability.FieldComponent, observability.ComponentOutboxRelay)),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run polls until ctx is done, then makes a final pass so committed records are not
+// left behind on a clean shutdown.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = r.Flush(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+			_ = r.Flush(ctx)
+		}
+	}
+}
+
+// Flush publishes pending records in order until the store is empty or a publish fails.
+// A failed record stays pending and blocks later ones, preserving per-store order.
+func (r *Relay) Flush(ctx context.Context) error {
+	for {
+		recs, err := r.store.Pending(ctx, r.batch)
+		if err != nil {
+			r.log.Warn("outbox_relay_read_failed", observability.F("error", err.Error()))
+			return err
+		}
+		if len(recs) == 0 {
+			return nil
+		}
+
+		published := make([]uint64, 0, len(recs))
+		var pubErr error
+		for _, rec := range recs {
+			// Publish within the enqueuing request's trace rather than the relay's.
+			pubCtx := otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(rec.TraceContext))
+			if pubErr = r.publisher.Publish(pubCtx, rec.Event); pubErr != nil {
+				r.log.Warn("outbox_relay_publish_failed",
+					observability.F("event", rec.Event.EventName()),
+					observability.F("record_id", rec.ID),
+					observability.F("error", pubErr.Error()),
+				)
+				break
+			}
+			published = append(published, rec.ID)
+		}
+
+		if err := r.store.MarkPublished(ctx, published...); err != nil {
+			r.log.Warn("outbox_relay_mark_failed", observability.F("error", err.Error()))
+			return err
+		}
+		if len(published) > 0 {
+			r.log.Debug("outbox_relayed", observability.F("count", len(published)))
+		}
+		if pubErr != nil {
+			return pubErr
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package outbox
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RetryPolicy bounds how often the bus re-invokes a failing handler before giving up
+// on the event for that handler. Waits grow exponentially from BaseDelay. It is the
+// bus's only retry mechanism; set MaxAttempts to 1 to leave retrying to the handler.
+type RetryPolicy struct {
+	MaxAttempts int           // total tries including the first; < 1 means a single try
+	BaseDelay   time.Duration // wait before the first retry, doubled for each later one
+	MaxDelay    time.Duration // cap on a single wait; 0 leaves it uncapped
+	Jitter      float64       // fraction of each wait that is randomised, 0..1
+}
+
+// DefaultRetryPolicy tries each handler once: the bus cannot tell a transient failure
+// from one that will fail again, so retrying is opt-in. The delays apply once
+// MaxAttempts is raised, starting at about 100ms.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 1,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	Jitter:      0.2,
+}
+
+// delay is the wait before retry number retry (1-based).
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := p.BaseDelay << (retry - 1)
+	if d < 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+	if j := min(max(p.Jitter, 0), 1); j > 0 && d > 0 {
+		d = time.Duration(float64(d) * (1 - j + 2*j*rand.Float64()))
+	}
+	return d
+}
+
+// retryBudget is a token bucket shared by every handler on the bus, so that an outage
+// downstream cannot turn into a retry storm. A nil budget allows every retry.
+type retryBudget struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRetryBudget(perSecond float64, burst int) *retryBudget {
+	b := max(float64(burst), 1)
+	return &retryBudget{rate: perSecond, burst: b, tokens: b, last: time.Now()}
+}
+
+// allow takes a token for one retry, reporting false once the budget is spent.
+func (r *retryBudget) allow() bool {
+	if r == nil {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.tokens = min(r.burst, r.tokens+now.Sub(r.last).Seconds()*r.rate)
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// invoke runs h under the retry policy. Retries stop early when ctx is done or the bus
+// retry budget is spent; a handler that still fails is logged as event_handler_exhausted
+// and counted.
+func (b *Bus) invoke(ctx context.Context, h domoutbox.Handler, e domoutbox.Event, handler string) error {
+	name := e.EventName()
+	attempts := max(b.retry.MaxAttempts, 1)
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		if err = h(ctx, e); err == nil || attempt == attempts {
+			break
+		}
+		if !b.retryBudget.allow() {
+			b.budgetExhausted.Add(1, observability.L("event", name))
+			b.log.Warn("event_handler_retry_budget_exhausted",
+				observability.F("event", name),
+				observability.F("handler", handler),
+				observability.F("attempts", attempt),
+				observability.F("error", err),
+			)
+			return err
+		}
+		wait := b.retry.delay(attempt)
+		trace.SpanFromContext(ctx).AddEvent("outbox.handler_retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt),
+			attribute.String("error", err.Error()),
+		))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil && attempts > 1 {
+		b.exhausted.Add(1,
+			observability.L("event", name),
+			observability.L("handler", handler),
+		)
+		b.log.Warn("event_handler_exhausted",
+			observability.F("event", name),
+			observability.F("handler", handler),
+			observability.F("attempts", attempt),
+			observability.F("error", err),
+		)
+	}
+	return err
+}
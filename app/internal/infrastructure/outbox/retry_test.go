@@ -0,0 +1,141 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/testkit/wait"
+)
+
+func TestRetryPolicyDelayBacksOffExponentially(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 35 * time.Millisecond}
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 35 * time.Millisecond, 35 * time.Millisecond}
+	for i, w := range want {
+		if got := p.delay(i + 1); got != w {
+			t.Errorf("delay(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+func TestRetryPolicyDelayJitterStaysInBounds(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: 0.2}
+	for range 1000 {
+		if got := p.delay(1); got < 80*time.Millisecond || got > 120*time.Millisecond {
+			t.Fatalf("delay(1) = %v, want within 100ms ±20%%", got)
+		}
+	}
+}
+
+func TestDefaultPolicyRunsAFailingHandlerOnce(t *testing.T) {
+	b := NewBus(nil, nil)
+	var calls atomic.Int32
+	b.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+		calls.Add(1)
+		return errors.New("order already settled")
+	})
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := b.WaitIdle(context.Background()); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler ran %d times, want 1 (retries are opt-in)", got)
+	}
+}
+
+func TestBusRetriesFailingHandlerUntilItSucceeds(t *testing.T) {
+	rec := obstest.New()
+	b := NewBus(nil, rec, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	var calls atomic.Int32
+	b.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+		if calls.Add(1) < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := b.WaitIdle(context.Background()); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("handler ran %d times, want 3", got)
+	}
+	if n := rec.Count(observability.MOutboxHandlersExhausted); n != 0 {
+		t.Errorf("outbox_handlers_exhausted_total = %v, want 0", n)
+	}
+}
+
+func TestBusCountsExhaustedHandlerByName(t *testing.T) {
+	rec := obstest.New()
+	b := NewBus(nil, rec, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+	var calls atomic.Int32
+	b.SubscribeNamed("healthy", "test.event", func(context.Context, domoutbox.Event) error { return nil })
+	b.SubscribeNamed("failing", "test.event", func(context.Context, domoutbox.Event) error {
+		calls.Add(1)
+		return errors.New("permanent")
+	})
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := b.WaitIdle(context.Background()); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("failing handler ran %d times, want 2", got)
+	}
+	got := rec.Count(observability.MOutboxHandlersExhausted,
+		observability.L("event", "test.event"),
+		observability.L("handler", "failing"),
+	)
+	if got != 1 {
+		t.Errorf("outbox_handlers_exhausted_total{handler=\"failing\"} = %v, want 1 (series: %v)",
+			got, rec.Series(observability.MOutboxHandlersExhausted))
+	}
+}
+
+func TestBusStopsRetryingWhenHandlerContextIsCancelled(t *testing.T) {
+	rec := obstest.New()
+	b := NewBus(nil, rec, WithRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}))
+	var calls atomic.Int32
+	b.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+		calls.Add(1)
+		return errors.New("down")
+	})
+	b.Start(context.Background())
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	wait.For(t, time.Second, func() bool { return calls.Load() == 1 })
+
+	// Stop's deadline expiring cancels in-flight handler contexts, which must cut the
+	// hour-long backoff short.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	b.Stop(ctx)
+
+	wait.For(t, time.Second, func() bool {
+		return rec.Count(observability.MOutboxHandlersExhausted, observability.L("event", "test.event")) == 1
+	})
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler ran %d times, want 1", got)
+	}
+}
@@ -0,0 +1,32 @@
+package outbox
+
+// State is the Bus lifecycle phase.
+type State int32
+
+const (
+	// StateNew: constructed but not started. Publish is accepted and buffered (up to the
+	// queue capacity); buffered events are dispatched once Start is called.
+	StateNew State = iota
+	// StateRunning: dispatchers are consuming the queue.
+	StateRunning
+	// StateStopped: Stop has completed; Publish returns domoutbox.ErrBusStopped.
+	StateStopped
+)
+
+func (s State) String() string {
+	switch s {
+	case StateNew:
+		return "new"
+	case StateRunning:
+		return "running"
+	case StateStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// State reports the current lifecycle phase, e.g. for readiness checks.
+func (b *Bus) State() State {
+	return State(b.state.Load())
+}
@@ -0,0 +1,40 @@
+package outbox
+
+// Stats is a point-in-time view of the bus for debug endpoints.
+type Stats struct {
+	State         string         `json:"state"`
+	QueueDepth    int            `json:"queue_depth"`
+	QueueCapacity int            `json:"queue_capacity"`
+	Pending       int64          `json:"pending"`    // queued or being fanned out
+	Dispatched    int64          `json:"dispatched"` // fully fanned out since NewBus
+	Subscriptions map[string]int `json:"subscriptions"`
+	// HandlerErrors counts error and panic outcomes per handler among the retained
+	// recent events; empty unless the bus was created WithRecentEvents.
+	HandlerErrors map[string]int `json:"recent_handler_errors"`
+}
+
+// Stats reports queue depth, subscription counts per event name and recent handler errors.
+func (b *Bus) Stats() Stats {
+	s := Stats{
+		State:         b.State().String(),
+		QueueDepth:    len(b.queue),
+		QueueCapacity: cap(b.queue),
+		Pending:       b.pending.Load(),
+		Dispatched:    b.dispatched.Load(),
+		Subscriptions: make(map[string]int),
+		HandlerErrors: make(map[string]int),
+	}
+	b.mu.RLock()
+	for name, subs := range b.subs {
+		s.Subscriptions[name] = len(subs)
+	}
+	b.mu.RUnlock()
+	for _, rec := range b.Recent() {
+		for _, h := range rec.Handlers {
+			if h.Outcome != "ok" {
+				s.HandlerErrors[h.Handler]++
+			}
+		}
+	}
+	return s
+}
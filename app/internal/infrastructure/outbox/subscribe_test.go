@@ -0,0 +1,188 @@
+package outbox
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+)
+
+// deliveries records which named handlers received which events, in arrival order.
+type deliveries struct {
+	mu  sync.Mutex
+	got []string
+}
+
+func (d *deliveries) handler(name string) domoutbox.Handler {
+	return func(_ context.Context, e domoutbox.Event) error {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.got = append(d.got, name+":"+e.EventName())
+		return nil
+	}
+}
+
+func (d *deliveries) take() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := d.got
+	d.got = nil
+	return out
+}
+
+func TestBusPatternSubscriptions(t *testing.T) {
+	b := NewBus(nil, nil, WithSynchronousDispatch(), WithSequentialFanout())
+	var d deliveries
+	b.SubscribeNamed("all", "*", d.handler("all"))
+	b.SubscribeNamed("orders", "order.*", d.handler("orders"))
+	b.SubscribeNamed("exact", "order.created", d.handler("exact"))
+	b.Start(context.Background())
+
+	tests := []struct {
+		event string
+		want  []string
+	}{
+		// Exact subscriptions come first, then patterns in first-subscription order.
+		{"order.created", []string{"exact:order.created", "all:order.created", "orders:order.created"}},
+		{"order.cancelled", []string{"all:order.cancelled", "orders:order.cancelled"}},
+		{"inventory.reserved", []string{"all:inventory.reserved"}},
+		// "order.*" matches by the "order." prefix, not by "order".
+		{"orders.archived", []string{"all:orders.archived"}},
+	}
+	for _, tt := range tests {
+		if err := b.Publish(context.Background(), testEvent{name: tt.event}); err != nil {
+			t.Fatalf("Publish(%s): %v", tt.event, err)
+		}
+		if got := d.take(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s delivered to %v, want %v", tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestBusUnsubscribePattern(t *testing.T) {
+	b := NewBus(nil, nil, WithSynchronousDispatch())
+	var d deliveries
+	token := b.SubscribeNamed("orders", "order.*", d.handler("orders"))
+	b.Start(context.Background())
+
+	b.Unsubscribe("order.*", token)
+	if err := b.Publish(context.Background(), testEvent{name: "order.created"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if got := d.take(); len(got) != 0 {
+		t.Errorf("unsubscribed pattern still received %v", got)
+	}
+}
+
+func TestBusDefaultHandlerNamesStayUniqueAfterUnsubscribe(t *testing.T) {
+	b := NewBus(nil, nil, WithSynchronousDispatch(), WithRecentEvents(1))
+	noop := func(context.Context, domoutbox.Event) error { return nil }
+	first := b.Subscribe("order.created", noop)
+	b.Subscribe("order.created", noop)
+	b.Unsubscribe("order.created", first)
+	b.Subscribe("order.created", noop)
+	b.Start(context.Background())
+
+	if err := b.Publish(context.Background(), testEvent{name: "order.created"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	recent := b.Recent()
+	if len(recent) != 1 {
+		t.Fatalf("Recent() = %d records, want 1", len(recent))
+	}
+	var names []string
+	for _, h := range recent[0].Handlers {
+		names = append(names, h.Handler)
+	}
+	if len(names) != 2 || names[0] == names[1] {
+		t.Errorf("handler names = %v, want two distinct names", names)
+	}
+}
+
+func TestUnsubscribeMidFlightStopsFurtherDeliveries(t *testing.T) {
+	b := NewBus(nil, nil)
+	var calls atomic.Int32
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	token := b.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+		if calls.Add(1) == 1 {
+			close(entered)
+			<-release
+			close(finished)
+		}
+		return nil
+	})
+	var others atomic.Int32
+	b.Subscribe("test.event", func(context.Context, domoutbox.Event) error {
+		others.Add(1)
+		return nil
+	})
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+
+	if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	<-entered
+	b.Unsubscribe("test.event", token)
+	close(release)
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("in-flight delivery was not allowed to finish")
+	}
+
+	for range 3 {
+		if err := b.Publish(context.Background(), testEvent{name: "test.event"}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.WaitIdle(ctx); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("unsubscribed handler ran %d times, want 1", n)
+	}
+	if n := others.Load(); n != 4 {
+		t.Errorf("remaining handler ran %d times, want 4", n)
+	}
+}
+
+func TestConcurrentSubscribeAndUnsubscribe(t *testing.T) {
+	b := NewBus(nil, nil)
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+	noop := func(context.Context, domoutbox.Event) error { return nil }
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				token := b.Subscribe("test.event", noop)
+				_ = b.Publish(context.Background(), testEvent{name: "test.event"})
+				b.Unsubscribe("test.event", token)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.WaitIdle(ctx); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if n := len(b.subs["test.event"]); n != 0 {
+		t.Errorf("%d subscriptions left after unsubscribing all of them", n)
+	}
+}
@@ -0,0 +1,39 @@
+package repolog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	dominv "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+)
+
+var _ dominv.Repository = (*InventoryRepository)(nil)
+
+// InventoryRepository logs every reservation made through the wrapped repository.
+type InventoryRepository struct {
+	base
+	next dominv.Repository
+}
+
+func NewInventoryRepository(next dominv.Repository, tel observability.Observability) *InventoryRepository {
+	return &InventoryRepository{base: newBase("inventory", tel), next: next}
+}
+
+func (r *InventoryRepository) Reserve(ctx context.Context, operationID, productID string, quantity int) (int, error) {
+	start := time.Now()
+	remaining, err := r.next.Reserve(ctx, operationID, productID, quantity)
+	outcome := outcomeOK
+	switch {
+	case err == nil:
+	case errors.Is(err, dominv.ErrNotFound):
+		outcome = outcomeNotFound
+	case errors.Is(err, dominv.ErrAlreadyReserved):
+		outcome = outcomeConflict
+	default:
+		outcome = outcomeError
+	}
+	r.logOp(ctx, "reserve", productID, start, outcome, err)
+	return remaining, err
+}
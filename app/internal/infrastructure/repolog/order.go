@@ -0,0 +1,63 @@
+package repolog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+)
+
+var _ domorder.Repository = (*OrderRepository)(nil)
+
+// OrderRepository logs every call to the wrapped order repository.
+type OrderRepository struct {
+	base
+	next domorder.Repository
+}
+
+func NewOrderRepository(next domorder.Repository, tel observability.Observability) *OrderRepository {
+	return &OrderRepository{base: newBase("order", tel), next: next}
+}
+
+func (r *OrderRepository) Insert(ctx context.Context, order *domorder.Order) error {
+	start := time.Now()
+	err := r.next.Insert(ctx, order)
+	r.logOp(ctx, "insert", order.ID, start, orderOutcome(err), err)
+	return err
+}
+
+func (r *OrderRepository) Get(ctx context.Context, id string) (*domorder.Order, error) {
+	start := time.Now()
+	o, err := r.next.Get(ctx, id)
+	r.logOp(ctx, "get", id, start, orderOutcome(err), err)
+	return o, err
+}
+
+func (r *OrderRepository) Update(ctx context.Context, order *domorder.Order) error {
+	start := time.Now()
+	err := r.next.Update(ctx, order)
+	r.logOp(ctx, "update", order.ID, start, orderOutcome(err), err)
+	return err
+}
+
+func (r *OrderRepository) FindByIdempotency(ctx context.Context, customerID, key string) (*domorder.Order, error) {
+	start := time.Now()
+	o, err := r.next.FindByIdempotency(ctx, customerID, key)
+	r.logOp(ctx, "find_by_idempotency", customerID+"/"+key, start, orderOutcome(err), err)
+	return o, err
+}
+
+func orderOutcome(err error) string {
+	switch {
+	case err == nil:
+		return outcomeOK
+	case errors.Is(err, domorder.ErrNotFound):
+		return outcomeNotFound
+	case errors.Is(err, domorder.ErrConflict), errors.Is(err, domorder.ErrVersionConflict):
+		return outcomeConflict
+	default:
+		return outcomeError
+	}
+}
@@ -0,0 +1,48 @@
+// Package repolog decorates domain repositories with a debug-level repo_operation log
+// line per call, written through the request-scoped logger so the trail ties back to
+// the request or event that caused it. The wrapped implementations stay log-free.
+package repolog
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+)
+
+// Outcomes reported on repo_operation.
+const (
+	outcomeOK       = "ok"
+	outcomeNotFound = "not_found"
+	outcomeConflict = "conflict"
+	outcomeError    = "error"
+)
+
+type base struct {
+	repo string
+	log  observability.Logger
+}
+
+func newBase(repo string, tel observability.Observability) base {
+	return base{
+		repo: repo,
+		log:  observability.LoggerOf(tel).With(observability.F(observability.FieldComponent, observability.ComponentRepository)),
+	}
+}
+
+// logOp writes repo_operation for one call that started at start and ended with err,
+// classified into outcome by the caller.
+func (b base) logOp(ctx context.Context, op, key string, start time.Time, outcome string, err error) {
+	fields := []observability.Field{
+		observability.F("repository", b.repo),
+		observability.F("operation", op),
+		observability.F("key", key),
+		observability.F("outcome", outcome),
+		observability.F("latency_seconds", time.Since(start).Seconds()),
+	}
+	if err != nil {
+		fields = append(fields, observability.F("error", err.Error()))
+	}
+	logctx.FromOr(ctx, b.log).Debug("repo_operation", fields...)
+}
@@ -0,0 +1,11 @@
+package observability
+
+// Baggage members shared by the HTTP edge, which sets them, and the trace sampler,
+// which reads them.
+const (
+	// BaggageDebug set to BaggageDebugOn forces the trace to be sampled.
+	BaggageDebug   = "debug"
+	BaggageDebugOn = "1"
+	// BaggageTenant carries the tenant a debug trace was forced for.
+	BaggageTenant = "tenant_id"
+)
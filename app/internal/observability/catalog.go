@@ -0,0 +1,24 @@
+package observability
+
+// Instrument describes a registered metric for documentation and tooling.
+type Instrument struct {
+	Name   string   `json:"name"` // fully qualified, as exported
+	Key    string   `json:"key"`  // name the instrument was registered under
+	Help   string   `json:"help"`
+	Type   string   `json:"type"` // counter, histogram or gauge
+	Labels []string `json:"labels"`
+}
+
+// Cataloger is implemented by metrics backends and providers that can enumerate their
+// registered instruments.
+type Cataloger interface {
+	Catalog() []Instrument
+}
+
+// CatalogOf lists the instruments behind tel's metrics, or nil when the provider cannot.
+func CatalogOf(tel Observability) []Instrument {
+	if c, ok := MetricsOf(tel).(Cataloger); ok {
+		return c.Catalog()
+	}
+	return nil
+}
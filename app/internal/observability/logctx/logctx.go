@@ -2,18 +2,41 @@ package logctx
 
 import (
 	"context"
+	"sync"
 
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
 )
 
 type loggerKey struct{}
 
+// holder lets AddFields enrich the logger seen by every context derived from the one
+// passed to With, including callers further up the stack (e.g. an access log).
+type holder struct {
+	mu     sync.RWMutex
+	logger observability.Logger
+}
+
 // With stores the provided logger on the context for request-scoped logging.
 func With(ctx context.Context, logger observability.Logger) context.Context {
 	if ctx == nil || logger == nil {
 		return ctx
 	}
-	return context.WithValue(ctx, loggerKey{}, logger)
+	return context.WithValue(ctx, loggerKey{}, &holder{logger: logger})
+}
+
+// AddFields binds fields to the context logger in place, so code holding this context or
+// any context sharing the same With scope logs them too. No-op when ctx has no logger.
+func AddFields(ctx context.Context, fields ...observability.Field) {
+	if ctx == nil || len(fields) == 0 {
+		return
+	}
+	h, _ := ctx.Value(loggerKey{}).(*holder)
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.logger = h.logger.With(fields...)
 }
 
 // From retrieves a logger from the context if present.
@@ -21,14 +44,33 @@ func From(ctx context.Context) observability.Logger {
 	if ctx == nil {
 		return nil
 	}
-	logger, _ := ctx.Value(loggerKey{}).(observability.Logger)
-	return logger
+	h, _ := ctx.Value(loggerKey{}).(*holder)
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.logger
 }
 
-// FromOr returns the context logger when available, otherwise falls back to the supplied logger.
+// FromOr returns the context logger when available, otherwise falls back to the supplied logger,
+// and to a no-op logger if that is nil too, so callers never need their own nil check.
 func FromOr(ctx context.Context, fallback observability.Logger) observability.Logger {
 	if logger := From(ctx); logger != nil {
 		return logger
 	}
+	if fallback == nil {
+		return observability.NopLogger()
+	}
 	return fallback
 }
+
+// FromOrWith is FromOr for a logger owned by one service or component: a context logger
+// comes from the caller's layer, so fields (e.g. the canonical service) are bound to it;
+// fallback is returned as is because its owner bound them at construction.
+func FromOrWith(ctx context.Context, fallback observability.Logger, fields ...observability.Field) observability.Logger {
+	if logger := From(ctx); logger != nil {
+		return logger.With(fields...)
+	}
+	return FromOr(ctx, fallback)
+}
@@ -0,0 +1,29 @@
+package logctx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+)
+
+func TestFromOrPrefersTheContextLogger(t *testing.T) {
+	ctxRec, fallbackRec := obstest.New(), obstest.New()
+
+	logctx.FromOr(logctx.With(context.Background(), ctxRec.Logger()), fallbackRec.Logger()).Info("from_context")
+	logctx.FromOr(context.Background(), fallbackRec.Logger()).Info("from_fallback")
+
+	if len(ctxRec.Logs("from_context")) != 1 || len(fallbackRec.Logs("from_context")) != 0 {
+		t.Error("context logger was not preferred over the fallback")
+	}
+	if len(fallbackRec.Logs("from_fallback")) != 1 {
+		t.Error("bare context did not log through the fallback")
+	}
+}
+
+func TestFromOrNeverReturnsNil(t *testing.T) {
+	if logctx.FromOr(context.Background(), nil) == nil {
+		t.Fatal("logctx.FromOr(bare context, nil) = nil, want a no-op logger")
+	}
+}
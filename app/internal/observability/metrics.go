@@ -5,6 +5,34 @@ const (
 	MUsecaseDuration         MetricKey = "usecase_duration_seconds"
 	MHTTPRequests            MetricKey = "http_requests_total"
 	MHTTPRequestDuration     MetricKey = "http_request_duration_seconds"
+	MHTTPInFlight            MetricKey = "http_requests_in_flight"
+	MHTTPConcurrencyRejected MetricKey = "http_concurrency_rejected_total"
+	MHTTPPanics              MetricKey = "http_panics_total"
 	MExternalRequests        MetricKey = "external_requests_total"
 	MExternalRequestDuration MetricKey = "external_request_duration_seconds"
+	MOutboxHandlerDuration   MetricKey = "outbox_handler_duration_seconds"
+	MBusHandlerWait          MetricKey = "bus_handler_wait_seconds"
+	MOutboxFanoutWait        MetricKey = "outbox_fanout_semaphore_wait_seconds"
+	MOutboxFanoutAtCapacity  MetricKey = "outbox_fanout_at_capacity_total"
+	MOutboxEventsDropped     MetricKey = "outbox_events_dropped_total"
+	MOutboxEventsRejected    MetricKey = "outbox_events_rejected_total"
+	MPayments                MetricKey = "payments_total"
+	MInventoryLowStock       MetricKey = "inventory_low_stock_total"
+	MInventoryInvariant      MetricKey = "inventory_invariant_violation_total"
+	MLogWriteErrors          MetricKey = "log_write_errors_total"
+
+	MInventoryReservationsExpired MetricKey = "inventory_reservations_expired_total"
+	MInventoryProductsSeeded      MetricKey = "products_seeded"
+	MInventoryRemainingStock      MetricKey = "inventory_remaining_stock"
+	MOrderTransitions             MetricKey = "order_transitions_total"
+
+	MOutboxHandlersForceCancelled MetricKey = "outbox_handlers_force_cancelled_total"
+	MOutboxHandlersExhausted      MetricKey = "outbox_handlers_exhausted_total"
+	MOutboxRetryBudgetExhausted   MetricKey = "outbox_retry_budget_exhausted_total"
+	MOutboxShutdownDrained        MetricKey = "outbox_shutdown_drained_total"
+	MOutboxShutdownAbandoned      MetricKey = "outbox_shutdown_abandoned_total"
+	MOutboxQueueDepth             MetricKey = "outbox_queue_depth"
+	MOutboxHandlersInFlight       MetricKey = "outbox_handlers_inflight"
+
+	MWorkerSLAViolations MetricKey = "worker_sla_violations_total"
 )
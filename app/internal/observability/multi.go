@@ -0,0 +1,67 @@
+package observability
+
+import "errors"
+
+type multiLogger []Logger
+
+// MultiLogger fans every log call out to all supplied loggers (nil entries are skipped).
+func MultiLogger(loggers ...Logger) Logger {
+	out := make(multiLogger, 0, len(loggers))
+	for _, l := range loggers {
+		if l != nil {
+			out = append(out, l)
+		}
+	}
+	switch len(out) {
+	case 0:
+		return NopLogger()
+	case 1:
+		return out[0]
+	}
+	return out
+}
+
+func (m multiLogger) With(fields ...Field) Logger {
+	out := make(multiLogger, len(m))
+	for i, l := range m {
+		out[i] = l.With(fields...)
+	}
+	return out
+}
+
+func (m multiLogger) Debug(msg string, fields ...Field) {
+	for _, l := range m {
+		l.Debug(msg, fields...)
+	}
+}
+
+func (m multiLogger) Info(msg string, fields ...Field) {
+	for _, l := range m {
+		l.Info(msg, fields...)
+	}
+}
+
+func (m multiLogger) Warn(msg string, fields ...Field) {
+	for _, l := range m {
+		l.Warn(msg, fields...)
+	}
+}
+
+func (m multiLogger) Error(msg string, fields ...Field) {
+	for _, l := range m {
+		l.Error(msg, fields...)
+	}
+}
+
+// Sync flushes every underlying logger that supports it.
+func (m multiLogger) Sync() error {
+	var errs []error
+	for _, l := range m {
+		if s, ok := l.(interface{ Sync() error }); ok {
+			if err := s.Sync(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -7,6 +7,16 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+type nopObservability struct{}
+
+func (nopObservability) Tracer() Tracer   { return nopTracer{} }
+func (nopObservability) Logger() Logger   { return nopLogger{} }
+func (nopObservability) Metrics() Metrics { return nopMetrics{} }
+
+// NopObservability returns a provider whose tracer, logger and metrics are all no-ops.
+// Use it to guarantee a non-nil provider instead of nil-checking at every call site.
+func NopObservability() Observability { return nopObservability{} }
+
 type nopLogger struct{}
 
 func (nopLogger) With(_ ...Field) Logger { return nopLogger{} }
@@ -31,6 +41,7 @@ type nopMetrics struct{}
 
 func (nopMetrics) Counter(MetricKey) Counter     { return nopCounter{} }
 func (nopMetrics) Histogram(MetricKey) Histogram { return nopHistogram{} }
+func (nopMetrics) Gauge(MetricKey) Gauge         { return nopGauge{} }
 
 // NopMetrics returns a metrics provider whose instruments drop all observations.
 func NopMetrics() Metrics { return nopMetrics{} }
@@ -44,7 +55,7 @@ func NopCounter() Counter { return nopCounter{} }
 
 type nopBoundCounter struct{}
 
-func (nopBoundCounter) Add(_ float64) {}
+func (nopBoundCounter) Add(_ float64, _ ...Label) {}
 
 type nopHistogram struct{}
 
@@ -55,4 +66,41 @@ func NopHistogram() Histogram { return nopHistogram{} }
 
 type nopBoundHistogram struct{}
 
-func (nopBoundHistogram) Observe(_ float64) {}
+func (nopBoundHistogram) Observe(_ float64, _ ...Label) {}
+
+type nopGauge struct{}
+
+func (nopGauge) Set(_ float64, _ ...Label) {}
+func (nopGauge) Add(_ float64, _ ...Label) {}
+
+func NopGauge() Gauge { return nopGauge{} }
+
+// LoggerOf returns tel's logger, falling back to NopLogger when tel or its logger is nil.
+func LoggerOf(tel Observability) Logger {
+	if tel != nil {
+		if l := tel.Logger(); l != nil {
+			return l
+		}
+	}
+	return NopLogger()
+}
+
+// TracerOf returns tel's tracer, falling back to NopTracer when tel or its tracer is nil.
+func TracerOf(tel Observability) Tracer {
+	if tel != nil {
+		if t := tel.Tracer(); t != nil {
+			return t
+		}
+	}
+	return NopTracer()
+}
+
+// MetricsOf returns tel's metrics provider, falling back to NopMetrics when tel or its provider is nil.
+func MetricsOf(tel Observability) Metrics {
+	if tel != nil {
+		if m := tel.Metrics(); m != nil {
+			return m
+		}
+	}
+	return NopMetrics()
+}
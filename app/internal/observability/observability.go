@@ -16,6 +16,7 @@ type Observability interface {
 type Metrics interface {
 	Counter(name MetricKey) Counter
 	Histogram(name MetricKey) Histogram
+	Gauge(name MetricKey) Gauge
 }
 
 // Tracer is a thin wrapper to start spans.
@@ -29,8 +30,10 @@ type Counter interface {
 	Bind(labels ...Label) BoundCounter
 }
 
+// BoundCounter is a Counter with some labels fixed up front; the remaining
+// labels are supplied per call.
 type BoundCounter interface {
-	Add(delta float64)
+	Add(delta float64, remaining ...Label)
 }
 
 type Histogram interface {
@@ -38,8 +41,16 @@ type Histogram interface {
 	Bind(labels ...Label) BoundHistogram
 }
 
+// BoundHistogram is a Histogram with some labels fixed up front; the remaining
+// labels are supplied per call.
 type BoundHistogram interface {
-	Observe(value float64)
+	Observe(value float64, remaining ...Label)
+}
+
+// Gauge is a thin wrapper for values that go up and down (in-flight work, queue depth).
+type Gauge interface {
+	Set(value float64, labels ...Label)
+	Add(delta float64, labels ...Label)
 }
 
 type Label struct{ Key, Value string }
@@ -0,0 +1,43 @@
+package observability
+
+import "strings"
+
+// Canonical log field keys and values identifying where a log line came from.
+// Values are snake_case, like metric labels, so filters behave the same everywhere.
+const (
+	FieldService   = "service"
+	FieldComponent = "component"
+
+	// Services: application-layer use cases and the workers driving them.
+	ServiceOrder           = "order_service"
+	ServiceOrderWorker     = "order_worker"
+	ServicePayment         = "payment_service"
+	ServicePaymentWorker   = "payment_worker"
+	ServiceInventory       = "inventory_service"
+	ServiceInventoryWorker = "inventory_worker"
+
+	// Components: infrastructure and presentation building blocks.
+	ComponentHTTPServer  = "http_server"
+	ComponentOutbox      = "outbox"
+	ComponentOutboxRelay = "outbox_relay"
+	ComponentRepository  = "repository"
+	ComponentSystem      = "system"
+)
+
+// StageOf maps a service to its saga stage ("order", "inventory", "payment") by dropping
+// the _service or _worker suffix, so use cases and the workers driving them aggregate
+// together.
+func StageOf(service string) string {
+	if stage, ok := strings.CutSuffix(service, "_service"); ok {
+		return stage
+	}
+	stage, _ := strings.CutSuffix(service, "_worker")
+	return stage
+}
+
+// UseCaseLabels are the fixed labels of usecase_requests_total and
+// usecase_duration_seconds: the service's stage plus the fine-grained use_case. Bind
+// them once at construction so calls only add outcome.
+func UseCaseLabels(service, useCase string) []Label {
+	return []Label{L("stage", StageOf(service)), L("use_case", useCase)}
+}
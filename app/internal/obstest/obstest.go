@@ -0,0 +1,263 @@
+// Package obstest provides an in-memory Observability whose metrics, ended spans and log
+// lines can be inspected, so tests can assert the instrumentation contract (e.g. with
+// testkit.WithTelemetry) without scraping Prometheus or exporting traces.
+package obstest
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ observability.Observability = (*Recorder)(nil)
+
+// Recorder records every counter add, histogram observation and gauge update by metric
+// key and label set. It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	series map[observability.MetricKey]map[string]*series
+	spans  *tracetest.SpanRecorder
+	tracer trace.Tracer
+	logs   []Entry
+}
+
+// Entry is one recorded log line, with the fields bound through With merged in.
+type Entry struct {
+	Level  string
+	Msg    string
+	Fields map[string]any
+}
+
+type series struct {
+	labels []observability.Label
+	sum    float64 // counter total, histogram sum or gauge value
+	count  int     // number of recorded calls
+}
+
+func New() *Recorder {
+	spans := tracetest.NewSpanRecorder()
+	return &Recorder{
+		series: make(map[observability.MetricKey]map[string]*series),
+		spans:  spans,
+		tracer: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)).Tracer("obstest"),
+	}
+}
+
+func (r *Recorder) Tracer() observability.Tracer   { return recorderTracer{r.tracer} }
+func (r *Recorder) Logger() observability.Logger   { return recorderLogger{r: r} }
+func (r *Recorder) Metrics() observability.Metrics { return recorderMetrics{r} }
+
+// Count returns the total added to counter key across every series carrying labels.
+func (r *Recorder) Count(key observability.MetricKey, labels ...observability.Label) float64 {
+	var total float64
+	r.each(key, labels, func(s *series) { total += s.sum })
+	return total
+}
+
+// Observations returns how many values histogram key observed across every series
+// carrying labels.
+func (r *Recorder) Observations(key observability.MetricKey, labels ...observability.Label) int {
+	var n int
+	r.each(key, labels, func(s *series) { n += s.count })
+	return n
+}
+
+// GaugeValue returns the current value of gauge key for the series with exactly labels.
+func (r *Recorder) GaugeValue(key observability.MetricKey, labels ...observability.Label) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.series[key][seriesID(labels)]; ok {
+		return s.sum
+	}
+	return 0
+}
+
+// Series lists the label sets recorded for key, for failure messages.
+func (r *Recorder) Series(key observability.MetricKey) [][]observability.Label {
+	var out [][]observability.Label
+	r.each(key, nil, func(s *series) { out = append(out, s.labels) })
+	return out
+}
+
+// Spans returns the ended spans named name, in the order they ended.
+func (r *Recorder) Spans(name string) []sdktrace.ReadOnlySpan {
+	var out []sdktrace.ReadOnlySpan
+	for _, s := range r.spans.Ended() {
+		if s.Name() == name {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Logs returns the recorded log lines with message msg, in the order they were written.
+func (r *Recorder) Logs(msg string) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Entry
+	for _, e := range r.logs {
+		if e.Msg == msg {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Reset forgets everything recorded so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.series = make(map[observability.MetricKey]map[string]*series)
+	r.logs = nil
+	r.spans.Reset()
+}
+
+// each calls fn for every series of key whose labels include want.
+func (r *Recorder) each(key observability.MetricKey, want []observability.Label, fn func(*series)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.series[key] {
+		if hasLabels(s.labels, want) {
+			fn(s)
+		}
+	}
+}
+
+func (r *Recorder) record(key observability.MetricKey, labels []observability.Label, update func(*series)) {
+	labels = normalize(labels)
+	id := seriesID(labels)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byID, ok := r.series[key]
+	if !ok {
+		byID = make(map[string]*series)
+		r.series[key] = byID
+	}
+	s, ok := byID[id]
+	if !ok {
+		s = &series{labels: labels}
+		byID[id] = s
+	}
+	update(s)
+	s.count++
+}
+
+func hasLabels(have, want []observability.Label) bool {
+	for _, w := range want {
+		if !slices.Contains(have, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize sorts labels by key so call-site order does not split series.
+func normalize(labels []observability.Label) []observability.Label {
+	out := slices.Clone(labels)
+	slices.SortStableFunc(out, func(a, b observability.Label) int { return strings.Compare(a.Key, b.Key) })
+	return out
+}
+
+func seriesID(labels []observability.Label) string {
+	var b strings.Builder
+	for _, l := range normalize(labels) {
+		b.WriteString(l.Key)
+		b.WriteByte('=')
+		b.WriteString(l.Value)
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+type recorderLogger struct {
+	r      *Recorder
+	fields []observability.Field
+}
+
+func (l recorderLogger) With(fields ...observability.Field) observability.Logger {
+	return recorderLogger{r: l.r, fields: append(slices.Clone(l.fields), fields...)}
+}
+
+func (l recorderLogger) Debug(msg string, fields ...observability.Field) { l.log("debug", msg, fields) }
+func (l recorderLogger) Info(msg string, fields ...observability.Field)  { l.log("info", msg, fields) }
+func (l recorderLogger) Warn(msg string, fields ...observability.Field)  { l.log("warn", msg, fields) }
+func (l recorderLogger) Error(msg string, fields ...observability.Field) { l.log("error", msg, fields) }
+
+func (l recorderLogger) log(level, msg string, fields []observability.Field) {
+	e := Entry{Level: level, Msg: msg, Fields: make(map[string]any, len(l.fields)+len(fields))}
+	for _, f := range append(slices.Clone(l.fields), fields...) {
+		e.Fields[f.Key] = f.Value
+	}
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	l.r.logs = append(l.r.logs, e)
+}
+
+type recorderTracer struct{ t trace.Tracer }
+
+func (t recorderTracer) Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return t.t.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+type recorderMetrics struct{ r *Recorder }
+
+func (m recorderMetrics) Counter(key observability.MetricKey) observability.Counter {
+	return &counter{r: m.r, key: key}
+}
+
+func (m recorderMetrics) Histogram(key observability.MetricKey) observability.Histogram {
+	return &histogram{r: m.r, key: key}
+}
+
+func (m recorderMetrics) Gauge(key observability.MetricKey) observability.Gauge {
+	return &gauge{r: m.r, key: key}
+}
+
+type counter struct {
+	r     *Recorder
+	key   observability.MetricKey
+	bound []observability.Label
+}
+
+func (c *counter) Add(d float64, labels ...observability.Label) {
+	c.r.record(c.key, append(slices.Clone(c.bound), labels...), func(s *series) { s.sum += d })
+}
+
+func (c *counter) Bind(labels ...observability.Label) observability.BoundCounter {
+	return &counter{r: c.r, key: c.key, bound: append(slices.Clone(c.bound), labels...)}
+}
+
+type histogram struct {
+	r     *Recorder
+	key   observability.MetricKey
+	bound []observability.Label
+}
+
+func (h *histogram) Observe(v float64, labels ...observability.Label) {
+	h.r.record(h.key, append(slices.Clone(h.bound), labels...), func(s *series) { s.sum += v })
+}
+
+func (h *histogram) Bind(labels ...observability.Label) observability.BoundHistogram {
+	return &histogram{r: h.r, key: h.key, bound: append(slices.Clone(h.bound), labels...)}
+}
+
+type gauge struct {
+	r   *Recorder
+	key observability.MetricKey
+}
+
+func (g *gauge) Set(v float64, labels ...observability.Label) {
+	g.r.record(g.key, labels, func(s *series) { s.sum = v })
+}
+
+func (g *gauge) Add(d float64, labels ...observability.Label) {
+	g.r.record(g.key, labels, func(s *series) { s.sum += d })
+}
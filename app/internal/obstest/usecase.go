@@ -0,0 +1,50 @@
+package obstest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+)
+
+const outcomeSuccess = "success"
+
+// ExecuteUseCase runs uc once and fails t unless the run satisfies the outcome
+// invariant every instrumented use case must keep, on every branch:
+//
+//   - exactly one usecase_requests_total increment is recorded for useCase, so no branch
+//     forgets its outcome or counts twice;
+//   - exactly one usecase_duration_seconds observation is recorded for useCase;
+//   - a returned error is never labeled outcome="success".
+//
+// uc must have been built with rec as its Observability. Increments for other use
+// cases (e.g. handlers run by a synchronous bus) are ignored.
+func ExecuteUseCase[C, R any](
+	t testing.TB,
+	ctx context.Context,
+	rec *Recorder,
+	uc application.UseCase[C, R],
+	useCase string,
+	cmd C,
+) (R, error) {
+	t.Helper()
+	label := observability.L("use_case", useCase)
+	success := observability.L("outcome", outcomeSuccess)
+	beforeAll := rec.Count(observability.MUsecaseRequests, label)
+	beforeSuccess := rec.Count(observability.MUsecaseRequests, label, success)
+	beforeObs := rec.Observations(observability.MUsecaseDuration, label)
+
+	res, err := uc.Execute(ctx, cmd)
+
+	if n := rec.Count(observability.MUsecaseRequests, label) - beforeAll; n != 1 {
+		t.Errorf("%s recorded %v outcomes, want exactly 1 (series: %v)", useCase, n, rec.Series(observability.MUsecaseRequests))
+	}
+	if n := rec.Observations(observability.MUsecaseDuration, label) - beforeObs; n != 1 {
+		t.Errorf("%s recorded %d durations, want exactly 1", useCase, n)
+	}
+	if err != nil && rec.Count(observability.MUsecaseRequests, label, success) > beforeSuccess {
+		t.Errorf("%s returned error %v but recorded outcome %q", useCase, err, outcomeSuccess)
+	}
+	return res, err
+}
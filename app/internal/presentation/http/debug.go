@@ -0,0 +1,56 @@
+package httppresentation
+
+import (
+	"net/http"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+)
+
+// RecentEventsHandler serves GET /debug/events/recent from recent, which returns the
+// records retained by the event bus. Only mount it when debugging is enabled.
+func RecentEventsHandler[T any](recent func() []T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		events := recent()
+		if events == nil {
+			events = []T{}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"events": events})
+	})
+}
+
+// MetricsCatalogHandler serves GET /debug/metrics/catalog: every registered metric with
+// its help text, type and label keys, for generating documentation.
+func MetricsCatalogHandler(catalog func() []observability.Instrument) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		instruments := catalog()
+		if instruments == nil {
+			instruments = []observability.Instrument{}
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"metrics": instruments})
+	})
+}
+
+// SnapshotHandler serves GET /debug/snapshot: one JSON object with a key per source,
+// each evaluated on request. It is a lightweight operator view for dev and triage when
+// no Prometheus is at hand; only mount it when debugging is enabled.
+func SnapshotHandler(sources map[string]func() any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		snapshot := make(map[string]any, len(sources))
+		for name, source := range sources {
+			snapshot[name] = source()
+		}
+		writeJSON(w, http.StatusOK, snapshot)
+	})
+}
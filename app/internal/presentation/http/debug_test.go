@@ -0,0 +1,90 @@
+package httppresentation_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/outbox"
+	httppresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/http"
+)
+
+type pingEvent struct{}
+
+func (pingEvent) EventName() string { return "test.ping" }
+
+func TestSnapshotReportsRecentHandlerErrors(t *testing.T) {
+	bus := outbox.NewBus(nil, nil, outbox.WithRecentEvents(10))
+	bus.SubscribeNamed("flaky", "test.ping", func(context.Context, domoutbox.Event) error {
+		return errors.New("downstream unavailable")
+	})
+	bus.SubscribeNamed("steady", "test.ping", func(context.Context, domoutbox.Event) error { return nil })
+	bus.Start(context.Background())
+	t.Cleanup(func() { bus.Stop(context.Background()) })
+	for range 2 {
+		_ = bus.Publish(context.Background(), pingEvent{})
+	}
+	if err := bus.WaitIdle(context.Background()); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+
+	srv := httptest.NewServer(httppresentation.SnapshotHandler(map[string]func() any{
+		"bus":           func() any { return bus.Stats() },
+		"recent_errors": func() any { return map[string]any{"handlers": bus.Stats().HandlerErrors} },
+	}))
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var snapshot struct {
+		Bus struct {
+			QueueDepth    int            `json:"queue_depth"`
+			Subscriptions map[string]int `json:"subscriptions"`
+		} `json:"bus"`
+		RecentErrors struct {
+			Handlers map[string]int `json:"handlers"`
+		} `json:"recent_errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		t.Fatalf("decode snapshot: %v", err)
+	}
+	if got := snapshot.Bus.Subscriptions["test.ping"]; got != 2 {
+		t.Errorf("bus.subscriptions[test.ping] = %d, want 2", got)
+	}
+	if got := snapshot.RecentErrors.Handlers["flaky"]; got != 2 {
+		t.Errorf("recent_errors.handlers[flaky] = %d, want 2 (got %v)", got, snapshot.RecentErrors.Handlers)
+	}
+	if got, ok := snapshot.RecentErrors.Handlers["steady"]; ok {
+		t.Errorf("recent_errors.handlers[steady] = %d, want no entry for a handler that never failed", got)
+	}
+}
+
+func TestSnapshotRejectsNonGet(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(httppresentation.SnapshotHandler(map[string]func() any{
+		"bus": func() any { called = true; return nil },
+	}))
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Post(srv.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405", resp.StatusCode)
+	}
+	if called {
+		t.Error("snapshot sources were evaluated for a rejected method")
+	}
+}
@@ -4,21 +4,26 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
+	appInventory "github.com/Zhima-Mochi/minishop-observability/app/internal/application/inventory"
 	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
 	appPayment "github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
 	domainInventory "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
 	domainOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	domainOutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
 	domainPayment "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/payment"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/health"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -26,16 +31,29 @@ import (
 type Handler struct {
 	orderUseCase   application.UseCase[appOrder.CreateOrderInput, *appOrder.CreateOrderResult]
 	paymentUseCase application.UseCase[appPayment.ProcessPaymentInput, *appPayment.ProcessPaymentResult]
+	sagaUseCase    application.UseCase[appOrder.SagaViewInput, *appOrder.SagaView]                         // optional
+	seedUseCase    application.UseCase[appInventory.SeedInventoryInput, *appInventory.SeedInventoryResult] // optional
 	log            observability.Logger
 	tel            observability.Observability
 	httpCounter    observability.Counter
 	httpHistogram  observability.Histogram
+
+	debugTenants map[string]struct{} // tenants whose requests are always traced; see WithDebugTenants
+	health       *health.State       // optional, see WithReadiness
+	maxBodyBytes int64               // request body limit for decodeJSON; see WithMaxBodyBytes
+
+	inFlight        chan struct{}         // nil unless WithMaxInFlight
+	inFlightGauge   observability.Gauge   // http_requests_in_flight
+	rejectedCounter observability.Counter // http_concurrency_rejected_total{route}
+	panicCounter    observability.Counter // http_panics_total{route}
 }
 
 const (
-	componentHTTPHandler = "http_server"
-	headerRequestID      = "X-Request-ID"
-	headerTenantID       = "X-Tenant-ID"
+	headerRequestID = "X-Request-ID"
+	headerTenantID  = "X-Tenant-ID"
+
+	// DefaultMaxBodyBytes is the request body limit used unless WithMaxBodyBytes is set.
+	DefaultMaxBodyBytes = 1 << 20
 )
 
 func NewHandler(
@@ -43,22 +61,73 @@ func NewHandler(
 	paymentUC application.UseCase[appPayment.ProcessPaymentInput, *appPayment.ProcessPaymentResult],
 	logger observability.Logger,
 	tel observability.Observability,
+	opts ...HandlerOption,
 ) *Handler {
+	if tel == nil {
+		tel = observability.NopObservability()
+	}
 	baseLogger := logger
 	if baseLogger == nil {
-		baseLogger = observability.NopLogger()
-	}
-	metricsProvider := observability.NopMetrics()
-	if tel != nil {
-		metricsProvider = tel.Metrics()
+		baseLogger = observability.LoggerOf(tel)
 	}
-	return &Handler{
+	metricsProvider := observability.MetricsOf(tel)
+	h := &Handler{
 		orderUseCase:   orderUC,
 		paymentUseCase: paymentUC,
-		log:            baseLogger.With(observability.F("component", componentHTTPHandler)),
+		log:            baseLogger.With(observability.F(observability.FieldComponent, observability.ComponentHTTPServer)),
 		tel:            tel,
+		maxBodyBytes:   DefaultMaxBodyBytes,
 		httpCounter:    metricsProvider.Counter(observability.MHTTPRequests),
 		httpHistogram:  metricsProvider.Histogram(observability.MHTTPRequestDuration),
+
+		inFlightGauge:   metricsProvider.Gauge(observability.MHTTPInFlight),
+		rejectedCounter: metricsProvider.Counter(observability.MHTTPConcurrencyRejected),
+		panicCounter:    metricsProvider.Counter(observability.MHTTPPanics),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// HandlerOption enables optional routes.
+type HandlerOption func(*Handler)
+
+// WithSagaView serves GET /order/{id}/saga from uc.
+func WithSagaView(uc application.UseCase[appOrder.SagaViewInput, *appOrder.SagaView]) HandlerOption {
+	return func(h *Handler) {
+		h.sagaUseCase = uc
+	}
+}
+
+// WithInventorySeeding serves POST /inventory:bulk from uc.
+func WithInventorySeeding(uc application.UseCase[appInventory.SeedInventoryInput, *appInventory.SeedInventoryResult]) HandlerOption {
+	return func(h *Handler) {
+		h.seedUseCase = uc
+	}
+}
+
+// WithMaxBodyBytes caps JSON request bodies at n bytes (default DefaultMaxBodyBytes);
+// larger ones are answered with 413. n <= 0 keeps the default.
+func WithMaxBodyBytes(n int64) HandlerOption {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxBodyBytes = n
+		}
+	}
+}
+
+// WithDebugTenants marks requests whose X-Tenant-ID is listed with debug baggage, which
+// the trace sampler honours by sampling the whole trace regardless of the global ratio.
+func WithDebugTenants(tenants ...string) HandlerOption {
+	return func(h *Handler) {
+		if len(tenants) == 0 {
+			return
+		}
+		h.debugTenants = make(map[string]struct{}, len(tenants))
+		for _, t := range tenants {
+			h.debugTenants[t] = struct{}{}
+		}
 	}
 }
 
@@ -66,15 +135,43 @@ func (h *Handler) Router() http.Handler {
 	mux := http.NewServeMux()
 
 	// Wire each route with middlewares:
-	// Trace → ObservabilityMiddleware (request logger) → HTTP metrics → Access log → Handler
-	h.muxHandle(mux, http.MethodPost, "/order", h.handleCreateOrder)
-	h.muxHandle(mux, http.MethodPost, "/payment/pay", h.handleProcessPayment)
+	// Trace → ObservabilityMiddleware (request logger) → Access log → HTTP metrics → Recover → [Concurrency limit] → Handler
+	// Trace must come first: the request logger reads trace_id/span_id from its server span.
+	h.muxHandle(mux, http.MethodPost, "/order", h.withConcurrencyLimit("/order", h.handleCreateOrder))
+	h.muxHandle(mux, http.MethodPost, "/payment/pay", h.withConcurrencyLimit("/payment/pay", h.handleProcessPayment))
 	h.muxHandle(mux, http.MethodGet, "/health", h.handleHealth)
+	if h.health != nil {
+		h.muxHandle(mux, http.MethodGet, "/ready", h.handleReady)
+	}
+	if h.sagaUseCase != nil {
+		h.muxHandle(mux, http.MethodGet, "/order/{id}/saga", h.withConcurrencyLimit("/order/{id}/saga", h.handleOrderSaga))
+	}
+	if h.seedUseCase != nil {
+		h.muxHandle(mux, http.MethodPost, "/inventory:bulk", h.withConcurrencyLimit("/inventory:bulk", h.handleSeedInventory))
+	}
 
 	return mux
 }
 
 func (h *Handler) muxHandle(mux *http.ServeMux, method, route string, handler http.HandlerFunc) {
+	// Wrap once per route: Trace → Request Logger → Access Log → Metrics → Recover → Handler
+	wrapped := h.withTrace(
+		ObservabilityMiddleware(
+			h.log,
+			func(r *http.Request) string {
+				return r.Header.Get(headerRequestID)
+			},
+			func(r *http.Request) string {
+				return r.Header.Get(headerTenantID)
+			},
+			h.tel,
+		)(
+			h.withAccessLog(
+				h.withHTTPMetrics(method, route, h.withRecover(route, handler)),
+			),
+		),
+	)
+
 	mux.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != method {
 			w.WriteHeader(http.StatusMethodNotAllowed)
@@ -82,27 +179,7 @@ func (h *Handler) muxHandle(mux *http.ServeMux, method, route string, handler ht
 		}
 
 		// Store stable route template for low-cardinality labels
-		ctx := contextWithRoute(r.Context(), route)
-		r = r.WithContext(ctx)
-
-		// Wrap: Trace → Request Logger → Metrics → Access Log → Handler
-		wrapped := h.withTrace(
-			ObservabilityMiddleware(
-				logctx.FromOr(ctx, h.log),
-				func(r *http.Request) string {
-					return r.Header.Get(headerRequestID)
-				},
-				func(r *http.Request) string {
-					return r.Header.Get(headerTenantID)
-				},
-				h.tel,
-			)(
-				h.withAccessLog(
-					h.withHTTPMetrics(http.HandlerFunc(handler)),
-				),
-			),
-		)
-		wrapped.ServeHTTP(w, r)
+		wrapped.ServeHTTP(w, r.WithContext(contextWithRoute(r.Context(), route)))
 	})
 }
 
@@ -121,8 +198,8 @@ type createOrderResponse struct {
 
 func (h *Handler) handleCreateOrder(w http.ResponseWriter, r *http.Request) {
 	var req createOrderRequest
-	if err := decodeJSON(r.Context(), r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, err)
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
@@ -137,6 +214,7 @@ func (h *Handler) handleCreateOrder(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, err)
 		return
 	}
+	logctx.AddFields(r.Context(), observability.F("order_id", result.OrderID))
 
 	writeJSON(w, http.StatusCreated, createOrderResponse{
 		OrderID: result.OrderID,
@@ -156,8 +234,8 @@ type processPaymentResponse struct {
 
 func (h *Handler) handleProcessPayment(w http.ResponseWriter, r *http.Request) {
 	var req processPaymentRequest
-	if err := decodeJSON(r.Context(), r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, err)
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
@@ -169,6 +247,10 @@ func (h *Handler) handleProcessPayment(w http.ResponseWriter, r *http.Request) {
 		writeDomainError(w, err)
 		return
 	}
+	logctx.AddFields(r.Context(),
+		observability.F("order_id", req.OrderID),
+		observability.F("payment_status", res.Status),
+	)
 
 	writeJSON(w, http.StatusOK, processPaymentResponse{
 		OrderID: req.OrderID,
@@ -176,13 +258,60 @@ func (h *Handler) handleProcessPayment(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) handleOrderSaga(w http.ResponseWriter, r *http.Request) {
+	view, err := h.sagaUseCase.Execute(r.Context(), appOrder.SagaViewInput{OrderID: r.PathValue("id")})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	logctx.AddFields(r.Context(), observability.F("order_id", view.Order.ID))
+
+	writeJSON(w, http.StatusOK, view)
+}
+
+type seedInventoryItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+type seedInventoryResponse struct {
+	Seeded   int `json:"seeded"`
+	Products int `json:"products"`
+}
+
+func (h *Handler) handleSeedInventory(w http.ResponseWriter, r *http.Request) {
+	var req []seedInventoryItem
+	if err := h.decodeJSON(w, r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if len(req) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("expected at least one item"))
+		return
+	}
+
+	stock := make([]domainInventory.Stock, 0, len(req))
+	for _, item := range req {
+		stock = append(stock, domainInventory.Stock{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	res, err := h.seedUseCase.Execute(r.Context(), appInventory.SeedInventoryInput{Stock: stock})
+	if err != nil {
+		writeDomainError(w, err)
+		return
+	}
+	logctx.AddFields(r.Context(), observability.F("seeded", res.Seeded))
+
+	writeJSON(w, http.StatusOK, seedInventoryResponse{Seeded: res.Seeded, Products: res.Products})
+}
+
 func (h *Handler) handleHealth(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("ok"))
 }
 
 // withAccessLog writes a single access log after the handler completes.
-// It relies on the request-scoped logger already injected by ObservabilityMiddleware.
+// It relies on the request-scoped logger already injected by ObservabilityMiddleware,
+// so business identifiers handlers add via logctx.AddFields appear on the same line.
 func (h *Handler) withAccessLog(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -201,10 +330,13 @@ func (h *Handler) withAccessLog(next http.Handler) http.Handler {
 }
 
 // withTrace creates a server span for the request using OTel and W3C propagation.
+// The span is started under the extracted traceparent, so parent-based samplers keep
+// the caller's sampled decision.
 func (h *Handler) withTrace(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		tracer := otel.Tracer("minishop.http")
 		parentCtx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		parentCtx = h.debugBaggage(parentCtx, r.Header.Get(headerTenantID))
 
 		route := routeFromContext(parentCtx)
 		spanName := route
@@ -235,33 +367,56 @@ func (h *Handler) withTrace(next http.Handler) http.Handler {
 	})
 }
 
+// debugBaggage adds debug baggage for allow-listed tenants before the server span
+// starts, so the sampler sees it for the root span and every child.
+func (h *Handler) debugBaggage(ctx context.Context, tenant string) context.Context {
+	if tenant == "" {
+		return ctx
+	}
+	if _, ok := h.debugTenants[tenant]; !ok {
+		return ctx
+	}
+	bag := baggage.FromContext(ctx)
+	for key, value := range map[string]string{
+		observability.BaggageDebug:  observability.BaggageDebugOn,
+		observability.BaggageTenant: tenant,
+	} {
+		m, err := baggage.NewMemberRaw(key, value)
+		if err != nil {
+			continue
+		}
+		if next, err := bag.SetMember(m); err == nil {
+			bag = next
+		}
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
 // withHTTPMetrics records RED-ish HTTP metrics using injected vectors.
-// DO NOT new metrics inside the middleware.
-func (h *Handler) withHTTPMetrics(next http.Handler) http.Handler {
+// DO NOT new metrics inside the middleware; method and route are bound once per route.
+func (h *Handler) withHTTPMetrics(method, route string, next http.Handler) http.Handler {
+	routeLabels := []observability.Label{
+		observability.L("method", method),
+		observability.L("route", route),
+	}
+	reqCounter := h.httpCounter.Bind(routeLabels...)
+	reqHistogram := h.httpHistogram.Bind(routeLabels...)
+
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		lrw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
 
 		next.ServeHTTP(lrw, r)
 
-		route := routeFromContext(r.Context())
-		statusLabel := strconv.Itoa(lrw.status)
-		h.httpCounter.Add(1,
-			observability.L("method", r.Method),
-			observability.L("route", route),
-			observability.L("status", statusLabel),
-		)
-		h.httpHistogram.Observe(time.Since(start).Seconds(),
-			observability.L("method", r.Method),
-			observability.L("route", route),
-			observability.L("status", statusLabel),
-		)
+		statusLabel := observability.L("status", strconv.Itoa(lrw.status))
+		reqCounter.Add(1, statusLabel)
+		reqHistogram.Observe(time.Since(start).Seconds(), statusLabel)
 	})
 }
 
-func decodeJSON(ctx context.Context, r *http.Request, dst any) error {
-	_ = ctx
-	decoder := json.NewDecoder(r.Body)
+// decodeJSON reads at most h.maxBodyBytes of the request body into dst.
+func (h *Handler) decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
 	decoder.DisallowUnknownFields()
 	if err := decoder.Decode(dst); err != nil {
 		return err
@@ -269,6 +424,17 @@ func decodeJSON(ctx context.Context, r *http.Request, dst any) error {
 	return nil
 }
 
+// writeDecodeError answers a body over the limit with 413 and any other decode error
+// with 400.
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit))
+		return
+	}
+	writeError(w, http.StatusBadRequest, err)
+}
+
 func writeJSON(w http.ResponseWriter, status int, body any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -284,11 +450,27 @@ func writeDomainError(w http.ResponseWriter, err error) {
 	case errors.Is(err, domainOrder.ErrNotFound),
 		errors.Is(err, domainInventory.ErrNotFound):
 		writeError(w, http.StatusNotFound, err)
+	case appOrder.IsValidation(err):
+		writeJSON(w, http.StatusBadRequest, map[string]string{
+			"error": err.Error(),
+			"code":  appOrder.ValidationCode(err),
+		})
 	case errors.Is(err, domainInventory.ErrInvalidQuantity),
 		errors.Is(err, domainInventory.ErrInsufficientStock),
+		errors.Is(err, domainInventory.ErrInvalidStock),
 		errors.Is(err, domainOrder.ErrInvalidAmount),
-		errors.Is(err, domainOrder.ErrInvalidQuantity):
+		errors.Is(err, domainOrder.ErrInvalidQuantity),
+		errors.Is(err, appPayment.ErrZeroAmount),
+		errors.Is(err, appPayment.ErrAmountMismatch):
 		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, domainOrder.ErrVersionConflict):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, domainOutbox.ErrQueueFull):
+		// Backpressure: ask clients to back off instead of retrying straight away.
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+		writeError(w, http.StatusServiceUnavailable, err)
+	case errors.Is(err, appOrder.ErrEventPublish):
+		writeError(w, http.StatusServiceUnavailable, err)
 	default:
 		writeError(w, http.StatusInternalServerError, err)
 	}
@@ -0,0 +1,157 @@
+package httppresentation_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	appPayment "github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/id"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	httppresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/http"
+)
+
+const orderBody = `{"customer_id":"c-1","product_id":"sku-1","quantity":1,"amount":100}`
+
+// paddedOrder is orderBody padded with leading whitespace to exactly size bytes, so the
+// decoder has to read all of it.
+func paddedOrder(t *testing.T, size int) string {
+	t.Helper()
+	if size < len(orderBody) {
+		t.Fatalf("size %d is below the %d-byte body", size, len(orderBody))
+	}
+	return strings.Repeat(" ", size-len(orderBody)) + orderBody
+}
+
+func TestRequestBodyLimit(t *testing.T) {
+	const limit = 256
+	orders := memory.NewOrderRepository()
+	h := httppresentation.NewHandler(
+		appOrder.NewCreateOrderUseCase(orders, id.NewUUIDGenerator(), nil, nil),
+		appPayment.NewProcessPaymentUseCase(orders, nil),
+		nil, nil,
+		httppresentation.WithMaxBodyBytes(limit),
+	)
+	srv := httptest.NewServer(h.Router())
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name  string
+		route string
+		size  int
+		want  int
+	}{
+		{"at the limit", "/order", limit, http.StatusCreated},
+		{"one byte over", "/order", limit + 1, http.StatusRequestEntityTooLarge},
+		// Every JSON route shares decodeJSON and its limit.
+		{"payment one byte over", "/payment/pay", limit + 1, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := srv.Client().Post(srv.URL+tt.route, "application/json", strings.NewReader(paddedOrder(t, tt.size)))
+			if err != nil {
+				t.Fatalf("POST %s: %v", tt.route, err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if tt.want != http.StatusRequestEntityTooLarge {
+				return
+			}
+			var out map[string]string
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("decode error body: %v", err)
+			}
+			if !strings.Contains(out["error"], "exceeds 256 bytes") {
+				t.Errorf("error = %q, want it to name the 256-byte limit", out["error"])
+			}
+		})
+	}
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(context.Context, domoutbox.Event) error {
+	return errors.New("broker unavailable")
+}
+
+func TestCreateOrderPublishPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy appOrder.PublishPolicy
+		want   int
+	}{
+		{"best effort keeps the created order", appOrder.PublishBestEffort, http.StatusCreated},
+		{"required asks the client to retry", appOrder.PublishRequired, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := memory.NewOrderRepository()
+			h := httppresentation.NewHandler(
+				appOrder.NewCreateOrderUseCase(orders, id.NewUUIDGenerator(), failingPublisher{}, nil,
+					appOrder.WithPublishPolicy(tt.policy)),
+				appPayment.NewProcessPaymentUseCase(orders, nil),
+				nil, nil,
+			)
+			srv := httptest.NewServer(h.Router())
+			t.Cleanup(srv.Close)
+
+			resp, err := srv.Client().Post(srv.URL+"/order", "application/json", strings.NewReader(orderBody))
+			if err != nil {
+				t.Fatalf("POST /order: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateOrderValidationErrorsAre400WithFieldCode(t *testing.T) {
+	orders := memory.NewOrderRepository()
+	h := httppresentation.NewHandler(
+		appOrder.NewCreateOrderUseCase(orders, id.NewUUIDGenerator(), nil, nil),
+		appPayment.NewProcessPaymentUseCase(orders, nil),
+		nil, nil,
+	)
+	srv := httptest.NewServer(h.Router())
+	t.Cleanup(srv.Close)
+
+	tests := []struct {
+		name string
+		body string
+		code string
+	}{
+		{"missing customer", `{"product_id":"sku-1","quantity":1,"amount":100}`, "CUSTOMER_ID_REQUIRED"},
+		{"missing product", `{"customer_id":"c-1","quantity":1,"amount":100}`, "PRODUCT_ID_REQUIRED"},
+		{"zero quantity", `{"customer_id":"c-1","product_id":"sku-1","quantity":0,"amount":100}`, "QUANTITY_INVALID"},
+		{"negative amount", `{"customer_id":"c-1","product_id":"sku-1","quantity":1,"amount":-1}`, "AMOUNT_INVALID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := srv.Client().Post(srv.URL+"/order", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("POST /order: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", resp.StatusCode)
+			}
+			var out map[string]string
+			if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+				t.Fatalf("decode error body: %v", err)
+			}
+			if out["code"] != tt.code {
+				t.Errorf("code = %q, want %q (body %v)", out["code"], tt.code, out)
+			}
+		})
+	}
+}
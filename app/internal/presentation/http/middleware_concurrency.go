@@ -0,0 +1,48 @@
+package httppresentation
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+)
+
+// retryAfterSeconds is the Retry-After hint sent with load-shedding 503s.
+const retryAfterSeconds = 1
+
+// WithMaxInFlight caps concurrently handled business requests across all routes; excess
+// requests get 503 with Retry-After instead of queueing in front of the saga pipeline.
+// /health and /ready are never limited. n <= 0 disables the limit.
+func WithMaxInFlight(n int) HandlerOption {
+	return func(h *Handler) {
+		if n > 0 {
+			h.inFlight = make(chan struct{}, n)
+		}
+	}
+}
+
+// withConcurrencyLimit admits the request if a global in-flight slot is free and rejects
+// it immediately otherwise. It sits inside the metrics and access log middlewares so
+// rejections are still counted (status 503) and logged.
+func (h *Handler) withConcurrencyLimit(route string, next http.HandlerFunc) http.HandlerFunc {
+	if h.inFlight == nil {
+		return next
+	}
+	rejected := h.rejectedCounter.Bind(observability.L("route", route))
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case h.inFlight <- struct{}{}:
+		default:
+			rejected.Add(1)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "server busy"})
+			return
+		}
+		h.inFlightGauge.Add(1)
+		defer func() {
+			h.inFlightGauge.Add(-1)
+			<-h.inFlight
+		}()
+		next(w, r)
+	}
+}
@@ -3,47 +3,38 @@ package httppresentation
 
 import (
 	"net/http"
-	"time"
 
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
 	"github.com/google/uuid"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // ObservabilityMiddleware combines:
-// - W3C Trace Context extraction
-// - request-scoped logger injection (dynamic fields only)
+// - request-scoped logger injection (dynamic fields only, incl. the sampling decision)
 // - X-Request-ID generation + echo
-// - HTTP metrics (counter + histogram) with low-cardinality labels
+//
+// It must run inside withTrace: the logger takes trace_id/span_id from the server span
+// already in the request context, so every request log correlates with that span. The
+// W3C extraction happens once, in withTrace.
+//
+// HTTP metrics are recorded by the handler's withHTTPMetrics so each request is counted once.
 func ObservabilityMiddleware(
 	base observability.Logger,
 	requestID func(*http.Request) string,
 	tenantID func(*http.Request) string,
 	tel observability.Observability,
 ) func(http.Handler) http.Handler {
-	if base == nil {
-		if tel != nil {
-			base = tel.Logger()
-		} else {
-			base = observability.NopLogger()
-		}
+	if tel == nil {
+		tel = observability.NopObservability()
 	}
-	prop := otel.GetTextMapPropagator() // W3C by default
-	reqCounter := observability.NopCounter()
-	reqHistogram := observability.NopHistogram()
-	if tel != nil {
-		metrics := tel.Metrics()
-		reqCounter = metrics.Counter(observability.MHTTPRequests)
-		reqHistogram = metrics.Histogram(observability.MHTTPRequestDuration)
+	if base == nil {
+		base = observability.LoggerOf(tel)
 	}
-
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// --- Extract W3C trace context
-			ctx := prop.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			// --- Trace context of the server span withTrace started around this middleware
+			ctx := r.Context()
 			sc := trace.SpanContextFromContext(ctx)
 
 			// --- Request/Tenant IDs
@@ -69,29 +60,14 @@ func ObservabilityMiddleware(
 				fields = append(fields,
 					observability.F("trace_id", sc.TraceID().String()),
 					observability.F("span_id", sc.SpanID().String()),
+					// sampled=false: the trace was dropped by head sampling and won't be found in the backend.
+					observability.F("sampled", sc.IsSampled()),
 				)
 			}
 			reqLogger := base.With(fields...)
 			ctx = logctx.With(ctx, reqLogger)
 
-			// --- Metrics wrap to capture final status + duration
-			start := time.Now()
-			lrw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
-			next.ServeHTTP(lrw, r.WithContext(ctx))
-
-			route := routeFromContext(ctx)             // low-cardinality template you set earlier
-			statusLabel := http.StatusText(lrw.status) // or strconv.Itoa(lrw.status)
-
-			reqCounter.Add(1,
-				observability.L("method", r.Method),
-				observability.L("route", route),
-				observability.L("status", statusLabel),
-			)
-			reqHistogram.Observe(time.Since(start).Seconds(),
-				observability.L("method", r.Method),
-				observability.L("route", route),
-				observability.L("status", statusLabel),
-			)
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
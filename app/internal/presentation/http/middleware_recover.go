@@ -0,0 +1,64 @@
+package httppresentation
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// withRecover turns a panic in next into a 500 instead of letting net/http drop the
+// connection. It is the innermost middleware so the metrics, access log and server span
+// wrapping it still see the request complete with status 500. http.ErrAbortHandler is
+// re-raised, as net/http uses it to abort a response deliberately.
+func (h *Handler) withRecover(route string, next http.Handler) http.Handler {
+	panics := h.panicCounter.Bind(observability.L("route", route))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pw := &panicWriter{ResponseWriter: w}
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			panics.Add(1)
+			logctx.FromOr(r.Context(), h.log).Error("http_panic",
+				observability.F("panic", rec),
+				observability.F("stack", string(debug.Stack())),
+			)
+			span := trace.SpanFromContext(r.Context())
+			span.RecordError(fmt.Errorf("http: handler panic: %v", rec))
+			span.SetStatus(codes.Error, "PANIC")
+
+			// Headers already sent cannot be replaced; the client sees a truncated body.
+			if !pw.wroteHeader {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+			}
+		}()
+		next.ServeHTTP(pw, r)
+	})
+}
+
+// panicWriter records whether the response has started, so withRecover knows whether
+// it can still write its own status.
+type panicWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *panicWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *panicWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,29 @@
+package httppresentation
+
+import (
+	"net/http"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/health"
+)
+
+// WithReadiness serves GET /ready from state: 200 once every registered component is
+// ready, 503 before that and while shutting down. Like /health it is never limited.
+func WithReadiness(state *health.State) HandlerOption {
+	return func(h *Handler) {
+		h.health = state
+	}
+}
+
+type readinessResponse struct {
+	Ready      bool               `json:"ready"`
+	Components []health.Component `json:"components"`
+}
+
+func (h *Handler) handleReady(w http.ResponseWriter, _ *http.Request) {
+	resp := readinessResponse{Ready: h.health.Ready(), Components: h.health.Components()}
+	status := http.StatusOK
+	if !resp.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, resp)
+}
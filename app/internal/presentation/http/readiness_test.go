@@ -0,0 +1,117 @@
+package httppresentation_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appInventory "github.com/Zhima-Mochi/minishop-observability/app/internal/application/inventory"
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	appPayment "github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/health"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/id"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/outbox"
+	httppresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/http"
+	workerpresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/worker"
+)
+
+type readiness struct {
+	status     int
+	Ready      bool               `json:"ready"`
+	Components []health.Component `json:"components"`
+}
+
+func getReady(t *testing.T, srv *httptest.Server) readiness {
+	t.Helper()
+	resp, err := srv.Client().Get(srv.URL + "/ready")
+	if err != nil {
+		t.Fatalf("GET /ready: %v", err)
+	}
+	defer resp.Body.Close()
+	r := readiness{status: resp.StatusCode}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		t.Fatalf("decode /ready: %v", err)
+	}
+	return r
+}
+
+func TestReadyFollowsBusAndWorkerLifecycle(t *testing.T) {
+	state := &health.State{}
+	orders := memory.NewOrderRepository()
+	inventory := memory.NewInventoryRepository()
+	bus := outbox.NewBus(nil, nil, outbox.WithHealth(state))
+	payments := appPayment.NewProcessPaymentUseCase(orders, nil)
+	withHealth := workerpresentation.WithHealth(state)
+	inventoryWorker := appInventory.New(bus, appInventory.NewReserveInventoryUseCase(inventory, bus, nil), nil, nil, withHealth)
+	orderWorker := appOrder.New(orders, bus, bus, nil, nil, withHealth)
+	paymentWorker := appPayment.New(bus, payments, nil, withHealth)
+
+	h := httppresentation.NewHandler(
+		appOrder.NewCreateOrderUseCase(orders, id.NewUUIDGenerator(), bus, nil),
+		payments, nil, nil,
+		httppresentation.WithReadiness(state),
+	)
+	srv := httptest.NewServer(h.Router())
+	t.Cleanup(srv.Close)
+
+	before := getReady(t, srv)
+	if before.status != http.StatusServiceUnavailable || before.Ready {
+		t.Errorf("before Start: status %d ready %v, want 503 not ready", before.status, before.Ready)
+	}
+	want := []string{"bus", "inventory_worker", "order_worker", "payment_worker"}
+	if len(before.Components) != len(want) {
+		t.Fatalf("components = %+v, want %v", before.Components, want)
+	}
+	for i, c := range before.Components {
+		if c.Name != want[i] || c.Ready {
+			t.Errorf("component %d = %+v, want %s not ready", i, c, want[i])
+		}
+	}
+
+	inventoryWorker.Start()
+	orderWorker.Start()
+	paymentWorker.Start()
+	if r := getReady(t, srv); r.status != http.StatusServiceUnavailable {
+		t.Errorf("workers subscribed, bus not started: status %d, want 503", r.status)
+	}
+
+	bus.Start(context.Background())
+	after := getReady(t, srv)
+	if after.status != http.StatusOK || !after.Ready {
+		t.Errorf("after Start: status %d ready %v, want 200 ready", after.status, after.Ready)
+	}
+	for _, c := range after.Components {
+		if !c.Ready {
+			t.Errorf("after Start: %s not ready", c.Name)
+		}
+	}
+
+	bus.Stop(context.Background())
+	if r := getReady(t, srv); r.status != http.StatusServiceUnavailable {
+		t.Errorf("after Stop: status %d, want 503", r.status)
+	}
+}
+
+func TestReadyIsUnavailableWhileDraining(t *testing.T) {
+	state := &health.State{}
+	state.Set("bus", true)
+	orders := memory.NewOrderRepository()
+	h := httppresentation.NewHandler(
+		appOrder.NewCreateOrderUseCase(orders, id.NewUUIDGenerator(), nil, nil),
+		appPayment.NewProcessPaymentUseCase(orders, nil), nil, nil,
+		httppresentation.WithReadiness(state),
+	)
+	srv := httptest.NewServer(h.Router())
+	t.Cleanup(srv.Close)
+
+	if r := getReady(t, srv); r.status != http.StatusOK {
+		t.Fatalf("status %d, want 200 before shutdown", r.status)
+	}
+	state.Drain()
+	if r := getReady(t, srv); r.status != http.StatusServiceUnavailable || r.Ready {
+		t.Errorf("draining: status %d ready %v, want 503 not ready", r.status, r.Ready)
+	}
+}
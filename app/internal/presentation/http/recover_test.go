@@ -0,0 +1,85 @@
+package httppresentation_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	appPayment "github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	httppresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/http"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// panickingIDs makes every order creation panic inside the handler.
+type panickingIDs struct{}
+
+func (panickingIDs) NewID() string { panic("id generator exploded") }
+
+func TestHandlerPanicBecomesClean500(t *testing.T) {
+	spans := useTracing(t, sdktrace.AlwaysSample())
+	rec := obstest.New()
+	orders := memory.NewOrderRepository()
+	h := httppresentation.NewHandler(
+		appOrder.NewCreateOrderUseCase(orders, panickingIDs{}, nil, rec),
+		appPayment.NewProcessPaymentUseCase(orders, nil),
+		rec.Logger(), rec,
+	)
+	srv := httptest.NewServer(h.Router())
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Post(srv.URL+"/order", "application/json", strings.NewReader(orderBody))
+	if err != nil {
+		t.Fatalf("POST /order: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", resp.StatusCode)
+	}
+	var out map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode error body: %v", err)
+	}
+	if out["error"] != "internal server error" {
+		t.Errorf("body = %v, want a generic JSON error", out)
+	}
+
+	if got := rec.Count(observability.MHTTPPanics, observability.L("route", "/order")); got != 1 {
+		t.Errorf("http_panics_total{route=\"/order\"} = %v, want 1", got)
+	}
+	logged := rec.Logs("http_panic")
+	if len(logged) != 1 {
+		t.Fatalf("http_panic lines = %d, want 1", len(logged))
+	}
+	if stack, _ := logged[0].Fields["stack"].(string); !strings.Contains(stack, "panickingIDs") {
+		t.Errorf("http_panic stack does not reach the panicking call:\n%s", stack)
+	}
+	// The request logger already carries the trace, so the panic line correlates with it.
+	if logged[0].Fields["trace_id"] == nil {
+		t.Errorf("http_panic fields = %v, want the request's trace_id", logged[0].Fields)
+	}
+	if access := rec.Logs("http_access"); len(access) != 1 || access[0].Fields["status"] != http.StatusInternalServerError {
+		t.Errorf("http_access = %+v, want one line with status 500", access)
+	}
+	ended := spans.Ended()
+	if len(ended) != 1 || ended[0].Status().Code != codes.Error || len(ended[0].Events()) == 0 {
+		t.Errorf("server span = %+v, want one errored span with the panic recorded", ended)
+	}
+
+	// The server survives and keeps serving.
+	health, err := srv.Client().Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health after panic: %v", err)
+	}
+	health.Body.Close()
+	if health.StatusCode != http.StatusOK {
+		t.Errorf("GET /health after panic = %d, want 200", health.StatusCode)
+	}
+}
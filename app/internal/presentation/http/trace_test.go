@@ -0,0 +1,129 @@
+package httppresentation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	appPayment "github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/id"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/oteltrace"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	httppresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/http"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// useTracing installs a tracer provider with sampler and the production propagator for
+// the duration of t, returning the recorder of its spans.
+func useTracing(t *testing.T, sampler sdktrace.Sampler) *tracetest.SpanRecorder {
+	t.Helper()
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(spans))
+	prevTP, prevProp := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	oteltrace.SetPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTP)
+		otel.SetTextMapPropagator(prevProp)
+		_ = tp.Shutdown(context.Background())
+	})
+	return spans
+}
+
+func TestServerSpanHonorsIncomingSampledDecision(t *testing.T) {
+	// The production default, with a ratio that would drop every new trace.
+	sampler, err := oteltrace.SamplingConfig{Mode: oteltrace.SampleParentBasedRatio, Ratio: 0}.Sampler()
+	if err != nil {
+		t.Fatalf("Sampler: %v", err)
+	}
+	spans := useTracing(t, sampler)
+
+	orders := memory.NewOrderRepository()
+	h := httppresentation.NewHandler(
+		appOrder.NewCreateOrderUseCase(orders, id.NewUUIDGenerator(), nil, nil),
+		appPayment.NewProcessPaymentUseCase(orders, nil),
+		nil, nil,
+	)
+	srv := httptest.NewServer(h.Router())
+	t.Cleanup(srv.Close)
+
+	const traceID = "0af7651916cd43dd8448eb211c80319c"
+	tests := []struct {
+		name        string
+		traceparent string
+		want        int
+	}{
+		{"sampled caller", "00-" + traceID + "-b7ad6b7169203331-01", 1},
+		{"unsampled caller", "00-" + traceID + "-b7ad6b7169203331-00", 0},
+		{"new trace", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(spans.Ended())
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"/health", nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			resp, err := srv.Client().Do(req)
+			if err != nil {
+				t.Fatalf("GET /health: %v", err)
+			}
+			resp.Body.Close()
+
+			ended := spans.Ended()[before:]
+			if len(ended) != tt.want {
+				t.Fatalf("recorded %d server spans, want %d", len(ended), tt.want)
+			}
+			if tt.want == 1 && ended[0].SpanContext().TraceID().String() != traceID {
+				t.Errorf("server span trace ID = %s, want the caller's %s", ended[0].SpanContext().TraceID(), traceID)
+			}
+		})
+	}
+}
+
+func TestAccessLogCarriesTheServerSpanIDs(t *testing.T) {
+	spans := useTracing(t, sdktrace.AlwaysSample())
+	rec := obstest.New()
+	orders := memory.NewOrderRepository()
+	h := httppresentation.NewHandler(
+		appOrder.NewCreateOrderUseCase(orders, id.NewUUIDGenerator(), nil, nil),
+		appPayment.NewProcessPaymentUseCase(orders, nil),
+		rec.Logger(), nil,
+	)
+	srv := httptest.NewServer(h.Router())
+	t.Cleanup(srv.Close)
+
+	for _, traceparent := range []string{"", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"} {
+		rec.Reset()
+		before := len(spans.Ended())
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/health", nil)
+		if traceparent != "" {
+			req.Header.Set("traceparent", traceparent)
+		}
+		resp, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatalf("GET /health: %v", err)
+		}
+		resp.Body.Close()
+
+		ended := spans.Ended()[before:]
+		access := rec.Logs("http_access")
+		if len(ended) != 1 || len(access) != 1 {
+			t.Fatalf("traceparent %q: %d server spans, %d access lines; want 1 each", traceparent, len(ended), len(access))
+		}
+		// The span ID must be the server span's own, not the caller's it was extracted from.
+		sc := ended[0].SpanContext()
+		if got := access[0].Fields["trace_id"]; got != sc.TraceID().String() {
+			t.Errorf("traceparent %q: access log trace_id = %v, want %s", traceparent, got, sc.TraceID())
+		}
+		if got := access[0].Fields["span_id"]; got != sc.SpanID().String() {
+			t.Errorf("traceparent %q: access log span_id = %v, want %s", traceparent, got, sc.SpanID())
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package workerpresentation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	outcomeSuccess = "success"
+	outcomeError   = "error"
+	outcomeIgnored = "ignored"
+
+	statusOK     = "OK"
+	statusFailed = "USE_CASE_FAILED"
+)
+
+// EventConfig names an event subscription driven by ForEvent.
+type EventConfig struct {
+	Handler string // subscription name for logs, metrics and spans, e.g. "inventory.order_created"
+	Service string // owning service, e.g. observability.ServiceInventoryWorker; bound to the handler's logger and sets the stage label
+	UseCase string // use_case label, e.g. "inventory.worker.order_created"
+	Span    string // span name; defaults to "Worker." + the event name
+	SLA     *SLA   // optional handling budget; nil skips the check
+}
+
+// LogFielder is implemented by use case results that contribute fields to use_case_done.
+// LogFields must tolerate a nil receiver, since failed executions may return nil results.
+type LogFielder interface {
+	LogFields() []observability.Field
+}
+
+// EventOption customises a ForEvent subscription.
+type EventOption[E domoutbox.Event] func(*eventOptions[E])
+
+// WithEventFields adds event-specific fields (e.g. order_id) to the handler's logger,
+// so the use case and the use_case_done line both carry them.
+func WithEventFields[E domoutbox.Event](fn func(E) []observability.Field) EventOption[E] {
+	return func(o *eventOptions[E]) {
+		o.fields = fn
+	}
+}
+
+type eventOptions[E domoutbox.Event] struct {
+	fields func(E) []observability.Field
+}
+
+// ForEvent subscribes useCase to events of type E with the standard worker instrumentation:
+// a span, an event-scoped logger (WithEventContext), usecase_requests_total and
+// usecase_duration_seconds under cfg.UseCase, and a use_case_done log line. Events of
+// another type are counted as ignored. Results implementing LogFielder add their fields.
+// With cfg.SLA set, the same latency is checked against the event's budget. The returned
+// token unsubscribes the handler.
+func ForEvent[E domoutbox.Event, Out any](
+	subscriber domoutbox.Subscriber,
+	cfg EventConfig,
+	useCase application.UseCase[E, Out],
+	tel observability.Observability,
+	logger observability.Logger,
+	opts ...EventOption[E],
+) domoutbox.SubscriptionToken {
+	if useCase == nil {
+		return 0
+	}
+	var zero E
+	eventName := zero.EventName()
+	if cfg.Span == "" {
+		cfg.Span = "Worker." + eventName
+	}
+	if logger == nil {
+		logger = observability.LoggerOf(tel)
+	}
+	var o eventOptions[E]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	tracer := observability.TracerOf(tel)
+	metricsProvider := observability.MetricsOf(tel)
+	useCaseLabels := observability.UseCaseLabels(cfg.Service, cfg.UseCase)
+	reqCounter := metricsProvider.Counter(observability.MUsecaseRequests).Bind(useCaseLabels...)
+	durHistogram := metricsProvider.Histogram(observability.MUsecaseDuration).Bind(useCaseLabels...)
+
+	return subscriber.SubscribeNamed(cfg.Handler, eventName, func(ctx context.Context, e domoutbox.Event) (err error) {
+		evt, ok := e.(E)
+		if !ok {
+			reqCounter.Add(1, observability.L("outcome", outcomeIgnored))
+			return nil
+		}
+
+		ctx, span := tracer.Start(ctx, cfg.Span,
+			attribute.String("use_case", cfg.UseCase),
+			attribute.String("event", eventName),
+		)
+		start := time.Now()
+		outcome, status := outcomeSuccess, statusOK
+		var resultFields []observability.Field
+
+		sc := trace.SpanContextFromContext(ctx)
+		ctx = WithEventContext(ctx, logctx.FromOrWith(ctx, logger, observability.F(observability.FieldService, cfg.Service)), tel, sc.TraceID(), sc.SpanID(), map[string]string{
+			"use_case": cfg.UseCase,
+			"event":    eventName,
+		})
+		if o.fields != nil {
+			ctx = logctx.With(ctx, logctx.FromOr(ctx, logger).With(o.fields(evt)...))
+		}
+
+		defer func() {
+			lat := time.Since(start).Seconds()
+			reqCounter.Add(1, observability.L("outcome", outcome))
+			durHistogram.Observe(lat)
+			cfg.SLA.Check(logctx.FromOr(ctx, logger), eventName, lat)
+
+			fields := []observability.Field{
+				observability.F("outcome", outcome),
+				observability.F("status", status),
+				observability.F("latency_seconds", lat),
+			}
+			fields = append(fields, resultFields...)
+			if err != nil {
+				fields = append(fields, observability.F("error", err.Error()))
+				span.RecordError(err)
+				span.SetStatus(codes.Error, status)
+			} else {
+				span.SetStatus(codes.Ok, status)
+			}
+			span.End()
+			logctx.FromOr(ctx, logger).Info("use_case_done", fields...)
+		}()
+
+		res, execErr := useCase.Execute(ctx, evt)
+		if lf, ok := any(res).(LogFielder); ok {
+			resultFields = lf.LogFields()
+		}
+		if execErr != nil {
+			outcome, status = outcomeError, statusFailed
+			return fmt.Errorf("worker: %s: %w", cfg.UseCase, execErr)
+		}
+		return nil
+	})
+}
@@ -3,6 +3,7 @@ package workerpresentation
 import (
 	"context"
 
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability/logctx"
 	"github.com/google/uuid"
@@ -10,8 +11,9 @@ import (
 )
 
 // WithEventContext injects a request-scoped logger for background/worker executions.
-// Dynamic fields only: trace_id/span_id (if valid), event_id (generated if empty),
-// plus caller-provided low-cardinality attributes (e.g. "use_case", "event", "tenant_id").
+// Dynamic fields only: trace_id/span_id/sampled (if valid), event_id (from attrs, else
+// the bus envelope in ctx, else generated), plus caller-provided low-cardinality
+// attributes (e.g. "use_case", "event", "tenant_id").
 func WithEventContext(
 	ctx context.Context,
 	base observability.Logger,
@@ -21,17 +23,20 @@ func WithEventContext(
 	attrs map[string]string, // keep this low-cardinality: event name, tenant, shard, queue, etc.
 ) context.Context {
 	if base == nil {
-		base = tel.Logger()
+		base = observability.LoggerOf(tel)
 	}
 
 	if attrs == nil {
 		attrs = make(map[string]string)
 	}
 
-	fields := make([]observability.Field, 0, 6)
+	fields := make([]observability.Field, 0, 7)
 
 	// Prefer a stable, human-pivotable ID for the event
 	evtID := attrs["event_id"]
+	if env, ok := domoutbox.EnvelopeFrom(ctx); ok && evtID == "" {
+		evtID = env.EventID
+	}
 	if evtID == "" {
 		evtID = uuid.NewString()
 	}
@@ -44,6 +49,10 @@ func WithEventContext(
 	if spanID.IsValid() {
 		fields = append(fields, observability.F("span_id", spanID.String()))
 	}
+	// Tell readers whether the trace was exported, since trace_id is logged either way.
+	if traceID.IsValid() {
+		fields = append(fields, observability.F("sampled", trace.SpanContextFromContext(ctx).IsSampled()))
+	}
 
 	// Copy over remaining attributes (skip event_id since we already normalized it)
 	for k, v := range attrs {
@@ -0,0 +1,85 @@
+package workerpresentation
+
+import (
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/health"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+)
+
+// SLA holds per-event handling budgets. Handlers slower than their event's budget are
+// counted in worker_sla_violations_total{event} and logged as worker_sla_exceeded.
+// A nil *SLA disables the check.
+type SLA struct {
+	def        time.Duration
+	perEvent   map[string]time.Duration
+	violations observability.Counter
+}
+
+// NewSLA returns nil when neither a default nor a per-event budget is set.
+func NewSLA(def time.Duration, perEvent map[string]time.Duration, tel observability.Observability) *SLA {
+	if def <= 0 && len(perEvent) == 0 {
+		return nil
+	}
+	return &SLA{
+		def:        def,
+		perEvent:   perEvent,
+		violations: observability.MetricsOf(tel).Counter(observability.MWorkerSLAViolations),
+	}
+}
+
+// Check compares a handler latency, as measured by the worker instrumentation, against
+// the event's budget and reports whether it was exceeded.
+func (s *SLA) Check(logger observability.Logger, event string, latencySeconds float64) bool {
+	if s == nil {
+		return false
+	}
+	budget, ok := s.perEvent[event]
+	if !ok {
+		budget = s.def
+	}
+	if budget <= 0 || latencySeconds <= budget.Seconds() {
+		return false
+	}
+	s.violations.Add(1, observability.L("event", event))
+	if logger != nil {
+		logger.Warn("worker_sla_exceeded",
+			observability.F("latency_seconds", latencySeconds),
+			observability.F("sla_seconds", budget.Seconds()),
+		)
+	}
+	return true
+}
+
+// Option customises the instrumentation shared by every worker.
+type Option func(*Options)
+
+// Options is the resolved set of worker Options.
+type Options struct {
+	SLA    *SLA
+	Health *health.State
+}
+
+// WithSLA checks handler latency against sla.
+func WithSLA(sla *SLA) Option {
+	return func(o *Options) {
+		o.SLA = sla
+	}
+}
+
+// WithHealth registers the worker in state, ready once Start has subscribed its
+// handlers and not ready after Stop.
+func WithHealth(state *health.State) Option {
+	return func(o *Options) {
+		o.Health = state
+	}
+}
+
+// ApplyOptions resolves opts for a worker constructor.
+func ApplyOptions(opts ...Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
@@ -0,0 +1,68 @@
+package workerpresentation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	workerpresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/worker"
+)
+
+type slowEvent struct{}
+
+func (slowEvent) EventName() string { return "test.slow" }
+
+type fastEvent struct{}
+
+func (fastEvent) EventName() string { return "test.fast" }
+
+// sleepUseCase stands in for a handler that takes d to process each event.
+type sleepUseCase[E any] struct{ d time.Duration }
+
+func (u sleepUseCase[E]) Execute(context.Context, E) (struct{}, error) {
+	time.Sleep(u.d)
+	return struct{}{}, nil
+}
+
+func TestSlowHandlerCountsAnSLAViolation(t *testing.T) {
+	rec := obstest.New()
+	bus := outbox.NewBus(rec.Logger(), rec)
+	bus.Start(context.Background())
+	t.Cleanup(func() { bus.Stop(context.Background()) })
+
+	// A tight default budget, and a generous override the fast event stays within.
+	sla := workerpresentation.NewSLA(5*time.Millisecond, map[string]time.Duration{"test.fast": time.Second}, rec)
+	workerpresentation.ForEvent(bus, workerpresentation.EventConfig{
+		Handler: "test.slow_handler", Service: observability.ServiceInventoryWorker, UseCase: "test.slow", SLA: sla,
+	}, sleepUseCase[slowEvent]{d: 20 * time.Millisecond}, rec, nil)
+	workerpresentation.ForEvent(bus, workerpresentation.EventConfig{
+		Handler: "test.fast_handler", Service: observability.ServiceInventoryWorker, UseCase: "test.fast", SLA: sla,
+	}, sleepUseCase[fastEvent]{d: 10 * time.Millisecond}, rec, nil)
+
+	for _, e := range []domoutbox.Event{slowEvent{}, fastEvent{}} {
+		if err := bus.Publish(context.Background(), e); err != nil {
+			t.Fatalf("Publish %s: %v", e.EventName(), err)
+		}
+	}
+	if err := bus.WaitIdle(context.Background()); err != nil {
+		t.Fatalf("WaitIdle: %v", err)
+	}
+
+	if got := rec.Count(observability.MWorkerSLAViolations, observability.L("event", "test.slow")); got != 1 {
+		t.Errorf("worker_sla_violations_total{event=\"test.slow\"} = %v, want 1", got)
+	}
+	if got := rec.Count(observability.MWorkerSLAViolations, observability.L("event", "test.fast")); got != 0 {
+		t.Errorf("worker_sla_violations_total{event=\"test.fast\"} = %v, want 0 within its override", got)
+	}
+	warnings := rec.Logs("worker_sla_exceeded")
+	if len(warnings) != 1 || warnings[0].Level != "warn" {
+		t.Fatalf("worker_sla_exceeded logs = %+v, want one warning", warnings)
+	}
+	if got := warnings[0].Fields["sla_seconds"]; got != 0.005 {
+		t.Errorf("sla_seconds = %v, want 0.005", got)
+	}
+}
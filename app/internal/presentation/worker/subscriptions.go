@@ -0,0 +1,40 @@
+package workerpresentation
+
+import (
+	"sync"
+
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+)
+
+// Subscriptions retains the tokens a worker's Start received so Stop can remove its
+// handlers again, e.g. when tests build fresh workers on a shared bus.
+type Subscriptions struct {
+	mu      sync.Mutex
+	entries []subscriptionEntry
+}
+
+type subscriptionEntry struct {
+	event string
+	token domoutbox.SubscriptionToken
+}
+
+// Add records a subscription to eventName.
+func (s *Subscriptions) Add(eventName string, token domoutbox.SubscriptionToken) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, subscriptionEntry{event: eventName, token: token})
+}
+
+// UnsubscribeAll removes every recorded subscription from subscriber and forgets them.
+func (s *Subscriptions) UnsubscribeAll(subscriber domoutbox.Subscriber) {
+	s.mu.Lock()
+	entries := s.entries
+	s.entries = nil
+	s.mu.Unlock()
+	if subscriber == nil {
+		return
+	}
+	for _, e := range entries {
+		subscriber.Unsubscribe(e.event, e.token)
+	}
+}
@@ -0,0 +1,77 @@
+// Package shutdown collects the cleanup steps of long-lived components so main can run
+// them in one place, in the reverse of the order the components were started.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+)
+
+// Func releases a component's resources, giving up when ctx is done.
+type Func func(ctx context.Context) error
+
+type hook struct {
+	name string
+	fn   Func
+}
+
+// Registry runs registered hooks last-in, first-out: register a component right after
+// starting it, so whatever depends on it (and was started later) is closed first.
+// The zero value is ready to use.
+type Registry struct {
+	mu    sync.Mutex
+	hooks []hook
+	done  bool
+}
+
+// Register adds fn under name, which identifies it in shutdown logs. Hooks registered
+// after Run has started are ignored.
+func (r *Registry) Register(name string, fn Func) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done || fn == nil {
+		return
+	}
+	r.hooks = append(r.hooks, hook{name: name, fn: fn})
+}
+
+// Run calls every hook in reverse registration order with the shared grace ctx,
+// logging shutdown_hook_done or shutdown_hook_failed for each, and returns their errors
+// joined. Later hooks still run when an earlier one fails. Only the first call has effect.
+func (r *Registry) Run(ctx context.Context, logger observability.Logger) error {
+	r.mu.Lock()
+	if r.done {
+		r.mu.Unlock()
+		return nil
+	}
+	r.done = true
+	hooks := r.hooks
+	r.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		start := time.Now()
+		err := h.fn(ctx)
+		fields := []observability.Field{
+			observability.F("hook", h.name),
+			observability.F("duration_seconds", time.Since(start).Seconds()),
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shutdown: %s: %w", h.name, err))
+			if logger != nil {
+				logger.Error("shutdown_hook_failed", append(fields, observability.F("error", err))...)
+			}
+			continue
+		}
+		if logger != nil {
+			logger.Info("shutdown_hook_done", fields...)
+		}
+	}
+	return errors.Join(errs...)
+}
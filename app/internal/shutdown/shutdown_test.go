@@ -0,0 +1,66 @@
+package shutdown_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/shutdown"
+)
+
+func TestRunClosesInReverseStartOrder(t *testing.T) {
+	rec := obstest.New()
+	var r shutdown.Registry
+	var closed []string
+	register := func(name string, err error) {
+		r.Register(name, func(context.Context) error {
+			closed = append(closed, name)
+			return err
+		})
+	}
+	broken := errors.New("flush failed")
+	register("tracer_provider", nil)
+	register("event_bus", broken)
+	register("http_server", nil)
+
+	err := r.Run(context.Background(), rec.Logger())
+	// A failing hook does not stop the ones registered before it.
+	if want := []string{"http_server", "event_bus", "tracer_provider"}; !slices.Equal(closed, want) {
+		t.Errorf("closed %v, want %v", closed, want)
+	}
+	if !errors.Is(err, broken) {
+		t.Errorf("Run error = %v, want it to wrap %v", err, broken)
+	}
+
+	var done []string
+	for _, e := range rec.Logs("shutdown_hook_done") {
+		done = append(done, e.Fields["hook"].(string))
+	}
+	if want := []string{"http_server", "tracer_provider"}; !slices.Equal(done, want) {
+		t.Errorf("shutdown_hook_done hooks = %v, want %v", done, want)
+	}
+	failed := rec.Logs("shutdown_hook_failed")
+	if len(failed) != 1 || failed[0].Fields["hook"] != "event_bus" {
+		t.Errorf("shutdown_hook_failed = %+v, want one entry for event_bus", failed)
+	}
+}
+
+func TestRunHasEffectOnce(t *testing.T) {
+	var r shutdown.Registry
+	calls := 0
+	r.Register("event_bus", func(context.Context) error { calls++; return nil })
+
+	if err := r.Run(context.Background(), nil); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// Hooks registered after shutdown began would race the resources already closed.
+	r.Register("late", func(context.Context) error { t.Error("late hook ran"); return nil })
+	if err := r.Run(context.Background(), nil); err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("hook ran %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,58 @@
+package testkit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/domain/clock"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+)
+
+func TestFrozenClockMakesOrderLifecycleGolden(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fc, restore := clock.Freeze(t0)
+	t.Cleanup(restore)
+
+	ctx := context.Background()
+	h := New(t, WithPaymentSuccessRate(0))
+	h.Seed("sku-1", 5)
+	res, err := h.RunCreateOrder(ctx, appOrder.CreateOrderInput{
+		CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100,
+	})
+	if err != nil {
+		t.Fatalf("RunCreateOrder: %v", err)
+	}
+
+	// The saga settled: reserved, then declined, all at the frozen instant.
+	want := &domorder.Order{
+		ID: res.OrderID, CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100,
+		Status: domorder.StatusPaymentFailed, FailureReason: "payment_declined",
+		CreatedAt: t0, UpdatedAt: t0, Version: 2,
+	}
+	expectOrder(t, h, want.Clone())
+
+	// A later successful retry moves only UpdatedAt.
+	fc.Advance(time.Minute)
+	h.PaymentUseCase.SetSuccessRate(1)
+	if _, err := h.PaymentUseCase.Execute(ctx, payment.ProcessPaymentInput{OrderID: res.OrderID}); err != nil {
+		t.Fatalf("retry payment: %v", err)
+	}
+	want.Status, want.FailureReason = domorder.StatusCompleted, ""
+	want.UpdatedAt, want.Version = t0.Add(time.Minute), 3
+	expectOrder(t, h, want.Clone())
+}
+
+func expectOrder(t *testing.T, h *Harness, want *domorder.Order) {
+	t.Helper()
+	got, err := h.Orders.Get(context.Background(), want.ID)
+	if err != nil {
+		t.Fatalf("load order: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stored order =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -0,0 +1,40 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+)
+
+func TestPostOrderCompletesThroughRealWiring(t *testing.T) {
+	h := New(t)
+	h.PaymentUseCase.SetSuccessRate(1)
+	h.Seed("sku-1", 3)
+	srv := h.Server(t)
+
+	orderID := h.PostOrder(t, srv, appOrder.CreateOrderInput{
+		CustomerID: "c-1", ProductID: "sku-1", Quantity: 2, Amount: 500,
+	})
+
+	deadline := time.Now().Add(time.Second)
+	var o *domorder.Order
+	for {
+		var err error
+		if o, err = h.Orders.Get(context.Background(), orderID); err != nil {
+			t.Fatalf("load order %s: %v", orderID, err)
+		}
+		if o.Status == domorder.StatusCompleted || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if o.Status != domorder.StatusCompleted {
+		t.Fatalf("order %s status = %q, want %q (failure_reason=%q)", orderID, o.Status, domorder.StatusCompleted, o.FailureReason)
+	}
+	if stock, err := h.Inventory.Available(context.Background(), "sku-1"); err != nil || stock != 1 {
+		t.Errorf("remaining stock = %d (err %v), want 1", stock, err)
+	}
+}
@@ -0,0 +1,41 @@
+package testkit
+
+import (
+	"testing"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+)
+
+func TestUseCaseLogsCarryCanonicalService(t *testing.T) {
+	rec := obstest.New()
+	h := New(t, WithTelemetry(rec))
+	h.Seed("sku-1", 1)
+	srv := h.Server(t)
+
+	// The HTTP and outbox layers hand their own loggers down the context; each use case
+	// and worker must still label its lines with its own service.
+	h.PostOrder(t, srv, appOrder.CreateOrderInput{
+		CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100,
+	})
+
+	want := map[string]string{
+		"order.create":                            observability.ServiceOrder,
+		"inventory.worker.order_created":          observability.ServiceInventoryWorker,
+		"inventory.reserve":                       observability.ServiceInventory,
+		"order.worker.inventory_reserved":         observability.ServiceOrderWorker,
+		"payment.worker.order_inventory_reserved": observability.ServicePaymentWorker,
+		"payment.process":                         observability.ServicePayment,
+	}
+	got := make(map[string]any)
+	for _, e := range rec.Logs("use_case_done") {
+		useCase, _ := e.Fields["use_case"].(string)
+		got[useCase] = e.Fields[observability.FieldService]
+	}
+	for useCase, service := range want {
+		if got[useCase] != service {
+			t.Errorf("use_case_done{use_case=%q} service = %v, want %q", useCase, got[useCase], service)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package testkit
+
+import (
+	"testing"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+)
+
+func TestPostOrderEmitsREDMetrics(t *testing.T) {
+	rec := obstest.New()
+	h := New(t, WithTelemetry(rec))
+	h.Seed("sku-1", 1)
+	srv := h.Server(t)
+
+	h.PostOrder(t, srv, appOrder.CreateOrderInput{
+		CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100,
+	})
+
+	tests := []struct {
+		key    observability.MetricKey
+		labels []observability.Label
+	}{
+		{observability.MHTTPRequests, []observability.Label{
+			observability.L("route", "/order"), observability.L("status", "201"),
+		}},
+		{observability.MUsecaseRequests, []observability.Label{
+			observability.L("use_case", "order.create"), observability.L("outcome", "success"),
+		}},
+		{observability.MExternalRequests, []observability.Label{
+			observability.L("peer", "outbox"), observability.L("endpoint", "order.created"), observability.L("outcome", "success"),
+		}},
+	}
+	for _, tt := range tests {
+		if got := rec.Count(tt.key, tt.labels...); got != 1 {
+			t.Errorf("%s%v = %v, want 1 (series: %v)", tt.key, tt.labels, got, rec.Series(tt.key))
+		}
+	}
+}
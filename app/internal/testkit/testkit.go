@@ -0,0 +1,215 @@
+// Package testkit wires the full order saga in-process so integration tests can drive
+// "create order → reserve inventory → pay" without duplicating the main wiring.
+package testkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appInventory "github.com/Zhima-Mochi/minishop-observability/app/internal/application/inventory"
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	appPayment "github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/id"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
+	httppresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/http"
+)
+
+const defaultDrainTimeout = 5 * time.Second
+
+// Harness holds the wired saga components. Fields are exposed for direct assertions.
+type Harness struct {
+	Orders    *memory.OrderRepository
+	Inventory *memory.InventoryRepository
+	Bus       *outbox.Bus
+	Tel       observability.Observability
+
+	OrderUseCase     *appOrder.CreateOrderUseCase
+	PaymentUseCase   *appPayment.ProcessPaymentUseCase
+	InventoryUseCase *appInventory.ReserveInventoryUseCase
+
+	// HTTP serves the public routes over the same wiring, for end-to-end tests.
+	HTTP *httppresentation.Handler
+}
+
+type options struct {
+	tel         observability.Observability
+	successRate float64
+	async       bool
+	noEvents    bool
+}
+
+// Option customises the harness.
+type Option func(*options)
+
+// WithTelemetry injects a telemetry provider (e.g. a recording one); defaults to nop.
+func WithTelemetry(tel observability.Observability) Option {
+	return func(o *options) { o.tel = tel }
+}
+
+// WithPaymentSuccessRate sets the simulated payment success rate; defaults to 1 (always succeed).
+func WithPaymentSuccessRate(rate float64) Option {
+	return func(o *options) { o.successRate = rate }
+}
+
+// WithAsynchronousBus hands events to the bus's background dispatchers, as in
+// production, instead of the default inline dispatch. Use DrainEvents before asserting.
+func WithAsynchronousBus() Option {
+	return func(o *options) { o.async = true }
+}
+
+// WithoutEvents wires the use cases and workers with no publisher or subscriber, so
+// they fall back to application.NopPublisher and NopSubscriber: every event is dropped
+// and counted in outbox_events_dropped_total{reason="no_publisher"}, and the saga
+// never advances past the first step. Bus is still created but nothing uses it.
+func WithoutEvents() Option {
+	return func(o *options) { o.noEvents = true }
+}
+
+// New wires repositories, the bus, use cases and workers, starts the bus and
+// registers cleanup on t. The bus dispatches inline by default, so the saga has settled
+// by the time the call that published its first event returns.
+func New(t testing.TB, opts ...Option) *Harness {
+	t.Helper()
+
+	o := options{successRate: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.tel == nil {
+		o.tel = observability.NopObservability()
+	}
+
+	h := &Harness{
+		Orders:    memory.NewOrderRepository(),
+		Inventory: memory.NewInventoryRepository(),
+		Tel:       o.tel,
+	}
+	var busOpts []outbox.BusOption
+	if !o.async {
+		busOpts = append(busOpts, outbox.WithSynchronousDispatch())
+	}
+	h.Bus = outbox.NewBus(o.tel.Logger(), o.tel, busOpts...)
+	var (
+		publisher  domoutbox.Publisher  = h.Bus
+		subscriber domoutbox.Subscriber = h.Bus
+	)
+	if o.noEvents {
+		publisher, subscriber = nil, nil
+	}
+
+	h.OrderUseCase = appOrder.NewCreateOrderUseCase(h.Orders, id.NewUUIDGenerator(), publisher, o.tel)
+	h.PaymentUseCase = appPayment.NewProcessPaymentUseCase(h.Orders, o.tel)
+	h.PaymentUseCase.SetSuccessRate(o.successRate)
+	h.InventoryUseCase = appInventory.NewReserveInventoryUseCase(h.Inventory, publisher, o.tel)
+
+	h.HTTP = httppresentation.NewHandler(h.OrderUseCase, h.PaymentUseCase, o.tel.Logger(), o.tel,
+		httppresentation.WithSagaView(appOrder.NewSagaViewUseCase(h.Orders, h.Inventory, nil, o.tel)),
+		httppresentation.WithInventorySeeding(appInventory.NewSeedInventoryUseCase(h.Inventory, o.tel)),
+	)
+
+	appInventory.New(subscriber, h.InventoryUseCase, o.tel, o.tel.Logger()).Start()
+	appOrder.New(h.Orders, subscriber, publisher, o.tel, o.tel.Logger()).Start()
+	appPayment.New(subscriber, h.PaymentUseCase, o.tel).Start()
+
+	h.Bus.Start(context.Background())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultDrainTimeout)
+		defer cancel()
+		h.Bus.Stop(ctx)
+	})
+	return h
+}
+
+// StartedBus returns a started bus that dispatches inline and has no subscribers,
+// stopped on t cleanup. It suits use-case tests that only need a publisher.
+func StartedBus(t testing.TB, opts ...outbox.BusOption) *outbox.Bus {
+	t.Helper()
+	b := outbox.NewBus(nil, nil, append([]outbox.BusOption{outbox.WithSynchronousDispatch()}, opts...)...)
+	b.Start(context.Background())
+	t.Cleanup(func() { b.Stop(context.Background()) })
+	return b
+}
+
+// Seed sets the available stock for a product.
+func (h *Harness) Seed(productID string, quantity int) {
+	h.Inventory.Seed(productID, quantity)
+}
+
+// RunCreateOrder creates an order and waits for the resulting saga events to settle.
+func (h *Harness) RunCreateOrder(ctx context.Context, in appOrder.CreateOrderInput) (*appOrder.CreateOrderResult, error) {
+	res, err := h.OrderUseCase.Execute(ctx, in)
+	if err != nil {
+		return res, err
+	}
+	return res, h.DrainEvents(ctx)
+}
+
+// DrainEvents blocks until the bus has no pending events, bounded by a default timeout.
+func (h *Harness) DrainEvents(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultDrainTimeout)
+	defer cancel()
+	return h.Bus.WaitIdle(ctx)
+}
+
+// ExpectOrderStatus fails t unless the stored order has the wanted status.
+func (h *Harness) ExpectOrderStatus(t testing.TB, orderID string, want domorder.Status) {
+	t.Helper()
+	o, err := h.Orders.Get(context.Background(), orderID)
+	if err != nil {
+		t.Fatalf("load order %s: %v", orderID, err)
+	}
+	if o.Status != want {
+		t.Fatalf("order %s status = %q, want %q (failure_reason=%q)", orderID, o.Status, want, o.FailureReason)
+	}
+}
+
+// Server starts an httptest server for the harness routes, closed on t cleanup.
+func (h *Harness) Server(t testing.TB) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(h.HTTP.Router())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// PostOrder creates an order through POST /order on srv, fails t unless it is accepted,
+// waits for the saga events to settle and returns the order ID.
+func (h *Harness) PostOrder(t testing.TB, srv *httptest.Server, in appOrder.CreateOrderInput) string {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"customer_id":     in.CustomerID,
+		"idempotency_key": in.IdempotencyKey,
+		"product_id":      in.ProductID,
+		"quantity":        in.Quantity,
+		"amount":          in.Amount,
+	})
+	if err != nil {
+		t.Fatalf("encode order: %v", err)
+	}
+	resp, err := srv.Client().Post(srv.URL+"/order", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /order: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /order status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	var out struct {
+		OrderID string `json:"order_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode order response: %v", err)
+	}
+	if err := h.DrainEvents(context.Background()); err != nil {
+		t.Fatalf("drain events: %v", err)
+	}
+	return out.OrderID
+}
@@ -0,0 +1,51 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+)
+
+func TestHarnessSettlesSagaBeforeCreateReturns(t *testing.T) {
+	tests := []struct {
+		name  string
+		stock int
+		opts  []Option
+		want  domorder.Status
+	}{
+		{"in stock", 5, nil, domorder.StatusCompleted},
+		{"out of stock", 0, nil, domorder.StatusInventoryFailed},
+		{"payment declined", 5, []Option{WithPaymentSuccessRate(0)}, domorder.StatusPaymentFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(t, tt.opts...)
+			h.Seed("sku-1", tt.stock)
+
+			// No DrainEvents: the default bus dispatches inline, so the whole saga has
+			// run by the time Execute returns.
+			res, err := h.OrderUseCase.Execute(context.Background(), appOrder.CreateOrderInput{
+				CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100,
+			})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			h.ExpectOrderStatus(t, res.OrderID, tt.want)
+		})
+	}
+}
+
+func TestHarnessAsynchronousBusSettlesAfterDrain(t *testing.T) {
+	h := New(t, WithAsynchronousBus())
+	h.Seed("sku-1", 5)
+
+	res, err := h.RunCreateOrder(context.Background(), appOrder.CreateOrderInput{
+		CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100,
+	})
+	if err != nil {
+		t.Fatalf("RunCreateOrder: %v", err)
+	}
+	h.ExpectOrderStatus(t, res.OrderID, domorder.StatusCompleted)
+}
@@ -0,0 +1,89 @@
+package testkit
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/oteltrace"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/obstest"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// usePropagator installs the propagator main installs, restoring the previous one after t.
+func usePropagator(t *testing.T) {
+	t.Helper()
+	prev := otel.GetTextMapPropagator()
+	oteltrace.SetPropagator()
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+}
+
+func TestInventoryWorkerContinuesTheCreateOrderTrace(t *testing.T) {
+	usePropagator(t)
+	rec := obstest.New()
+	// Background dispatchers start from their own context, so only the envelope can
+	// carry the request's trace across the bus.
+	h := New(t, WithTelemetry(rec), WithAsynchronousBus())
+	h.Seed("sku-1", 1)
+
+	res, err := h.RunCreateOrder(context.Background(), appOrder.CreateOrderInput{
+		CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100,
+	})
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	h.ExpectOrderStatus(t, res.OrderID, domorder.StatusCompleted)
+
+	created := rec.Spans("UC.CreateOrder")
+	if len(created) != 1 {
+		t.Fatalf("UC.CreateOrder spans = %d, want 1", len(created))
+	}
+	traceID := created[0].SpanContext().TraceID()
+	for _, name := range []string{"UC.OrderCreated", "UC.OnOrderCreated"} {
+		spans := rec.Spans(name)
+		if len(spans) != 1 {
+			t.Fatalf("%s spans = %d, want 1", name, len(spans))
+		}
+		if got := spans[0].SpanContext().TraceID(); got != traceID {
+			t.Errorf("%s trace ID = %s, want the create-order trace %s", name, got, traceID)
+		}
+	}
+}
+
+func TestReservationSpanDescendsFromCreateOrderSpan(t *testing.T) {
+	usePropagator(t)
+	rec := obstest.New()
+	h := New(t, WithTelemetry(rec), WithAsynchronousBus())
+	h.Seed("sku-1", 1)
+
+	if _, err := h.RunCreateOrder(context.Background(), appOrder.CreateOrderInput{
+		CustomerID: "c-1", ProductID: "sku-1", Quantity: 1, Amount: 100,
+	}); err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	byID := make(map[trace.SpanID]sdktrace.ReadOnlySpan)
+	for _, name := range []string{"UC.CreateOrder", "bus.dispatch", "Outbox.Handler", "UC.OrderCreated", "UC.OnOrderCreated"} {
+		for _, s := range rec.Spans(name) {
+			byID[s.SpanContext().SpanID()] = s
+		}
+	}
+	reserve := rec.Spans("UC.OnOrderCreated")
+	if len(reserve) != 1 {
+		t.Fatalf("UC.OnOrderCreated spans = %d, want 1", len(reserve))
+	}
+	// Walk up from the reservation; every hop across the bus must have a recorded parent.
+	var path []string
+	for s := reserve[0]; s != nil; s = byID[s.Parent().SpanID()] {
+		path = append(path, s.Name())
+	}
+	want := []string{"UC.OnOrderCreated", "UC.OrderCreated", "Outbox.Handler", "bus.dispatch", "UC.CreateOrder"}
+	if !slices.Equal(path, want) {
+		t.Errorf("reservation span ancestry = %v, want %v", path, want)
+	}
+}
@@ -0,0 +1,21 @@
+// Package wait polls for conditions in tests. It imports nothing from the app, so
+// in-package tests of outbox and its adapters can use it without an import cycle
+// through testkit.
+package wait
+
+import (
+	"testing"
+	"time"
+)
+
+// For polls cond every millisecond until it holds, failing t after timeout.
+func For(t testing.TB, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met within %s", timeout)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
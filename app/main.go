@@ -3,51 +3,163 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	appInventory "github.com/Zhima-Mochi/minishop-observability/app/internal/application/inventory"
 	appOrder "github.com/Zhima-Mochi/minishop-observability/app/internal/application/order"
 	appPayment "github.com/Zhima-Mochi/minishop-observability/app/internal/application/payment"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/config"
+	dominventory "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/inventory"
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	domoutbox "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/health"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/id"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
 	obsprovider "github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/otellogger"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/otelmetrics"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/oteltrace"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/prometrics"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/observability/zaplogger"
 	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/outbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/outbox/fileoutbox"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/repolog"
 	coreobservability "github.com/Zhima-Mochi/minishop-observability/app/internal/observability"
 	httppresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/http"
+	workerpresentation "github.com/Zhima-Mochi/minishop-observability/app/internal/presentation/worker"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/shutdown"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// snapshotRecentEvents is how many bus events are retained for the snapshot's recent
+// error counts when DEBUG_RECENT_EVENTS does not already retain some.
+const snapshotRecentEvents = 100
+
 func main() {
-	serviceName := getenvDefault("SERVICE_NAME", "minishop")
-	env := getenvDefault("ENV", "dev")
+	cfg, err := config.Load()
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	serviceName := cfg.ServiceName
+	env := cfg.Env
 
-	baseLogger := zaplogger.New(
-		coreobservability.F("service", serviceName),
+	// W3C trace context and baggage (and optionally B3), for HTTP requests and events
+	// crossing the bus.
+	var propagatorOpts []oteltrace.PropagatorOption
+	if cfg.TracePropagateB3 {
+		propagatorOpts = append(propagatorOpts, oteltrace.WithB3())
+	}
+	oteltrace.SetPropagator(propagatorOpts...)
+
+	// Components register their cleanup as they start; it runs in reverse on shutdown.
+	var cleanup shutdown.Registry
+
+	fixedFields := []coreobservability.Field{
+		coreobservability.F(coreobservability.FieldService, serviceName),
 		coreobservability.F("env", env),
+		coreobservability.F("version", cfg.Version),
+	}
+
+	var metrics metricsRegistry
+	if cfg.MetricsBackend == "prometheus" {
+		var metricsOpts []prometrics.Option
+		if cfg.MetricsNativeHistograms {
+			metricsOpts = append(metricsOpts, prometrics.WithNativeHistograms(1.1, 160))
+		}
+		metrics = prometrics.New(serviceName, "app", metricsOpts...)
+	} else {
+		otelMetrics, err := otelmetrics.New(context.Background(), otelmetrics.Config{
+			Namespace:   serviceName,
+			Subsystem:   "app",
+			ServiceName: serviceName,
+			Version:     cfg.Version,
+			Env:         env,
+			Exporter:    cfg.MetricsBackend,
+			Interval:    cfg.MetricsExportInterval,
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		// Registered first so it runs last and pushes what shutdown itself recorded.
+		cleanup.Register("otel_meter_provider", otelMetrics.Shutdown)
+		metrics = otelMetrics
+	}
+	metrics.BuildInfo(cfg.Version, cfg.Commit)
+	// Registered before the logger so failed log writes are counted from the first line.
+	logWriteErrors := metrics.Counter(
+		string(coreobservability.MLogWriteErrors),
+		"Total number of failed log writes and syncs.",
+		"op",
+	)
+	baseLogger := zaplogger.New(
+		zaplogger.WithFields(fixedFields...),
+		zaplogger.WithWriteErrorCounter(logWriteErrors),
+		zaplogger.WithRotation(zaplogger.Rotation{
+			MaxSizeMB:  cfg.LogFileMaxSizeMB,
+			MaxBackups: cfg.LogFileMaxBackups,
+			MaxAgeDays: cfg.LogFileMaxAgeDays,
+			Compress:   cfg.LogFileCompress,
+		}),
 	)
+
+	// Optionally ship logs through the OTLP pipeline alongside stdout/file output.
+	if cfg.LogExporter == "otlp" {
+		logProvider, err := otellogger.NewProvider(context.Background(), serviceName, cfg.Version, env)
+		if err != nil {
+			baseLogger.Error("otel_log_provider_error",
+				coreobservability.F("error", err),
+			)
+		} else {
+			cleanup.Register("otel_log_provider", logProvider.Shutdown)
+			baseLogger = coreobservability.MultiLogger(baseLogger, logProvider.Logger(fixedFields...))
+		}
+	}
+	if cfg.TraceExporter == "otlp" {
+		shutdownTracing, err := oteltrace.InitProvider(context.Background(), oteltrace.Config{
+			ServiceName: serviceName,
+			Version:     cfg.Version,
+			Env:         env,
+			Endpoint:    cfg.TraceExporterEndpoint,
+			Protocol:    cfg.TraceExporterProtocol,
+			Sampling:    oteltrace.SamplingConfig{Mode: cfg.TraceSampler, Ratio: cfg.TraceSamplerRatio},
+		})
+		if err != nil {
+			baseLogger.Error("otel_trace_provider_error",
+				coreobservability.F("error", err),
+			)
+		} else {
+			// Registered before the server and bus so their last spans are flushed.
+			cleanup.Register("otel_trace_provider", shutdownTracing)
+		}
+	}
 	if syncer, ok := baseLogger.(interface{ Sync() error }); ok {
-		defer func() { _ = syncer.Sync() }()
+		cleanup.Register("logger_sync", func(context.Context) error {
+			// Sync failures are counted in log_write_errors_total by the zap core.
+			_ = syncer.Sync()
+			return nil
+		})
 	}
 
-	metrics := prometrics.New(serviceName, "app")
 	usecaseRequests := metrics.Counter(
 		string(coreobservability.MUsecaseRequests),
 		"Total number of use case invocations.",
-		"use_case", "outcome",
+		"stage", "use_case", "outcome",
 	)
 	usecaseDurations := metrics.Histogram(
 		string(coreobservability.MUsecaseDuration),
 		"Duration of use case execution in seconds.",
 		prometheus.DefBuckets,
-		"use_case",
+		"stage", "use_case",
 	)
 	httpRequests := metrics.Counter(
 		string(coreobservability.MHTTPRequests),
@@ -60,6 +172,20 @@ func main() {
 		prometheus.DefBuckets,
 		"method", "route", "status",
 	)
+	httpInFlight := metrics.Gauge(
+		string(coreobservability.MHTTPInFlight),
+		"Number of HTTP requests currently admitted by the concurrency limit.",
+	)
+	httpConcurrencyRejected := metrics.Counter(
+		string(coreobservability.MHTTPConcurrencyRejected),
+		"Total number of HTTP requests rejected with 503 because the in-flight limit was reached.",
+		"route",
+	)
+	httpPanics := metrics.Counter(
+		string(coreobservability.MHTTPPanics),
+		"Total number of HTTP handler panics recovered with a 500.",
+		"route",
+	)
 	externalRequests := metrics.Counter(
 		string(coreobservability.MExternalRequests),
 		"Total number of outbound requests made by the service.",
@@ -72,43 +198,341 @@ func main() {
 		"peer", "endpoint",
 	)
 
+	outboxHandlerDurations := metrics.Histogram(
+		string(coreobservability.MOutboxHandlerDuration),
+		"Duration of individual event handler invocations in seconds.",
+		prometheus.DefBuckets,
+		"event", "handler",
+	)
+	busHandlerWait := metrics.Histogram(
+		string(coreobservability.MBusHandlerWait),
+		"Time event handlers spent waiting for a global concurrency slot in seconds.",
+		prometheus.DefBuckets,
+		"event",
+	)
+	outboxFanoutSemaphoreWait := metrics.Histogram(
+		string(coreobservability.MOutboxFanoutWait),
+		"Time each event handler start waited for a slot in its event's fanout concurrency cap in seconds.",
+		prometheus.DefBuckets,
+		"event",
+	)
+	outboxFanoutAtCapacity := metrics.Counter(
+		string(coreobservability.MOutboxFanoutAtCapacity),
+		"Total number of event handler starts that found the fanout concurrency cap full.",
+		"event",
+	)
+
+	outboxHandlersForceCancelled := metrics.Counter(
+		string(coreobservability.MOutboxHandlersForceCancelled),
+		"Total number of event handlers cancelled because bus shutdown exceeded its deadline.",
+		"event", "handler",
+	)
+
+	outboxHandlersExhausted := metrics.Counter(
+		string(coreobservability.MOutboxHandlersExhausted),
+		"Total number of event handlers that still failed after every retry.",
+		"event", "handler",
+	)
+
+	outboxRetryBudgetExhausted := metrics.Counter(
+		string(coreobservability.MOutboxRetryBudgetExhausted),
+		"Total number of handler failures not retried because the bus retry budget was spent.",
+		"event",
+	)
+
+	outboxShutdownDrained := metrics.Counter(
+		string(coreobservability.MOutboxShutdownDrained),
+		"Total number of events dispatched while the bus drained at shutdown.",
+	)
+
+	outboxShutdownAbandoned := metrics.Counter(
+		string(coreobservability.MOutboxShutdownAbandoned),
+		"Total number of events still queued or in flight when bus shutdown gave up.",
+	)
+
+	outboxQueueDepth := metrics.Gauge(
+		string(coreobservability.MOutboxQueueDepth),
+		"Number of events buffered in the outbox bus queue.",
+	)
+	outboxHandlersInFlight := metrics.Gauge(
+		string(coreobservability.MOutboxHandlersInFlight),
+		"Number of event handler goroutines currently running.",
+	)
+
+	outboxEventsDropped := metrics.Counter(
+		string(coreobservability.MOutboxEventsDropped),
+		"Total number of events dropped by the outbox bus.",
+		"event", "reason",
+	)
+	outboxEventsRejected := metrics.Counter(
+		string(coreobservability.MOutboxEventsRejected),
+		"Total number of events a durable outbox refused to publish, by reason (too_large).",
+		"event", "reason",
+	)
+
+	payments := metrics.Counter(
+		string(coreobservability.MPayments),
+		"Total number of payment attempts by result (success, declined, error).",
+		"result",
+	)
+
+	inventoryLowStock := metrics.Counter(
+		string(coreobservability.MInventoryLowStock),
+		"Total number of reservations that left a product below its low-stock threshold.",
+		"product",
+	)
+
+	inventoryReservationsExpired := metrics.Counter(
+		string(coreobservability.MInventoryReservationsExpired),
+		"Total number of inventory reservations released because their order did not settle within the TTL.",
+	)
+	inventoryRemainingStock := metrics.Gauge(
+		string(coreobservability.MInventoryRemainingStock),
+		"Stock left after the latest reservation, for allow-listed products.",
+		"product",
+	)
+	inventoryProductsSeeded := metrics.Gauge(
+		string(coreobservability.MInventoryProductsSeeded),
+		"Number of distinct products stocked after the last bulk seed.",
+	)
+
+	inventoryInvariantViolations := metrics.Counter(
+		string(coreobservability.MInventoryInvariant),
+		"Total number of inventory operations rejected because they would break a stock invariant.",
+		"operation",
+	)
+
+	orderTransitions := metrics.Counter(
+		string(coreobservability.MOrderTransitions),
+		"Total number of stored order status changes by from and to status.",
+		"from", "to",
+	)
+
+	workerSLAViolations := metrics.Counter(
+		string(coreobservability.MWorkerSLAViolations),
+		"Total number of worker event handlings that exceeded their SLA.",
+		"event",
+	)
+
 	tel := obsprovider.New(
 		oteltrace.New(serviceName),
 		baseLogger,
 		map[coreobservability.MetricKey]coreobservability.Counter{
-			coreobservability.MUsecaseRequests:  usecaseRequests,
-			coreobservability.MHTTPRequests:     httpRequests,
-			coreobservability.MExternalRequests: externalRequests,
+			coreobservability.MUsecaseRequests:              usecaseRequests,
+			coreobservability.MHTTPRequests:                 httpRequests,
+			coreobservability.MHTTPConcurrencyRejected:      httpConcurrencyRejected,
+			coreobservability.MHTTPPanics:                   httpPanics,
+			coreobservability.MExternalRequests:             externalRequests,
+			coreobservability.MOutboxEventsDropped:          outboxEventsDropped,
+			coreobservability.MOutboxEventsRejected:         outboxEventsRejected,
+			coreobservability.MOutboxFanoutAtCapacity:       outboxFanoutAtCapacity,
+			coreobservability.MPayments:                     payments,
+			coreobservability.MOutboxHandlersForceCancelled: outboxHandlersForceCancelled,
+			coreobservability.MOutboxHandlersExhausted:      outboxHandlersExhausted,
+			coreobservability.MOutboxRetryBudgetExhausted:   outboxRetryBudgetExhausted,
+			coreobservability.MInventoryLowStock:            inventoryLowStock,
+			coreobservability.MInventoryInvariant:           inventoryInvariantViolations,
+			coreobservability.MLogWriteErrors:               logWriteErrors,
+			coreobservability.MInventoryReservationsExpired: inventoryReservationsExpired,
+			coreobservability.MOutboxShutdownDrained:        outboxShutdownDrained,
+			coreobservability.MOutboxShutdownAbandoned:      outboxShutdownAbandoned,
+			coreobservability.MWorkerSLAViolations:          workerSLAViolations,
+			coreobservability.MOrderTransitions:             orderTransitions,
 		},
 		map[coreobservability.MetricKey]coreobservability.Histogram{
 			coreobservability.MUsecaseDuration:         usecaseDurations,
 			coreobservability.MHTTPRequestDuration:     httpDurations,
 			coreobservability.MExternalRequestDuration: externalDurations,
+			coreobservability.MOutboxHandlerDuration:   outboxHandlerDurations,
+			coreobservability.MBusHandlerWait:          busHandlerWait,
+			coreobservability.MOutboxFanoutWait:        outboxFanoutSemaphoreWait,
+		},
+		map[coreobservability.MetricKey]coreobservability.Gauge{
+			coreobservability.MHTTPInFlight:            httpInFlight,
+			coreobservability.MInventoryProductsSeeded: inventoryProductsSeeded,
+			coreobservability.MInventoryRemainingStock: inventoryRemainingStock,
+			coreobservability.MOutboxQueueDepth:        outboxQueueDepth,
+			coreobservability.MOutboxHandlersInFlight:  outboxHandlersInFlight,
 		},
+		obsprovider.WithCatalog(metrics),
 	)
 
 	orderRepo := memory.NewOrderRepository()
 	inventoryRepo := memory.NewInventoryRepository()
 	idGenerator := id.NewUUIDGenerator()
 
+	seedUseCase := appInventory.NewSeedInventoryUseCase(inventoryRepo, tel)
+	if cfg.InventorySeedFile != "" {
+		stock, err := appInventory.ReadSeedFile(cfg.InventorySeedFile)
+		if err == nil {
+			_, err = seedUseCase.Execute(context.Background(), appInventory.SeedInventoryInput{Stock: stock})
+		}
+		if err != nil {
+			baseLogger.Error("inventory_seed_file_error",
+				coreobservability.F("path", cfg.InventorySeedFile),
+				coreobservability.F("error", err),
+			)
+			os.Exit(1)
+		}
+	}
+
+	// Readiness of the bus and workers, served on /ready.
+	readiness := new(health.State)
+
+	// The snapshot's recent error counts come from the events the bus retains.
+	recentEvents := cfg.DebugRecentEvents
+	if cfg.DebugSnapshot && recentEvents == 0 {
+		recentEvents = snapshotRecentEvents
+	}
+
 	// In-memory event bus (acts as outbox/event publisher for demo)
-	bus := outbox.NewBus(baseLogger, tel)
+	busOpts := []outbox.BusOption{
+		outbox.WithHealth(readiness),
+		outbox.WithDispatchers(cfg.OutboxDispatchers),
+		outbox.WithEventConcurrency(cfg.OutboxEventConcurrency),
+		outbox.WithGlobalConcurrency(cfg.OutboxGlobalConcurrency),
+		outbox.WithRequiredEvents(cfg.OutboxRequiredEvents...),
+		outbox.WithKnownEvents(knownEvents()...),
+		outbox.WithRecentEvents(recentEvents),
+		outbox.WithRetryPolicy(outbox.RetryPolicy{
+			MaxAttempts: cfg.OutboxRetryAttempts,
+			BaseDelay:   cfg.OutboxRetryBaseDelay,
+			MaxDelay:    cfg.OutboxRetryMaxDelay,
+			Jitter:      outbox.DefaultRetryPolicy.Jitter,
+		}),
+		outbox.WithRetryBudget(float64(cfg.OutboxRetryBudget), cfg.OutboxRetryBudget),
+		outbox.WithHandlerTimeout(cfg.OutboxHandlerTimeout),
+	}
+	if cfg.OutboxStrictEvents {
+		busOpts = append(busOpts, outbox.WithStrictEvents())
+	}
+	if cfg.OutboxNonBlockingPublish {
+		busOpts = append(busOpts, outbox.WithNonBlockingPublish())
+	}
+	if cfg.OutboxSequentialFanout {
+		busOpts = append(busOpts, outbox.WithSequentialFanout())
+	}
+	bus := outbox.NewBus(baseLogger, tel, busOpts...)
+	bus.Use(outbox.Recover())
+	var deadLetters *outbox.MemoryDeadLetters
+	if cfg.OutboxDeadLetters > 0 {
+		deadLetters = outbox.NewMemoryDeadLetters(cfg.OutboxDeadLetters)
+		bus.SetDeadLetterHandler(deadLetters.Handle)
+	}
 	bus.Start(context.Background())
-	defer bus.Stop(context.Background())
+	cleanup.Register("outbox_bus", func(ctx context.Context) error {
+		drained, remaining, err := bus.Stop(ctx)
+		fields := []coreobservability.Field{
+			coreobservability.F("drained", drained),
+			coreobservability.F("remaining", remaining),
+		}
+		if err != nil {
+			baseLogger.Error("event_bus_shutdown_incomplete", append(fields, coreobservability.F("error", err))...)
+			return err
+		}
+		baseLogger.Info("event_bus_shutdown_complete", fields...)
+		return nil
+	})
+
+	// Use cases and workers exchange events through the bus unless OUTBOX_FILE selects
+	// the durable file outbox. It starts after the workers subscribe, so events left
+	// unacknowledged by the previous run are redelivered to all of them. Its close is
+	// registered here, before the relay and the sweeper that publish into it, so it
+	// closes after they stop.
+	var (
+		publisher  domoutbox.Publisher  = bus
+		subscriber domoutbox.Subscriber = bus
+		fileOutbox *fileoutbox.Outbox
+	)
+	if cfg.OutboxFile != "" {
+		fileOutbox, err = fileoutbox.Open(cfg.OutboxFile, fileOutboxRegistry(), baseLogger,
+			fileoutbox.WithObservability(tel),
+			fileoutbox.WithMaxEventBytes(cfg.OutboxMaxEventBytes),
+		)
+		if err != nil {
+			baseLogger.Error("file_outbox_open_error",
+				coreobservability.F("path", cfg.OutboxFile),
+				coreobservability.F("error", err),
+			)
+			os.Exit(1)
+		}
+		publisher, subscriber = fileOutbox, fileOutbox
+		cleanup.Register("file_outbox", fileOutbox.Close)
+	}
 
 	// Order use case publishes events instead of mutating other contexts directly
-	orderUseCase := appOrder.NewCreateOrderUseCase(orderRepo, idGenerator, bus, tel)
-	paymentUseCase := appPayment.NewProcessPaymentUseCase(orderRepo, tel)
+	var orderOpts []appOrder.Option
+	if cfg.OrderPublishPolicy == "required" {
+		orderOpts = append(orderOpts, appOrder.WithPublishPolicy(appOrder.PublishRequired))
+	}
+	if cfg.OutboxTransactional {
+		outboxStore := memory.NewOutboxStore()
+		orderOpts = append(orderOpts, appOrder.WithUnitOfWork(memory.NewOrderUnitOfWork(orderRepo, outboxStore)))
+
+		relayCtx, stopRelay := context.WithCancel(context.Background())
+		relayDone := make(chan struct{})
+		go func() {
+			defer close(relayDone)
+			outbox.NewRelay(outboxStore, publisher, baseLogger).Run(relayCtx)
+		}()
+		// Registered after the publisher (bus or file outbox), so it stops first and its
+		// final pass can still publish.
+		cleanup.Register("outbox_relay", func(ctx context.Context) error {
+			stopRelay()
+			select {
+			case <-relayDone:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+	// Use cases and workers see the logging decorator; wiring that needs the memory
+	// implementation itself (unit of work, seeding, reservations) keeps the raw repo.
+	loggedOrders := repolog.NewOrderRepository(orderRepo, tel)
+	orderUseCase := appOrder.NewCreateOrderUseCase(loggedOrders, idGenerator, publisher, tel, orderOpts...)
+	paymentUseCase := appPayment.NewProcessPaymentUseCase(loggedOrders, tel)
 
-	inventoryUseCase := appInventory.NewReserveInventoryUseCase(inventoryRepo, bus, tel)
-	inventoryWorker := appInventory.New(bus, inventoryUseCase, tel, baseLogger)
-	orderWorker := appOrder.New(orderRepo, bus, bus, tel, baseLogger)
-	paymentWorker := appPayment.New(bus, paymentUseCase, tel)
+	inventoryOpts := []appInventory.Option{
+		appInventory.WithLowStockThreshold(cfg.InventoryLowStockThreshold, cfg.InventoryLowStockThresholds),
+		appInventory.WithRemainingStockGauge(cfg.InventoryStockGaugeProducts...),
+	}
+	if cfg.InventoryAutoProvision {
+		inventoryOpts = append(inventoryOpts, appInventory.WithAutoProvision(inventoryRepo, cfg.InventoryAutoProvisionQuantity))
+	}
+	if cfg.InventoryReservationTTL > 0 {
+		inventoryOpts = append(inventoryOpts, appInventory.WithReservationTracking(inventoryRepo))
+
+		sweeper := appInventory.NewReservationSweeper(inventoryRepo, orderSettled(orderRepo), publisher, cfg.InventoryReservationTTL, tel)
+		sweepCtx, stopSweep := context.WithCancel(context.Background())
+		go sweeper.Run(sweepCtx)
+		cleanup.Register("inventory_reservation_sweeper", func(context.Context) error {
+			stopSweep()
+			return nil
+		})
+	}
+	inventoryUseCase := appInventory.NewReserveInventoryUseCase(repolog.NewInventoryRepository(inventoryRepo, tel), publisher, tel, inventoryOpts...)
+	workerSLA := workerpresentation.WithSLA(workerpresentation.NewSLA(cfg.WorkerSLA, cfg.WorkerEventSLA, tel))
+	workerHealth := workerpresentation.WithHealth(readiness)
+	inventoryWorker := appInventory.New(subscriber, inventoryUseCase, tel, baseLogger, workerSLA, workerHealth)
+	orderWorker := appOrder.New(loggedOrders, subscriber, publisher, tel, baseLogger, workerSLA, workerHealth)
+	paymentWorker := appPayment.New(subscriber, paymentUseCase, tel, workerSLA, workerHealth)
 
 	inventoryWorker.Start()
 	orderWorker.Start()
 	paymentWorker.Start()
-	handler := httppresentation.NewHandler(orderUseCase, paymentUseCase, baseLogger, tel)
+	if fileOutbox != nil {
+		fileOutbox.Start(context.Background())
+	}
+	sagaUseCase := appOrder.NewSagaViewUseCase(loggedOrders, inventoryRepo, inventoryRepo, tel)
+	handler := httppresentation.NewHandler(orderUseCase, paymentUseCase, baseLogger, tel,
+		httppresentation.WithSagaView(sagaUseCase),
+		httppresentation.WithInventorySeeding(seedUseCase),
+		httppresentation.WithMaxInFlight(cfg.HTTPMaxInFlight),
+		httppresentation.WithMaxBodyBytes(int64(cfg.HTTPMaxBodyBytes)),
+		httppresentation.WithDebugTenants(cfg.TraceDebugTenants...),
+		httppresentation.WithReadiness(readiness),
+	)
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 	mux.Handle("/", handler.Router())
@@ -118,10 +542,47 @@ func main() {
 		Handler: mux,
 	}
 
+	// The /debug endpoints expose internal state, so they only listen on the operator
+	// address; an empty DEBUG_ADDR disables them.
+	debugMux := http.NewServeMux()
+	debugMux.Handle("/debug/metrics/catalog", httppresentation.MetricsCatalogHandler(func() []coreobservability.Instrument {
+		return coreobservability.CatalogOf(tel)
+	}))
+	if cfg.DebugRecentEvents > 0 {
+		debugMux.Handle("/debug/events/recent", httppresentation.RecentEventsHandler(bus.Recent))
+	}
+	if cfg.DebugSnapshot {
+		snapshot := map[string]func() any{
+			"bus":              func() any { return bus.Stats() },
+			"orders_by_status": func() any { return orderRepo.CountByStatus() },
+			"inventory":        func() any { return map[string]int{"products": inventoryRepo.Products()} },
+			"recent_errors":    func() any { return map[string]any{"handlers": bus.Stats().HandlerErrors} },
+		}
+		if deadLetters != nil {
+			snapshot["dead_letters"] = func() any { return deadLetters.DeadLetters() }
+		}
+		debugMux.Handle("/debug/snapshot", httppresentation.SnapshotHandler(snapshot))
+	}
+	var debugServer *http.Server
+	if cfg.DebugAddr != "" {
+		debugServer = &http.Server{
+			Addr:    cfg.DebugAddr,
+			Handler: debugMux,
+		}
+	}
+
 	systemLogger := tel.Logger().With(
-		coreobservability.F("component", "system"),
+		coreobservability.F(coreobservability.FieldComponent, coreobservability.ComponentSystem),
 	)
 
+	// Only the bus tracks required and known subscriptions.
+	if err := bus.CheckSubscriptions(); fileOutbox == nil && err != nil {
+		systemLogger.Error("event_bus_wiring_error",
+			coreobservability.F("error", err),
+		)
+		os.Exit(1)
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
@@ -137,23 +598,91 @@ func main() {
 		}
 	}()
 
+	cleanup.Register("http_server", func(ctx context.Context) error {
+		if err := server.Shutdown(ctx); err != nil {
+			return err
+		}
+		systemLogger.Info("http_server_stopped")
+		return nil
+	})
+
+	if debugServer != nil {
+		go func() {
+			systemLogger.Info("debug_server_start",
+				coreobservability.F("addr", debugServer.Addr),
+			)
+			err := debugServer.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				systemLogger.Error("debug_server_error",
+					coreobservability.F("error", err),
+				)
+			}
+		}()
+		cleanup.Register("debug_server", debugServer.Shutdown)
+	}
+
 	<-ctx.Done()
+	readiness.Drain()
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// One grace period covers every hook; each failure is already logged by Run.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownGrace)
 	defer cancel()
+	_ = cleanup.Run(shutdownCtx, systemLogger)
+}
 
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		systemLogger.Error("http_server_shutdown_error",
-			coreobservability.F("error", err),
-		)
-	} else {
-		systemLogger.Info("http_server_stopped")
+// knownEvents lists every event name the application publishes, derived from the event
+// types so renames stay in sync with the bus schema guard.
+func knownEvents() []string {
+	events := []domoutbox.Event{
+		domorder.OrderCreatedEvent{},
+		domorder.OrderInventoryReservedEvent{},
+		domorder.OrderInventoryReservationFailedEvent{},
+		dominventory.InventoryReservedEvent{},
+		dominventory.InventoryReservationFailedEvent{},
+		dominventory.InventoryLowStockEvent{},
+		dominventory.InventoryReservationExpiredEvent{},
 	}
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = e.EventName()
+	}
+	return names
+}
+
+// fileOutboxRegistry makes every event in knownEvents decodable from the file outbox.
+func fileOutboxRegistry() *fileoutbox.Registry {
+	r := fileoutbox.NewRegistry()
+	fileoutbox.Register[domorder.OrderCreatedEvent](r)
+	fileoutbox.Register[domorder.OrderInventoryReservedEvent](r)
+	fileoutbox.Register[domorder.OrderInventoryReservationFailedEvent](r)
+	fileoutbox.Register[dominventory.InventoryReservedEvent](r)
+	fileoutbox.Register[dominventory.InventoryReservationFailedEvent](r)
+	fileoutbox.Register[dominventory.InventoryLowStockEvent](r)
+	fileoutbox.Register[dominventory.InventoryReservationExpiredEvent](r)
+	return r
 }
 
-func getenvDefault(key, def string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
+// metricsRegistry is the part of prometrics.Registry and otelmetrics.Registry main uses
+// to register instruments, so METRICS_BACKEND can pick either.
+type metricsRegistry interface {
+	Counter(name string, help string, labelKeys ...string) coreobservability.Counter
+	Histogram(name string, help string, buckets []float64, labelKeys ...string) coreobservability.Histogram
+	Gauge(name string, help string, labelKeys ...string) coreobservability.Gauge
+	BuildInfo(version, commit string)
+	coreobservability.Cataloger
+}
+
+// orderSettled reports an order as settled once its saga reached a terminal status;
+// only reservations of orders still in flight expire.
+func orderSettled(orders domorder.Repository) appInventory.SettledFunc {
+	return func(ctx context.Context, orderID string) (bool, error) {
+		o, err := orders.Get(ctx, orderID)
+		if errors.Is(err, domorder.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return o.Status.Terminal(), nil
 	}
-	return def
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	domorder "github.com/Zhima-Mochi/minishop-observability/app/internal/domain/order"
+	"github.com/Zhima-Mochi/minishop-observability/app/internal/infrastructure/memory"
+)
+
+func TestOrderSettledTreatsEveryTerminalStatusAsSettled(t *testing.T) {
+	tests := []struct {
+		name    string
+		advance func(*domorder.Order) error
+		want    bool
+	}{
+		{"pending", func(*domorder.Order) error { return nil }, false},
+		{"inventory reserved", (*domorder.Order).InventoryReserved, false},
+		{"inventory failed", func(o *domorder.Order) error { return o.InventoryReservationFailed("out of stock") }, true},
+		{"completed", func(o *domorder.Order) error {
+			if err := o.InventoryReserved(); err != nil {
+				return err
+			}
+			return o.PaymentSucceeded()
+		}, true},
+		{"payment failed", func(o *domorder.Order) error {
+			if err := o.InventoryReserved(); err != nil {
+				return err
+			}
+			return o.PaymentFailed("declined")
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := memory.NewOrderRepository()
+			o, err := domorder.New("o-1", "c-1", "sku-1", "", 1, 100)
+			if err != nil {
+				t.Fatalf("new order: %v", err)
+			}
+			if err := tt.advance(o); err != nil {
+				t.Fatalf("advance order: %v", err)
+			}
+			if err := orders.Insert(context.Background(), o); err != nil {
+				t.Fatalf("insert order: %v", err)
+			}
+			got, err := orderSettled(orders)(context.Background(), "o-1")
+			if err != nil {
+				t.Fatalf("settled: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("settled(%s) = %v, want %v", o.Status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderSettledUnknownOrderIsNotSettled(t *testing.T) {
+	got, err := orderSettled(memory.NewOrderRepository())(context.Background(), "missing")
+	if err != nil || got {
+		t.Errorf("settled(missing) = %v, %v; want false, nil", got, err)
+	}
+}
+
+func TestFileOutboxRegistryCoversKnownEvents(t *testing.T) {
+	r := fileOutboxRegistry()
+	for _, name := range knownEvents() {
+		if !r.Known(name) {
+			t.Errorf("file outbox cannot decode %s", name)
+		}
+	}
+}